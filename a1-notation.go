@@ -0,0 +1,342 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// 列の上限（ZZZ 列）
+const maxA1Column = 26 + 26*26 + 26*26*26
+
+var (
+	// R1C1 表記の範囲（R1C1, R2C3:R10C5 など。相対参照 R[1]C[1] は扱わない）
+	r1c1RangePattern = regexp.MustCompile(`^(?i)R([0-9]+)C([0-9]+)(?::R([0-9]+)C([0-9]+))?$`)
+	// セル参照と紛らわしい名前（A1 形式・R1C1 形式）
+	cellReferencePattern = regexp.MustCompile(`^(?i:[A-Z]{1,3}[0-9]+|R[0-9]*C[0-9]*)$`)
+	// 引用符なしで範囲の指定に使えるシート名
+	plainSheetNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// A1 表記の範囲
+// 行・列は 1-based で、省略された端は 0（"A:C" は行が、"2:5" は列が省略されている）
+// sheet はシート名の指定がある場合のみ設定される
+type a1Range struct {
+	sheet    string
+	startCol int64
+	startRow int64
+	endCol   int64
+	endRow   int64
+}
+
+// 1つのセルを表す範囲
+func a1Cell(col, row int64) a1Range {
+	return a1Range{startCol: col, startRow: row, endCol: col, endRow: row}
+}
+
+// A1 表記または R1C1 表記の範囲を解析する
+// 対応する形式: "A1", "a1", "$A$1", "A1:C10", "A:C", "2:5", "A2:C", "'My Sheet'!A1:B2", "Sheet1!R1C1:R5C3"
+func parseA1Range(rangeStr string) (a1Range, error) {
+	var r a1Range
+	ref := strings.TrimSpace(rangeStr)
+	if ref == "" {
+		return r, fmt.Errorf("range must be specified")
+	}
+
+	// シート名を取り出す
+	sheet, ref, err := splitSheetPrefix(ref)
+	if err != nil {
+		return r, fmt.Errorf("invalid range: %s: %w", rangeStr, err)
+	}
+	r.sheet = sheet
+
+	// R1C1 表記
+	if m := r1c1RangePattern.FindStringSubmatch(ref); m != nil {
+		bounds := make([]int64, 4)
+		for i, part := range m[1:] {
+			if part == "" {
+				continue
+			}
+			value, err := strconv.ParseInt(part, 10, 64)
+			if err != nil || value <= 0 {
+				return r, fmt.Errorf("invalid range: %s", rangeStr)
+			}
+			bounds[i] = value
+		}
+		r.startRow, r.startCol = bounds[0], bounds[1]
+		r.endRow, r.endCol = bounds[0], bounds[1]
+		if m[3] != "" {
+			r.endRow, r.endCol = bounds[2], bounds[3]
+		}
+		if r.startCol > maxA1Column || r.endCol > maxA1Column {
+			return r, fmt.Errorf("invalid range: %s: column is out of range", rangeStr)
+		}
+		return r.normalized(), nil
+	}
+
+	// A1 表記
+	start, end, hasEnd := strings.Cut(ref, ":")
+	r.startCol, r.startRow, err = parseA1CellRef(start)
+	if err != nil {
+		return r, fmt.Errorf("invalid range: %s", rangeStr)
+	}
+	if !hasEnd {
+		// 列だけ・行だけの単独指定（"A", "5"）は範囲として扱わない
+		if r.startCol == 0 || r.startRow == 0 {
+			return r, fmt.Errorf("invalid range: %s. Use 'A:A' for a whole column or '5:5' for a whole row", rangeStr)
+		}
+		r.endCol, r.endRow = r.startCol, r.startRow
+		return r, nil
+	}
+	r.endCol, r.endRow, err = parseA1CellRef(end)
+	if err != nil {
+		return r, fmt.Errorf("invalid range: %s", rangeStr)
+	}
+	// "A:5" のように列のみと行のみを組み合わせた範囲は不正
+	if (r.startRow == 0 && r.startCol > 0 && r.endCol == 0) || (r.startCol == 0 && r.startRow > 0 && r.endRow == 0) {
+		return r, fmt.Errorf("invalid range: %s", rangeStr)
+	}
+	return r.normalized(), nil
+}
+
+// 先頭のシート名（'My Sheet'! や Sheet1!）を取り出す
+func splitSheetPrefix(ref string) (string, string, error) {
+	if strings.HasPrefix(ref, "'") {
+		// 引用符で囲まれたシート名（'' は ' を表す）
+		var name strings.Builder
+		for i := 1; i < len(ref); i++ {
+			if ref[i] != '\'' {
+				name.WriteByte(ref[i])
+				continue
+			}
+			if i+1 < len(ref) && ref[i+1] == '\'' {
+				name.WriteByte('\'')
+				i++
+				continue
+			}
+			if i+1 >= len(ref) || ref[i+1] != '!' {
+				return "", "", fmt.Errorf("a quoted sheet name must be followed by '!'")
+			}
+			if name.Len() == 0 {
+				return "", "", fmt.Errorf("sheet name is empty")
+			}
+			return name.String(), ref[i+2:], nil
+		}
+		return "", "", fmt.Errorf("unterminated quoted sheet name")
+	}
+	sheet, rest, found := strings.Cut(ref, "!")
+	if !found {
+		return "", ref, nil
+	}
+	if sheet == "" {
+		return "", "", fmt.Errorf("sheet name is empty")
+	}
+	return sheet, rest, nil
+}
+
+// A1 表記のセル参照（A1, $A$1, a1, A, 1 など）を列番号と行番号に分解する（省略された部分は 0）
+func parseA1CellRef(ref string) (int64, int64, error) {
+	i := 0
+	if i < len(ref) && ref[i] == '$' {
+		i++
+	}
+	letters := i
+	for i < len(ref) && isASCIILetter(ref[i]) {
+		i++
+	}
+	col, err := parseColumnLetters(ref[letters:i])
+	if err != nil {
+		return 0, 0, err
+	}
+	// 行の前の "$"（"$1" のような行だけの絶対参照では列の前の "$" と同じ）
+	if i < len(ref) && ref[i] == '$' {
+		if col == 0 {
+			return 0, 0, fmt.Errorf("invalid cell reference: %s", ref)
+		}
+		i++
+	}
+	var row int64
+	if i < len(ref) {
+		for _, c := range ref[i:] {
+			if c < '0' || c > '9' {
+				return 0, 0, fmt.Errorf("invalid cell reference: %s", ref)
+			}
+		}
+		row, err = strconv.ParseInt(ref[i:], 10, 64)
+		if err != nil || row <= 0 {
+			return 0, 0, fmt.Errorf("invalid cell reference: %s", ref)
+		}
+	}
+	if col == 0 && row == 0 {
+		return 0, 0, fmt.Errorf("invalid cell reference: %s", ref)
+	}
+	return col, row, nil
+}
+
+// 列文字（A, z, AB など）を列番号（1-based）に変換する（空文字列は 0）
+func parseColumnLetters(letters string) (int64, error) {
+	if len(letters) > 3 {
+		return 0, fmt.Errorf("invalid column: %s", letters)
+	}
+	var col int64
+	for i := 0; i < len(letters); i++ {
+		c := letters[i]
+		if !isASCIILetter(c) {
+			return 0, fmt.Errorf("invalid column: %s", letters)
+		}
+		col = col*26 + int64(c|0x20-'a'+1)
+	}
+	if col > maxA1Column {
+		return 0, fmt.Errorf("invalid column: %s", letters)
+	}
+	return col, nil
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+// 開始と終了が逆になっている範囲（"C3:A1" など）を並べ直す
+func (r a1Range) normalized() a1Range {
+	if r.startCol > 0 && r.endCol > 0 && r.startCol > r.endCol {
+		r.startCol, r.endCol = r.endCol, r.startCol
+	}
+	if r.startRow > 0 && r.endRow > 0 && r.startRow > r.endRow {
+		r.startRow, r.endRow = r.endRow, r.startRow
+	}
+	return r
+}
+
+// 範囲の左上のセル（省略されている場合は 1 行目・A 列）
+func (r a1Range) start() (int64, int64) {
+	return max(r.startCol, 1), max(r.startRow, 1)
+}
+
+// シート名を除いた範囲
+func (r a1Range) withoutSheet() a1Range {
+	r.sheet = ""
+	return r
+}
+
+// A1 表記の文字列に変換する（シート名がある場合は Sheet1!A1 の形式、シート全体の場合は範囲部分が空）
+func (r a1Range) String() string {
+	cells := r.cellsString()
+	if r.sheet == "" {
+		return cells
+	}
+	return sheetRange(r.sheet, cells)
+}
+
+// シート名を範囲の指定に使える形式にする
+// 空白や記号を含む名前、セル参照と紛らわしい名前（"Q1 2024", "A1", "Bob's" など）は引用符で囲み、名前中の引用符は2つ重ねる
+func quoteSheetName(sheetName string) string {
+	if plainSheetNamePattern.MatchString(sheetName) && !cellReferencePattern.MatchString(sheetName) {
+		return sheetName
+	}
+	return "'" + strings.ReplaceAll(sheetName, "'", "''") + "'"
+}
+
+// シート名と範囲を結合して範囲の指定を作成する（範囲が空の場合はシート全体）
+func sheetRange(sheetName, rangeStr string) string {
+	if rangeStr == "" {
+		return quoteSheetName(sheetName)
+	}
+	return quoteSheetName(sheetName) + "!" + rangeStr
+}
+
+// シート名を除いた範囲部分の文字列
+func (r a1Range) cellsString() string {
+	ref := func(col, row int64) string {
+		var s string
+		if col > 0 {
+			s = columnIndexToLetter(col)
+		}
+		if row > 0 {
+			s += strconv.FormatInt(row, 10)
+		}
+		return s
+	}
+	start, end := ref(r.startCol, r.startRow), ref(r.endCol, r.endRow)
+	switch {
+	case start == "" && end == "":
+		return ""
+	case start == end && r.startCol > 0 && r.startRow > 0:
+		return start
+	}
+	return start + ":" + end
+}
+
+// シート上の GridRange に変換する（省略された端はシートの端まで）
+func (r a1Range) gridRange(sheetId int64) *sheets.GridRange {
+	gridRange := &sheets.GridRange{
+		SheetId:         sheetId,
+		EndColumnIndex:  r.endCol,
+		EndRowIndex:     r.endRow,
+		ForceSendFields: []string{"SheetId"},
+	}
+	if r.startCol > 0 {
+		gridRange.StartColumnIndex = r.startCol - 1
+	}
+	if r.startRow > 0 {
+		gridRange.StartRowIndex = r.startRow - 1
+	}
+	return gridRange
+}
+
+// GridRange を A1 表記の範囲に変換する（終端が 0 の場合はシートの端まで）
+func a1RangeFromGridRange(gridRange *sheets.GridRange) a1Range {
+	var r a1Range
+	if gridRange == nil {
+		return r
+	}
+	if gridRange.EndColumnIndex > 0 {
+		r.startCol, r.endCol = gridRange.StartColumnIndex+1, gridRange.EndColumnIndex
+	} else if gridRange.StartColumnIndex > 0 {
+		r.startCol = gridRange.StartColumnIndex + 1
+	}
+	if gridRange.EndRowIndex > 0 {
+		r.startRow, r.endRow = gridRange.StartRowIndex+1, gridRange.EndRowIndex
+	} else if gridRange.StartRowIndex > 0 {
+		r.startRow = gridRange.StartRowIndex + 1
+	}
+	return r
+}
+
+// 終端がすべて省略された範囲（"5:" や "C:" のように A1 表記で表せないもの）の終端をシートの大きさで補う
+func (r a1Range) closedBy(grid *sheets.GridProperties) a1Range {
+	if grid == nil || r.endCol > 0 || r.endRow > 0 {
+		return r
+	}
+	if r.startRow > 0 {
+		r.endRow = max(grid.RowCount, r.startRow)
+	}
+	if r.startCol > 0 {
+		r.endCol = max(grid.ColumnCount, r.startCol)
+	}
+	return r
+}
+
+// GridRange をシート名付きの A1 表記の範囲に変換する（終端の省略はシートの大きさで補う）
+func a1RangeOnSheet(gridRange *sheets.GridRange, sheetProps map[int64]*sheets.SheetProperties) a1Range {
+	r := a1RangeFromGridRange(gridRange)
+	if gridRange == nil {
+		return r
+	}
+	if props := sheetProps[gridRange.SheetId]; props != nil {
+		r = r.closedBy(props.GridProperties)
+		r.sheet = props.Title
+	}
+	return r
+}
+
+// A1 表記の範囲をシート上の GridRange に変換する
+func gridRangeFromA1(sheetId int64, rangeStr string) (*sheets.GridRange, error) {
+	r, err := parseA1Range(rangeStr)
+	if err != nil {
+		return nil, err
+	}
+	return r.gridRange(sheetId), nil
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestParseA1Range(t *testing.T) {
+	tests := []struct {
+		input string
+		want  a1Range
+		str   string
+	}{
+		// 単一セル
+		{"A1", a1Cell(1, 1), "A1"},
+		{"a1", a1Cell(1, 1), "A1"},
+		{"$A$1", a1Cell(1, 1), "A1"},
+		{"$B2", a1Cell(2, 2), "B2"},
+		{"B$2", a1Cell(2, 2), "B2"},
+		{"AA10", a1Cell(27, 10), "AA10"},
+		{"zz100", a1Cell(702, 100), "ZZ100"},
+		{"ZZZ1", a1Cell(18278, 1), "ZZZ1"},
+		{" C3 ", a1Cell(3, 3), "C3"},
+		// 矩形の範囲
+		{"A1:C10", a1Range{startCol: 1, startRow: 1, endCol: 3, endRow: 10}, "A1:C10"},
+		{"b2:d5", a1Range{startCol: 2, startRow: 2, endCol: 4, endRow: 5}, "B2:D5"},
+		{"$A$1:$C$10", a1Range{startCol: 1, startRow: 1, endCol: 3, endRow: 10}, "A1:C10"},
+		{"C10:A1", a1Range{startCol: 1, startRow: 1, endCol: 3, endRow: 10}, "A1:C10"},
+		{"A1:A1", a1Cell(1, 1), "A1"},
+		// 列全体・行全体
+		{"A:C", a1Range{startCol: 1, endCol: 3}, "A:C"},
+		{"b:b", a1Range{startCol: 2, endCol: 2}, "B:B"},
+		{"$A:$C", a1Range{startCol: 1, endCol: 3}, "A:C"},
+		{"C:A", a1Range{startCol: 1, endCol: 3}, "A:C"},
+		{"2:5", a1Range{startRow: 2, endRow: 5}, "2:5"},
+		{"$2:$5", a1Range{startRow: 2, endRow: 5}, "2:5"},
+		{"5:2", a1Range{startRow: 2, endRow: 5}, "2:5"},
+		// 終端が開いた範囲
+		{"A2:C", a1Range{startCol: 1, startRow: 2, endCol: 3}, "A2:C"},
+		{"A101:F", a1Range{startCol: 1, startRow: 101, endCol: 6}, "A101:F"},
+		{"B2:10", a1Range{startCol: 2, startRow: 2, endRow: 10}, "B2:10"},
+		// シート名付き
+		{"Sheet1!A1:B2", a1Range{sheet: "Sheet1", startCol: 1, startRow: 1, endCol: 2, endRow: 2}, "Sheet1!A1:B2"},
+		{"'My Sheet'!A1", a1Range{sheet: "My Sheet", startCol: 1, startRow: 1, endCol: 1, endRow: 1}, "'My Sheet'!A1"},
+		{"'Bob''s'!B:B", a1Range{sheet: "Bob's", startCol: 2, endCol: 2}, "'Bob''s'!B:B"},
+		{"'A!B'!2:3", a1Range{sheet: "A!B", startRow: 2, endRow: 3}, "'A!B'!2:3"},
+		{"'Q1 2024'!r1c1", a1Range{sheet: "Q1 2024", startCol: 1, startRow: 1, endCol: 1, endRow: 1}, "'Q1 2024'!A1"},
+		// R1C1 表記
+		{"R1C1", a1Cell(1, 1), "A1"},
+		{"r2c3", a1Cell(3, 2), "C2"},
+		{"R1C1:R10C3", a1Range{startCol: 1, startRow: 1, endCol: 3, endRow: 10}, "A1:C10"},
+		{"R10C3:R1C1", a1Range{startCol: 1, startRow: 1, endCol: 3, endRow: 10}, "A1:C10"},
+		{"Sheet1!R5C28", a1Range{sheet: "Sheet1", startCol: 28, startRow: 5, endCol: 28, endRow: 5}, "Sheet1!AB5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseA1Range(tt.input)
+			if err != nil {
+				t.Fatalf("parseA1Range(%q) failed: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseA1Range(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+			if s := got.String(); s != tt.str {
+				t.Errorf("parseA1Range(%q).String() = %q, want %q", tt.input, s, tt.str)
+			}
+			// 文字列に戻した範囲は同じ範囲として解析できる
+			again, err := parseA1Range(got.String())
+			if err != nil || again != got {
+				t.Errorf("round trip of %q: got %+v, %v", got.String(), again, err)
+			}
+		})
+	}
+}
+
+func TestParseA1RangeErrors(t *testing.T) {
+	invalid := []string{
+		"",
+		"   ",
+		"A",
+		"5",
+		"$A",
+		"AAAA1",
+		"A0",
+		"A-1",
+		"1A",
+		"A1B",
+		"A1:",
+		":B2",
+		"A:5",
+		"5:A",
+		"A1:B2:C3",
+		"$$1",
+		"A$$1",
+		"A1.5",
+		"Tax_Rates",
+		"!A1",
+		"'My Sheet'A1",
+		"'My Sheet",
+		"''!A1",
+		"Sheet1!",
+		"R0C1",
+		"R1C0",
+		"R[1]C[1]",
+		"RC",
+		"R1C18279",
+	}
+	for _, input := range invalid {
+		if got, err := parseA1Range(input); err == nil {
+			t.Errorf("parseA1Range(%q) = %+v, want an error", input, got)
+		}
+	}
+}
+
+func TestParseColumnLetters(t *testing.T) {
+	tests := map[string]int64{
+		"":    0,
+		"A":   1,
+		"z":   26,
+		"AA":  27,
+		"Az":  52,
+		"BA":  53,
+		"ZZ":  702,
+		"AAA": 703,
+		"ZZZ": 18278,
+	}
+	for letters, want := range tests {
+		got, err := parseColumnLetters(letters)
+		if err != nil || got != want {
+			t.Errorf("parseColumnLetters(%q) = %d, %v, want %d", letters, got, err, want)
+		}
+		if want > 0 && columnIndexToLetter(want) != strings.ToUpper(letters) {
+			t.Errorf("columnIndexToLetter(%d) = %s, want %s", want, columnIndexToLetter(want), strings.ToUpper(letters))
+		}
+	}
+	for _, letters := range []string{"AAAA", "A1", "-"} {
+		if _, err := parseColumnLetters(letters); err == nil {
+			t.Errorf("parseColumnLetters(%q) should fail", letters)
+		}
+	}
+}
+
+func TestA1RangeStart(t *testing.T) {
+	tests := []struct {
+		input            string
+		wantCol, wantRow int64
+	}{
+		{"C5", 3, 5},
+		{"B2:D10", 2, 2},
+		{"C:E", 3, 1},
+		{"4:8", 1, 4},
+		{"A101:F", 1, 101},
+	}
+	for _, tt := range tests {
+		r, err := parseA1Range(tt.input)
+		if err != nil {
+			t.Fatalf("parseA1Range(%q) failed: %v", tt.input, err)
+		}
+		if col, row := r.start(); col != tt.wantCol || row != tt.wantRow {
+			t.Errorf("%q start = (%d, %d), want (%d, %d)", tt.input, col, row, tt.wantCol, tt.wantRow)
+		}
+	}
+}
+
+func TestA1RangeGridRange(t *testing.T) {
+	tests := []struct {
+		input string
+		want  sheets.GridRange
+	}{
+		{"A1", sheets.GridRange{StartRowIndex: 0, EndRowIndex: 1, StartColumnIndex: 0, EndColumnIndex: 1}},
+		{"B2:D5", sheets.GridRange{StartRowIndex: 1, EndRowIndex: 5, StartColumnIndex: 1, EndColumnIndex: 4}},
+		{"C:C", sheets.GridRange{StartColumnIndex: 2, EndColumnIndex: 3}},
+		{"2:5", sheets.GridRange{StartRowIndex: 1, EndRowIndex: 5}},
+		{"A2:C", sheets.GridRange{StartRowIndex: 1, StartColumnIndex: 0, EndColumnIndex: 3}},
+	}
+	for _, tt := range tests {
+		r, err := parseA1Range(tt.input)
+		if err != nil {
+			t.Fatalf("parseA1Range(%q) failed: %v", tt.input, err)
+		}
+		got := r.gridRange(7)
+		if got.SheetId != 7 || got.StartRowIndex != tt.want.StartRowIndex || got.EndRowIndex != tt.want.EndRowIndex ||
+			got.StartColumnIndex != tt.want.StartColumnIndex || got.EndColumnIndex != tt.want.EndColumnIndex {
+			t.Errorf("%q gridRange = %+v, want %+v", tt.input, got, tt.want)
+		}
+		// GridRange から戻した範囲は同じ A1 表記になる
+		if back := a1RangeFromGridRange(got).String(); back != r.String() {
+			t.Errorf("a1RangeFromGridRange(%q) = %q", tt.input, back)
+		}
+	}
+}
+
+func TestA1RangeFromGridRange(t *testing.T) {
+	tests := []struct {
+		gridRange *sheets.GridRange
+		want      string
+	}{
+		{&sheets.GridRange{StartRowIndex: 1, EndRowIndex: 100, StartColumnIndex: 0, EndColumnIndex: 6}, "A2:F100"},
+		{&sheets.GridRange{StartRowIndex: 4, EndRowIndex: 5, StartColumnIndex: 2, EndColumnIndex: 3}, "C5"},
+		{&sheets.GridRange{StartColumnIndex: 2, EndColumnIndex: 3}, "C:C"},
+		{&sheets.GridRange{StartRowIndex: 1, EndRowIndex: 5}, "2:5"},
+		{&sheets.GridRange{StartRowIndex: 9, StartColumnIndex: 0, EndColumnIndex: 2}, "A10:B"},
+		{&sheets.GridRange{}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := a1RangeFromGridRange(tt.gridRange).String(); got != tt.want {
+			t.Errorf("a1RangeFromGridRange(%+v) = %q, want %q", tt.gridRange, got, tt.want)
+		}
+	}
+}
+
+func TestA1RangeOnSheet(t *testing.T) {
+	sheetProps := map[int64]*sheets.SheetProperties{
+		0: {SheetId: 0, Title: "Sheet1", GridProperties: &sheets.GridProperties{RowCount: 1000, ColumnCount: 26}},
+		7: {SheetId: 7, Title: "Q1 2024", GridProperties: &sheets.GridProperties{RowCount: 50, ColumnCount: 8}},
+	}
+	tests := []struct {
+		gridRange *sheets.GridRange
+		want      string
+	}{
+		{&sheets.GridRange{SheetId: 0, StartRowIndex: 1, EndRowIndex: 100, StartColumnIndex: 0, EndColumnIndex: 6}, "Sheet1!A2:F100"},
+		{&sheets.GridRange{SheetId: 0, StartRowIndex: 4}, "Sheet1!5:1000"},
+		{&sheets.GridRange{SheetId: 0, StartColumnIndex: 2}, "Sheet1!C:Z"},
+		{&sheets.GridRange{SheetId: 7, StartRowIndex: 4, StartColumnIndex: 2}, "'Q1 2024'!C5:H50"},
+		{&sheets.GridRange{SheetId: 7, StartRowIndex: 9, StartColumnIndex: 0, EndColumnIndex: 2}, "'Q1 2024'!A10:B"},
+		{&sheets.GridRange{SheetId: 7}, "'Q1 2024'"},
+	}
+	for _, tt := range tests {
+		got := a1RangeOnSheet(tt.gridRange, sheetProps)
+		if got.String() != tt.want {
+			t.Errorf("a1RangeOnSheet(%+v) = %q, want %q", tt.gridRange, got, tt.want)
+		}
+		if got.withoutSheet().String() == "" {
+			continue
+		}
+		// 変換した範囲は解析して同じ GridRange に戻せる
+		parsed, err := parseA1Range(got.String())
+		if err != nil {
+			t.Errorf("parseA1Range(%q) returned error: %v", got, err)
+			continue
+		}
+		back := parsed.gridRange(tt.gridRange.SheetId)
+		if back.StartRowIndex != tt.gridRange.StartRowIndex || back.StartColumnIndex != tt.gridRange.StartColumnIndex {
+			t.Errorf("round trip of %q starts at row %d col %d, want row %d col %d",
+				got, back.StartRowIndex, back.StartColumnIndex, tt.gridRange.StartRowIndex, tt.gridRange.StartColumnIndex)
+		}
+	}
+}
+
+func TestQuoteSheetName(t *testing.T) {
+	tests := map[string]string{
+		"Sheet1":     "Sheet1",
+		"Data":       "Data",
+		"_raw":       "_raw",
+		"Q1 2024":    "'Q1 2024'",
+		"Bob's":      "'Bob''s'",
+		"A1":         "'A1'",
+		"xfd10":      "'xfd10'",
+		"R1C1":       "'R1C1'",
+		"2024":       "'2024'",
+		"売上":         "'売上'",
+		"Sales-2024": "'Sales-2024'",
+	}
+	for name, want := range tests {
+		if got := quoteSheetName(name); got != want {
+			t.Errorf("quoteSheetName(%q) = %s, want %s", name, got, want)
+		}
+		// 引用符付きのシート名は元の名前として解析できる
+		r, err := parseA1Range(sheetRange(name, "B2"))
+		if err != nil || r.sheet != name {
+			t.Errorf("parseA1Range(%q) = %+v, %v", sheetRange(name, "B2"), r, err)
+		}
+	}
+	if got := sheetRange("Q1 2024", ""); got != "'Q1 2024'" {
+		t.Errorf("sheetRange without range = %s", got)
+	}
+}
+
+func TestFindSheet(t *testing.T) {
+	sheetList := []*sheets.Sheet{
+		{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1"}},
+		{Properties: &sheets.SheetProperties{SheetId: 2024, Title: "Archive"}},
+		{Properties: &sheets.SheetProperties{SheetId: 123, Title: "2024"}},
+	}
+	tests := map[string]string{
+		"Sheet1":  "Sheet1",
+		"0":       "Sheet1",
+		"123":     "2024",
+		"2024":    "2024",
+		"Archive": "Archive",
+	}
+	for name, want := range tests {
+		sheet := findSheet(sheetList, name)
+		if sheet == nil || sheet.Properties.Title != want {
+			t.Errorf("findSheet(%q) = %+v, want %s", name, sheet, want)
+		}
+	}
+	if sheet := findSheet(sheetList, "999"); sheet != nil {
+		t.Errorf("findSheet(\"999\") = %+v, want nil", sheet.Properties)
+	}
+}
@@ -14,6 +14,10 @@ type Config struct {
 	TokenPathRaw     string `envconfig:"TOKEN_PATH"`
 	TokenPath        string `envconfig:"-"`
 	FolderID         string `envconfig:"FOLDER_ID"`
+	ExportDir        string `envconfig:"EXPORT_DIR"`
+	ImportDir        string `envconfig:"IMPORT_DIR"`
+	// 保護された範囲と重なる書き込みを止めて確認を求める
+	WarnProtectedRanges bool `envconfig:"WARN_PROTECTED_RANGES"`
 }
 
 func NewConfig() (*Config, error) {
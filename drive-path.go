@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ルートフォルダからの相対パスを名前の配列に分割する
+// 名前に含まれる / は \/、\ は \\ と書く（それ以外の \ はそのまま名前の一部として扱う）
+func splitDrivePath(filePath string) ([]string, error) {
+	if strings.HasPrefix(filePath, "/") {
+		return nil, fmt.Errorf("invalid path: directory traversal is not allowed")
+	}
+
+	var (
+		parts   []string
+		current strings.Builder
+		escaped bool // 現在の名前にエスケープされた文字が含まれるか
+	)
+	flush := func() error {
+		part := current.String()
+		current.Reset()
+		wasEscaped := escaped
+		escaped = false
+		if wasEscaped {
+			parts = append(parts, part)
+			return nil
+		}
+		switch part {
+		case "", ".":
+			// 空の要素や "." は無視する
+			return nil
+		case "..":
+			return fmt.Errorf("invalid path: directory traversal is not allowed")
+		}
+		parts = append(parts, part)
+		return nil
+	}
+
+	for i := 0; i < len(filePath); i++ {
+		c := filePath[i]
+		switch {
+		case c == '\\' && i+1 < len(filePath) && (filePath[i+1] == '/' || filePath[i+1] == '\\'):
+			current.WriteByte(filePath[i+1])
+			escaped = true
+			i++
+		case c == '/':
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+// 名前に含まれる \ と / をエスケープする
+func escapeDrivePathSegment(name string) string {
+	name = strings.ReplaceAll(name, `\`, `\\`)
+	return strings.ReplaceAll(name, "/", `\/`)
+}
+
+// 名前の配列をパス文字列に変換する（splitDrivePath の逆変換）
+func formatDrivePath(parts []string) string {
+	escaped := make([]string, len(parts))
+	for i, part := range parts {
+		escaped[i] = escapeDrivePathSegment(part)
+	}
+	return strings.Join(escaped, "/")
+}
+
+// 親フォルダのパスと名前を連結する
+func joinDrivePath(parent, name string) string {
+	if parent == "" {
+		return escapeDrivePathSegment(name)
+	}
+	return parent + "/" + escapeDrivePathSegment(name)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitDrivePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", path: "", want: nil},
+		{name: "dot", path: ".", want: nil},
+		{name: "single", path: "Budget", want: []string{"Budget"}},
+		{name: "nested", path: "Projects/2024/data", want: []string{"Projects", "2024", "data"}},
+		{name: "redundant separators", path: "Projects//2024/./data/", want: []string{"Projects", "2024", "data"}},
+		{name: "escaped slash", path: `2024\/25 Budget`, want: []string{"2024/25 Budget"}},
+		{name: "escaped slash in folder", path: `Reports/2024\/25/Q1`, want: []string{"Reports", "2024/25", "Q1"}},
+		{name: "leading escaped slash", path: `\/wiki\/api\/v2\/blogposts`, want: []string{"/wiki/api/v2/blogposts"}},
+		{name: "escaped backslash", path: `C:\\data`, want: []string{`C:\data`}},
+		{name: "escaped backslash before separator", path: `dir\\/file`, want: []string{`dir\`, "file"}},
+		{name: "unescaped backslash is literal", path: `a\b`, want: []string{`a\b`}},
+		{name: "trailing backslash is literal", path: `a\`, want: []string{`a\`}},
+		{name: "unicode", path: "売上/2024年", want: []string{"売上", "2024年"}},
+		{name: "apostrophe", path: "Bob's report", want: []string{"Bob's report"}},
+		{name: "backslash before dot dot is a name", path: `\..`, want: []string{`\..`}},
+		{name: "absolute", path: "/etc", wantErr: true},
+		{name: "parent", path: "..", wantErr: true},
+		{name: "parent prefix", path: "../secret", wantErr: true},
+		{name: "parent in middle", path: "a/../b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitDrivePath(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("splitDrivePath(%q) = %q, want error", tt.path, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitDrivePath(%q) returned error: %v", tt.path, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitDrivePath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDrivePathRoundTrip(t *testing.T) {
+	tests := [][]string{
+		{"Budget"},
+		{"Projects", "2024", "data"},
+		{"2024/25 Budget"},
+		{"/wiki/api/v2/blogposts"},
+		{`C:\data`, `a\/b`},
+		{`dir\`, "file"},
+		{"売上", "Bob's report"},
+	}
+	for _, parts := range tests {
+		formatted := formatDrivePath(parts)
+		got, err := splitDrivePath(formatted)
+		if err != nil {
+			t.Fatalf("splitDrivePath(%q) returned error: %v", formatted, err)
+		}
+		if !slices.Equal(got, parts) {
+			t.Errorf("round trip of %q via %q = %q", parts, formatted, got)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import "strings"
+
+// Drive API の検索クエリ（files.list の q パラメータ）を組み立てる
+// 値はすべて driveQueryString でエスケープされるため、ファイル名などをそのまま渡してよい
+type driveQuery struct {
+	clauses []string
+}
+
+func newDriveQuery() *driveQuery {
+	return &driveQuery{}
+}
+
+// 指定したフォルダの直下にあるもの
+func (q *driveQuery) inParents(folderID string) *driveQuery {
+	return q.add(driveQueryString(folderID) + " in parents")
+}
+
+// 名前が完全に一致するもの
+func (q *driveQuery) nameEquals(name string) *driveQuery {
+	return q.add("name = " + driveQueryString(name))
+}
+
+// 名前に指定した文字列を含むもの
+func (q *driveQuery) nameContains(text string) *driveQuery {
+	return q.add("name contains " + driveQueryString(text))
+}
+
+// 本文・名前・説明に指定した文字列を含むもの
+func (q *driveQuery) fullTextContains(text string) *driveQuery {
+	return q.add("fullText contains " + driveQueryString(text))
+}
+
+// MIME タイプが一致するもの
+func (q *driveQuery) mimeType(mimeType string) *driveQuery {
+	return q.add("mimeType = " + driveQueryString(mimeType))
+}
+
+// ゴミ箱に入っていないもの
+func (q *driveQuery) notTrashed() *driveQuery {
+	return q.add("trashed = false")
+}
+
+// いずれかのクエリに一致するもの（or で連結して括弧で囲む）
+func (q *driveQuery) anyOf(queries ...*driveQuery) *driveQuery {
+	clauses := make([]string, 0, len(queries))
+	for _, query := range queries {
+		if clause := query.String(); clause != "" {
+			clauses = append(clauses, "("+clause+")")
+		}
+	}
+	if len(clauses) == 0 {
+		return q
+	}
+	return q.add("(" + strings.Join(clauses, " or ") + ")")
+}
+
+func (q *driveQuery) add(clause string) *driveQuery {
+	q.clauses = append(q.clauses, clause)
+	return q
+}
+
+// 条件を and で連結したクエリ文字列を返す
+func (q *driveQuery) String() string {
+	return strings.Join(q.clauses, " and ")
+}
+
+// Drive API のクエリ文字列リテラルを作成する（\ と ' をエスケープして ' で囲む）
+func driveQueryString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
@@ -0,0 +1,80 @@
+package main
+
+import "testing"
+
+func TestDriveQueryString(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "Budget", `'Budget'`},
+		{"empty", "", `''`},
+		{"apostrophe", "Bob's report", `'Bob\'s report'`},
+		{"multiple apostrophes", "'quoted'", `'\'quoted\''`},
+		{"backslash", `C:\data`, `'C:\\data'`},
+		{"backslash before apostrophe", `it\'s`, `'it\\\'s'`},
+		{"trailing backslash", `dir\`, `'dir\\'`},
+		{"double quotes", `say "hi"`, `'say "hi"'`},
+		{"slash", "2024/25 Budget", `'2024/25 Budget'`},
+		{"unicode", "売上データ 2024年", `'売上データ 2024年'`},
+		{"emoji", "📊 report", `'📊 report'`},
+		{"injection attempt", "x' or name contains '", `'x\' or name contains \''`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := driveQueryString(tt.value); got != tt.want {
+				t.Errorf("driveQueryString(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDriveQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *driveQuery
+		want  string
+	}{
+		{
+			name:  "empty",
+			query: newDriveQuery(),
+			want:  "",
+		},
+		{
+			name:  "path part",
+			query: newDriveQuery().inParents("folder123").nameEquals("Bob's report").notTrashed(),
+			want:  `'folder123' in parents and name = 'Bob\'s report' and trashed = false`,
+		},
+		{
+			name:  "name with slash and backslash",
+			query: newDriveQuery().inParents("root").nameEquals(`2024/25 \ Budget`),
+			want:  `'root' in parents and name = '2024/25 \\ Budget'`,
+		},
+		{
+			name:  "unicode name with mime type",
+			query: newDriveQuery().nameEquals("売上").mimeType(spreadsheetMimeType),
+			want:  `name = '売上' and mimeType = 'application/vnd.google-apps.spreadsheet'`,
+		},
+		{
+			name: "any of",
+			query: newDriveQuery().anyOf(
+				newDriveQuery().nameContains("it's"),
+				newDriveQuery().fullTextContains("it's"),
+			).notTrashed(),
+			want: `((name contains 'it\'s') or (fullText contains 'it\'s')) and trashed = false`,
+		},
+		{
+			name:  "any of with empty queries",
+			query: newDriveQuery().anyOf(newDriveQuery(), newDriveQuery()).notTrashed(),
+			want:  `trashed = false`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.String(); got != tt.want {
+				t.Errorf("query = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -86,6 +86,15 @@ func (g *GoogleAuth) GetDriveService(ctx context.Context) (*drive.Service, error
 	return srv, nil
 }
 
+// GetHTTPClient は認証済みのHTTPクライアントを返します
+func (g *GoogleAuth) GetHTTPClient(ctx context.Context) (*http.Client, error) {
+	client, err := g.getClient(ctx, g.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+	return client, nil
+}
+
 // refreshAndGetClient はトークンをリフレッシュして新しいクライアントを返します
 func (g *GoogleAuth) refreshAndGetClient(ctx context.Context) (*http.Client, error) {
 	if g.config == nil {
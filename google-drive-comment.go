@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+)
+
+// コメント一覧で取得するフィールド
+const commentFields = "id,author(displayName,emailAddress),content,createdTime,resolved,deleted,quotedFileContent(value)," +
+	"replies(id,author(displayName,emailAddress),content,createdTime,action,deleted)"
+
+type ListCommentsRequest struct {
+	Path            string `json:"path"`
+	IncludeResolved bool   `json:"include_resolved"`
+}
+
+var ListCommentsInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"path": {
+			Type:        "string",
+			Description: "Spreadsheet path (relative to root folder). Write a '/' that is part of a name as '\\/'. Example: 'Reports/Q3 Sales'",
+		},
+		"include_resolved": {
+			Type:        "boolean",
+			Description: "Also list resolved comments. Default: only open comments",
+		},
+	},
+	Required: []string{"path"},
+}
+
+type AddCommentRequest struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+var AddCommentInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"path": {
+			Type:        "string",
+			Description: "Spreadsheet path (relative to root folder). Write a '/' that is part of a name as '\\/'. Example: 'Reports/Q3 Sales'",
+		},
+		"content": {
+			Type:        "string",
+			Description: "Comment text. Mention the sheet and cells it refers to, e.g. 'Sheet1!B2: total does not match'",
+		},
+	},
+	Required: []string{"path", "content"},
+}
+
+type ReplyCommentRequest struct {
+	Path      string `json:"path"`
+	CommentID string `json:"comment_id"`
+	Content   string `json:"content"`
+}
+
+var ReplyCommentInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"path": {
+			Type:        "string",
+			Description: "Spreadsheet path (relative to root folder). Write a '/' that is part of a name as '\\/'. Example: 'Reports/Q3 Sales'",
+		},
+		"comment_id": {
+			Type:        "string",
+			Description: "ID of the comment to reply to, as shown by google_drive_list_comments",
+		},
+		"content": {
+			Type:        "string",
+			Description: "Reply text",
+		},
+	},
+	Required: []string{"path", "comment_id", "content"},
+}
+
+type ResolveCommentRequest struct {
+	Path      string `json:"path"`
+	CommentID string `json:"comment_id"`
+	Content   string `json:"content"`
+}
+
+var ResolveCommentInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"path": {
+			Type:        "string",
+			Description: "Spreadsheet path (relative to root folder). Write a '/' that is part of a name as '\\/'. Example: 'Reports/Q3 Sales'",
+		},
+		"comment_id": {
+			Type:        "string",
+			Description: "ID of the comment to resolve, as shown by google_drive_list_comments",
+		},
+		"content": {
+			Type:        "string",
+			Description: "Optional reply posted together with resolving, e.g. what was changed",
+		},
+	},
+	Required: []string{"path", "comment_id"},
+}
+
+// コメントの作成者を表示用に整形する
+func commentAuthor(user *drive.User) string {
+	if user == nil || user.DisplayName == "" {
+		return "unknown"
+	}
+	if user.EmailAddress != "" {
+		return fmt.Sprintf("%s <%s>", user.DisplayName, user.EmailAddress)
+	}
+	return user.DisplayName
+}
+
+// コメントと返信を表示用に書き出す（複数行の本文は続きの行を字下げする）
+func writeComment(builder *strings.Builder, comment *drive.Comment) {
+	status := "open"
+	if comment.Resolved {
+		status = "resolved"
+	}
+	builder.WriteString(fmt.Sprintf("- [%s] %s (%s, %s)\n", comment.Id, commentAuthor(comment.Author), comment.CreatedTime, status))
+	if comment.QuotedFileContent != nil && comment.QuotedFileContent.Value != "" {
+		builder.WriteString(fmt.Sprintf("  > %s\n", strings.ReplaceAll(comment.QuotedFileContent.Value, "\n", "\n  > ")))
+	}
+	builder.WriteString(fmt.Sprintf("  %s\n", strings.ReplaceAll(comment.Content, "\n", "\n  ")))
+	for _, reply := range comment.Replies {
+		if reply.Deleted {
+			continue
+		}
+		// 解決・再開の操作は本文がない場合もある
+		action := ""
+		switch reply.Action {
+		case "resolve":
+			action = " [resolved]"
+		case "reopen":
+			action = " [reopened]"
+		}
+		builder.WriteString(fmt.Sprintf("    ↳ %s (%s)%s", commentAuthor(reply.Author), reply.CreatedTime, action))
+		if reply.Content != "" {
+			builder.WriteString(": " + strings.ReplaceAll(reply.Content, "\n", "\n      "))
+		}
+		builder.WriteString("\n")
+	}
+}
+
+func (gd *GoogleDrive) ListCommentsHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ListCommentsRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// ファイルのIDを取得
+	fileID, err := gd.getFileIDByPathWithContext(ctx, request.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file ID: %w", err)
+	}
+
+	service, err := gd.auth.GetDriveService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drive service: %w", err)
+	}
+
+	var comments []*drive.Comment
+	resolved := 0
+	err = service.Comments.List(fileID).
+		PageSize(100).
+		Fields("nextPageToken", "comments("+commentFields+")").
+		Pages(ctx, func(page *drive.CommentList) error {
+			for _, comment := range page.Comments {
+				if comment.Deleted {
+					continue
+				}
+				if comment.Resolved {
+					resolved++
+					if !request.IncludeResolved {
+						continue
+					}
+				}
+				comments = append(comments, comment)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Comments on '%s':\n\n", request.Path))
+	if len(comments) == 0 {
+		result.WriteString("No comments found.\n")
+	}
+	for _, comment := range comments {
+		writeComment(&result, comment)
+	}
+	if resolved > 0 && !request.IncludeResolved {
+		result.WriteString(fmt.Sprintf("\n%d resolved comment(s) not shown. Set include_resolved to list them.\n", resolved))
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil
+}
+
+func (gd *GoogleDrive) AddCommentHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[AddCommentRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	if strings.TrimSpace(request.Content) == "" {
+		return nil, fmt.Errorf("comment content cannot be empty")
+	}
+
+	// ファイルのIDを取得
+	fileID, err := gd.getFileIDByPathWithContext(ctx, request.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file ID: %w", err)
+	}
+
+	service, err := gd.auth.GetDriveService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drive service: %w", err)
+	}
+
+	// Drive API からはセルに紐づくコメントを作成できないため、ファイル全体へのコメントになる
+	comment, err := service.Comments.Create(fileID, &drive.Comment{Content: request.Content}).
+		Fields("id").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to add comment: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Comment added to '%s'. Comment ID: %s", request.Path, comment.Id)}},
+	}, nil
+}
+
+func (gd *GoogleDrive) ReplyCommentHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ReplyCommentRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	if strings.TrimSpace(request.Content) == "" {
+		return nil, fmt.Errorf("reply content cannot be empty")
+	}
+
+	// ファイルのIDを取得
+	fileID, err := gd.getFileIDByPathWithContext(ctx, request.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file ID: %w", err)
+	}
+
+	service, err := gd.auth.GetDriveService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drive service: %w", err)
+	}
+
+	reply, err := service.Replies.Create(fileID, request.CommentID, &drive.Reply{Content: request.Content}).
+		Fields("id").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reply to comment %s: %w", request.CommentID, err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Reply added to comment %s on '%s'. Reply ID: %s", request.CommentID, request.Path, reply.Id)}},
+	}, nil
+}
+
+func (gd *GoogleDrive) ResolveCommentHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ResolveCommentRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// ファイルのIDを取得
+	fileID, err := gd.getFileIDByPathWithContext(ctx, request.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file ID: %w", err)
+	}
+
+	service, err := gd.auth.GetDriveService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drive service: %w", err)
+	}
+
+	// コメントの解決は resolve アクション付きの返信として行う
+	_, err = service.Replies.Create(fileID, request.CommentID, &drive.Reply{Action: "resolve", Content: request.Content}).
+		Fields("id").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve comment %s: %w", request.CommentID, err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Comment %s on '%s' has been resolved.", request.CommentID, request.Path)}},
+	}, nil
+}
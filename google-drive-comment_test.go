@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+)
+
+func TestWriteComment(t *testing.T) {
+	comment := &drive.Comment{
+		Id:                "c1",
+		Author:            &drive.User{DisplayName: "Reviewer", EmailAddress: "reviewer@example.com"},
+		Content:           "Total is wrong\nPlease recheck",
+		CreatedTime:       "2024-05-01T10:00:00Z",
+		QuotedFileContent: &drive.CommentQuotedFileContent{Value: "1,234"},
+		Replies: []*drive.Reply{
+			{Author: &drive.User{DisplayName: "Editor"}, Content: "Fixed", CreatedTime: "2024-05-02T09:00:00Z"},
+			{Author: &drive.User{DisplayName: "Editor"}, Content: "removed", Deleted: true},
+			{Author: &drive.User{DisplayName: "Reviewer"}, Action: "resolve", CreatedTime: "2024-05-03T09:00:00Z"},
+		},
+	}
+	var builder strings.Builder
+	writeComment(&builder, comment)
+	want := "- [c1] Reviewer <reviewer@example.com> (2024-05-01T10:00:00Z, open)\n" +
+		"  > 1,234\n" +
+		"  Total is wrong\n  Please recheck\n" +
+		"    ↳ Editor (2024-05-02T09:00:00Z): Fixed\n" +
+		"    ↳ Reviewer (2024-05-03T09:00:00Z) [resolved]\n"
+	if got := builder.String(); got != want {
+		t.Errorf("writeComment =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCommentAuthor(t *testing.T) {
+	tests := []struct {
+		user *drive.User
+		want string
+	}{
+		{nil, "unknown"},
+		{&drive.User{}, "unknown"},
+		{&drive.User{DisplayName: "Alice"}, "Alice"},
+		{&drive.User{DisplayName: "Alice", EmailAddress: "alice@example.com"}, "Alice <alice@example.com>"},
+	}
+	for _, tt := range tests {
+		if got := commentAuthor(tt.user); got != tt.want {
+			t.Errorf("commentAuthor(%+v) = %q, want %q", tt.user, got, tt.want)
+		}
+	}
+}
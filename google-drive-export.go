@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// エクスポート形式
+type exportFormat struct {
+	mimeType  string
+	extension string
+	text      bool // テキスト形式かどうか（リソースとして返す場合に text で返す）
+	perSheet  bool // シート単位でエクスポートできるかどうか
+}
+
+var exportFormats = map[string]exportFormat{
+	"xlsx": {mimeType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", extension: "xlsx"},
+	"ods":  {mimeType: "application/vnd.oasis.opendocument.spreadsheet", extension: "ods"},
+	"pdf":  {mimeType: "application/pdf", extension: "pdf", perSheet: true},
+	"csv":  {mimeType: "text/csv", extension: "csv", text: true, perSheet: true},
+	"tsv":  {mimeType: "text/tab-separated-values", extension: "tsv", text: true, perSheet: true},
+}
+
+type ExportFileRequest struct {
+	Path       string `json:"path"`
+	Format     string `json:"format"`
+	SheetName  string `json:"sheet_name"`
+	Output     string `json:"output"`
+	OutputName string `json:"output_name"`
+	Overwrite  bool   `json:"overwrite"`
+}
+
+var ExportFileInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"path": {
+			Type:        "string",
+			Description: "Spreadsheet path (relative to root folder). Write a '/' that is part of a name as '\\/'. Example: 'Reports/Q3 Sales'",
+		},
+		"format": {
+			Type:        "string",
+			Description: "Export format",
+			Enum:        []any{"xlsx", "ods", "pdf", "csv", "tsv"},
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Export only this sheet/tab (csv, tsv and pdf only). Without it, csv/tsv contain only the first sheet.",
+		},
+		"output": {
+			Type:        "string",
+			Description: "'file' writes to the export directory (MCPGS_EXPORT_DIR), 'resource' returns the content in the response. Default: 'file' when the export directory is configured, otherwise 'resource'",
+			Enum:        []any{"file", "resource"},
+		},
+		"output_name": {
+			Type:        "string",
+			Description: "File name to write in the export directory (without directories). Default: '<spreadsheet name>.<format>'",
+		},
+		"overwrite": {
+			Type:        "boolean",
+			Description: "Replace a file with the same name in the export directory. Without it, the export fails if the file exists; confirm with the user before overwriting",
+		},
+	},
+	Required: []string{"path", "format"},
+}
+
+// ファイル名として使えない文字を置き換える
+func sanitizeFileName(name string) string {
+	return strings.NewReplacer("/", "_", `\`, "_").Replace(name)
+}
+
+// エクスポートしたファイルを書き出す
+// 既存のファイルは overwrite の場合のみ上書きし、シンボリックリンクはたどらない
+func writeExportFile(path string, data []byte, overwrite bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSymlink != 0 || !info.Mode().IsRegular() {
+			return fmt.Errorf("'%s' exists and is not a regular file. Choose another output_name", path)
+		}
+		if !overwrite {
+			return fmt.Errorf("'%s' already exists. Choose another output_name, or set overwrite to true after confirming with the user", path)
+		}
+		flags = os.O_WRONLY | os.O_TRUNC
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to check the output file: %w", err)
+	}
+
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write exported file: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write exported file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write exported file: %w", err)
+	}
+	return nil
+}
+
+func (gd *GoogleDrive) ExportFileHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ExportFileRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	format, ok := exportFormats[request.Format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported format: '%s'. Supported formats: xlsx, ods, pdf, csv, tsv", request.Format)
+	}
+	if request.SheetName != "" && !format.perSheet {
+		return nil, fmt.Errorf("sheet_name is only supported for csv, tsv and pdf exports")
+	}
+
+	// 出力先を決定
+	output := request.Output
+	if output == "" {
+		output = "resource"
+		if gd.cfg.ExportDir != "" {
+			output = "file"
+		}
+	}
+	if output == "file" && gd.cfg.ExportDir == "" {
+		return nil, fmt.Errorf("export directory is not configured. Set MCPGS_EXPORT_DIR or use output 'resource'")
+	}
+	if request.OutputName != "" && (filepath.Base(request.OutputName) != request.OutputName || request.OutputName == "." || request.OutputName == "..") {
+		return nil, fmt.Errorf("invalid output_name: directories are not allowed")
+	}
+
+	// ファイルのIDを取得
+	fileID, err := gd.getFileIDByPathWithContext(ctx, request.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file ID: %w", err)
+	}
+
+	service, err := gd.auth.GetDriveService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drive service: %w", err)
+	}
+
+	file, err := service.Files.Get(fileID).
+		SupportsAllDrives(true).
+		Fields("name", "mimeType").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	if file.MimeType != spreadsheetMimeType {
+		return nil, fmt.Errorf("'%s' is not a Google Spreadsheet. Only Google Spreadsheets can be exported", request.Path)
+	}
+
+	// エクスポートを実行
+	var resp *http.Response
+	exportURL := fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s/export?format=%s", fileID, request.Format)
+	if request.SheetName != "" {
+		// シート単位のエクスポートは gid を指定して export URL から取得する
+		sheetsService, err := gd.auth.GetSheetsService(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sheets service: %w", err)
+		}
+		spreadsheet, err := sheetsService.Spreadsheets.Get(fileID).Fields("sheets.properties(sheetId,title)").Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get spreadsheet: %w", err)
+		}
+		sheet := findSheet(spreadsheet.Sheets, request.SheetName)
+		if sheet == nil {
+			return nil, fmt.Errorf("sheet not found: '%s'. Please check the sheet name. Use google_sheets_list_sheets to see available sheets in this spreadsheet", request.SheetName)
+		}
+		exportURL += fmt.Sprintf("&gid=%d", sheet.Properties.SheetId)
+
+		client, err := gd.auth.GetHTTPClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, exportURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create export request: %w", err)
+		}
+		resp, err = client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export file: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to export file: unexpected status %s", resp.Status)
+		}
+	} else {
+		resp, err = service.Files.Export(fileID, format.mimeType).Context(ctx).Download()
+		if err != nil {
+			return nil, fmt.Errorf("failed to export file: %w", err)
+		}
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exported data: %w", err)
+	}
+
+	// 出力ファイル名を決定
+	outputName := request.OutputName
+	if outputName == "" {
+		outputName = file.Name
+		if request.SheetName != "" {
+			outputName += " - " + request.SheetName
+		}
+		outputName = sanitizeFileName(outputName) + "." + format.extension
+	}
+
+	target := fmt.Sprintf("'%s'", request.Path)
+	if request.SheetName != "" {
+		target = fmt.Sprintf("sheet '%s' of '%s'", request.SheetName, request.Path)
+	}
+
+	if output == "resource" {
+		resource := &mcp.ResourceContents{
+			URI:      exportURL,
+			MIMEType: format.mimeType,
+		}
+		if format.text {
+			resource.Text = string(data)
+		} else {
+			// バイナリ形式は base64 エンコードされた blob として返す
+			resource.Blob = data
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Exported %s as %s (%s, %d bytes)", target, request.Format, outputName, len(data)),
+				},
+				&mcp.EmbeddedResource{Resource: resource},
+			},
+		}, nil
+	}
+
+	// エクスポート先ディレクトリに書き出す
+	if err := os.MkdirAll(gd.cfg.ExportDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	outputPath := filepath.Join(gd.cfg.ExportDir, outputName)
+	if err := writeExportFile(outputPath, data, request.Overwrite); err != nil {
+		return nil, err
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Exported %s as %s to '%s' (%d bytes)", target, request.Format, outputPath, len(data)),
+			},
+		},
+	}, nil
+}
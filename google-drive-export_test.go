@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Q3 Sales", "Q3 Sales"},
+		{"2024/25 Budget", "2024_25 Budget"},
+		{`a\b/c`, "a_b_c"},
+		{"../secret", ".._secret"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeFileName(tt.name); got != tt.want {
+			t.Errorf("sanitizeFileName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// エクスポート用の Google API の偽物（export URL に渡された gid を記録する）
+func newExportTestGoogleDrive(t *testing.T, exportDir string) (*GoogleDrive, context.Context, *[]string) {
+	t.Helper()
+	var gids []string
+	gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1", MimeType: spreadsheetMimeType}}})
+		case "/drive/v3/files/ss1":
+			writeTestJSON(t, w, &drive.File{Name: "Q3/Sales", MimeType: spreadsheetMimeType})
+		case "/drive/v3/files/ss1/export":
+			w.Write([]byte("whole,file\n"))
+		case "/v4/spreadsheets/ss1":
+			writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1"}},
+				{Properties: &sheets.SheetProperties{SheetId: 777, Title: "Data"}},
+			}})
+		case "/spreadsheets/d/ss1/export":
+			if r.URL.Host != "docs.google.com" {
+				t.Errorf("unexpected export host: %s", r.URL.Host)
+			}
+			gids = append(gids, r.URL.Query().Get("gid"))
+			w.Write([]byte("one,sheet\n"))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+	gs.cfg.ExportDir = exportDir
+	return &GoogleDrive{cfg: gs.cfg, auth: gs.auth}, ctx, &gids
+}
+
+func TestExportFileHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		exportDir  bool
+		request    ExportFileRequest
+		wantGids   []string
+		wantFile   string
+		wantText   string
+		wantErrMsg string
+	}{
+		{
+			name:     "whole spreadsheet as resource",
+			request:  ExportFileRequest{Format: "csv"},
+			wantText: "whole,file\n",
+		},
+		{
+			name:     "one sheet as resource",
+			request:  ExportFileRequest{Format: "csv", SheetName: "Data", Output: "resource"},
+			wantGids: []string{"777"},
+			wantText: "one,sheet\n",
+		},
+		{
+			name:      "one sheet to file",
+			exportDir: true,
+			request:   ExportFileRequest{Format: "tsv", SheetName: "Data"},
+			wantGids:  []string{"777"},
+			wantFile:  "Q3_Sales - Data.tsv",
+		},
+		{
+			name:      "sheet given by id",
+			exportDir: true,
+			request:   ExportFileRequest{Format: "pdf", SheetName: "0", OutputName: "report.pdf"},
+			wantGids:  []string{"0"},
+			wantFile:  "report.pdf",
+		},
+		{
+			name:      "whole spreadsheet to file",
+			exportDir: true,
+			request:   ExportFileRequest{Format: "xlsx"},
+			wantFile:  "Q3_Sales.xlsx",
+		},
+		{name: "sheet of xlsx", request: ExportFileRequest{Format: "xlsx", SheetName: "Data"}, wantErrMsg: "only supported for csv, tsv and pdf"},
+		{name: "unknown sheet", request: ExportFileRequest{Format: "csv", SheetName: "Missing"}, wantErrMsg: "sheet not found: 'Missing'"},
+		{name: "file without export directory", request: ExportFileRequest{Format: "csv", Output: "file"}, wantErrMsg: "export directory is not configured"},
+		{name: "output_name with directory", exportDir: true, request: ExportFileRequest{Format: "csv", OutputName: "../out.csv"}, wantErrMsg: "directories are not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exportDir := ""
+			if tt.exportDir {
+				exportDir = t.TempDir()
+			}
+			gd, ctx, gids := newExportTestGoogleDrive(t, exportDir)
+			tt.request.Path = "Q3 Sales"
+
+			result, err := gd.ExportFileHandler(ctx, nil, &mcp.CallToolParamsFor[ExportFileRequest]{Arguments: tt.request})
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExportFileHandler failed: %v", err)
+			}
+			if strings.Join(*gids, ",") != strings.Join(tt.wantGids, ",") {
+				t.Errorf("exported gids = %v, want %v", *gids, tt.wantGids)
+			}
+			if tt.wantText != "" {
+				resource := result.Content[1].(*mcp.EmbeddedResource).Resource
+				if resource.Text != tt.wantText {
+					t.Errorf("resource text = %q, want %q", resource.Text, tt.wantText)
+				}
+			}
+			if tt.wantFile != "" {
+				if _, err := os.Stat(filepath.Join(exportDir, tt.wantFile)); err != nil {
+					t.Errorf("exported file was not written: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteExportFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.csv")
+
+	if err := writeExportFile(path, []byte("first"), false); err != nil {
+		t.Fatalf("writeExportFile failed: %v", err)
+	}
+	// 既存のファイルは overwrite なしでは上書きしない
+	if err := writeExportFile(path, []byte("second"), false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("error = %v, want an already exists error", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "first" {
+		t.Errorf("existing file was changed to %q", data)
+	}
+	if err := writeExportFile(path, []byte("third"), true); err != nil {
+		t.Fatalf("writeExportFile with overwrite failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "third" {
+		t.Errorf("file = %q, want %q", data, "third")
+	}
+
+	// シンボリックリンクの先には書き込まない
+	outside := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(outside, []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link.csv")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+	for _, overwrite := range []bool{false, true} {
+		if err := writeExportFile(link, []byte("evil"), overwrite); err == nil || !strings.Contains(err.Error(), "not a regular file") {
+			t.Errorf("overwrite=%v: error = %v, want a not a regular file error", overwrite, err)
+		}
+	}
+	if data, _ := os.ReadFile(outside); string(data) != "keep" {
+		t.Errorf("symlink target was changed to %q", data)
+	}
+}
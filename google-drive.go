@@ -3,13 +3,16 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"path"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 )
 
 type GoogleDrive struct {
@@ -24,8 +27,41 @@ func NewGoogleDrive(cfg *Config, auth *GoogleAuth) (*GoogleDrive, error) {
 	}, nil
 }
 
+const (
+	folderMimeType      = "application/vnd.google-apps.folder"
+	spreadsheetMimeType = "application/vnd.google-apps.spreadsheet"
+
+	// 再帰的に一覧を取得する場合のデフォルトの深さ
+	defaultListMaxDepth = 3
+)
+
+// mime_type フィルタで利用できる短縮名
+var mimeTypeAliases = map[string]string{
+	"folder":       folderMimeType,
+	"spreadsheet":  spreadsheetMimeType,
+	"document":     "application/vnd.google-apps.document",
+	"presentation": "application/vnd.google-apps.presentation",
+	"form":         "application/vnd.google-apps.form",
+}
+
+// order_by で指定できるソートキーと Drive API の orderBy の対応
+var listOrderByFields = map[string]string{
+	"name":          "name",
+	"modified_time": "modifiedTime",
+	"created_time":  "createdTime",
+	"size":          "quotaBytesUsed",
+}
+
 type ListFilesRequest struct {
-	Path string `json:"path"`
+	Path          string `json:"path"`
+	MimeType      string `json:"mime_type"`
+	NameContains  string `json:"name_contains"`
+	ModifiedAfter string `json:"modified_after"`
+	OrderBy       string `json:"order_by"`
+	Descending    bool   `json:"descending"`
+	Recursive     bool   `json:"recursive"`
+	MaxDepth      int64  `json:"max_depth"`
+	Format        string `json:"format"`
 }
 
 var ListFilesInputSchema = &jsonschema.Schema{
@@ -33,10 +69,78 @@ var ListFilesInputSchema = &jsonschema.Schema{
 	Properties: map[string]*jsonschema.Schema{
 		"path": {
 			Type:        "string",
-			Description: "Directory path to list (relative to root folder). Leave empty for root directory. Examples: 'Archive', 'Projects/2024'",
+			Description: "Directory path to list (relative to root folder). Leave empty for root directory. Write a '/' that is part of a name as '\\/'. Examples: 'Archive', 'Projects/2024', 'Reports/2024\\/25'",
 			Default:     json.RawMessage(`"."`),
 		},
+		"mime_type": {
+			Type:        "string",
+			Description: "Only list items of this MIME type. Shorthands: 'spreadsheet', 'folder', 'document', 'presentation', 'form'. Full MIME types such as 'text/csv' are also accepted.",
+		},
+		"name_contains": {
+			Type:        "string",
+			Description: "Only list items whose name contains this text (case-insensitive). Example: 'budget'",
+		},
+		"modified_after": {
+			Type:        "string",
+			Description: "Only list items modified after this date/time. Accepts 'YYYY-MM-DD' or RFC 3339. Example: '2024-04-01'",
+		},
+		"order_by": {
+			Type:        "string",
+			Description: "Sort key within each folder. Folders are always listed before files.",
+			Enum:        []any{"name", "modified_time", "created_time", "size"},
+			Default:     json.RawMessage(`"name"`),
+		},
+		"descending": {
+			Type:        "boolean",
+			Description: "Sort in descending order",
+		},
+		"recursive": {
+			Type:        "boolean",
+			Description: "Also list the contents of subfolders",
+		},
+		"max_depth": {
+			Type:        "integer",
+			Description: "Maximum folder depth when recursive (1 = direct children only). Default: 3",
+		},
+		"format": {
+			Type:        "string",
+			Description: "Output format: 'list' groups folders and files, 'tree' renders the folder hierarchy",
+			Enum:        []any{"list", "tree"},
+			Default:     json.RawMessage(`"list"`),
+		},
+	},
+}
+
+type SearchFilesRequest struct {
+	Query      string `json:"query"`
+	Mode       string `json:"mode"`
+	MimeType   string `json:"mime_type"`
+	MaxResults int64  `json:"max_results"`
+}
+
+var SearchFilesInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"query": {
+			Type:        "string",
+			Description: "Text to search for. Example: 'Q3 hiring'",
+		},
+		"mode": {
+			Type:        "string",
+			Description: "Where to search: 'name' matches file names, 'full_text' matches file contents, 'any' matches either",
+			Enum:        []any{"any", "name", "full_text"},
+			Default:     json.RawMessage(`"any"`),
+		},
+		"mime_type": {
+			Type:        "string",
+			Description: "Only return items of this MIME type. Shorthands: 'spreadsheet', 'folder', 'document', 'presentation', 'form'",
+		},
+		"max_results": {
+			Type:        "integer",
+			Description: "Maximum number of results to return. Default: 50",
+		},
 	},
+	Required: []string{"query"},
 }
 
 type CopyFileRequest struct {
@@ -49,11 +153,11 @@ var CopyFileInputSchema = &jsonschema.Schema{
 	Properties: map[string]*jsonschema.Schema{
 		"src_path": {
 			Type:        "string",
-			Description: "Source file/folder path (relative to root folder). Example: 'Archive/document.xlsx'",
+			Description: "Source file/folder path (relative to root folder). Write a '/' that is part of a name as '\\/'. Example: 'Archive/document.xlsx'",
 		},
 		"dst_path": {
 			Type:        "string",
-			Description: "Destination path including new filename. Example: 'Projects/2024/document-copy.xlsx'",
+			Description: "Destination path including new filename. Write a '/' that is part of a name as '\\/'. Example: 'Projects/2024/document-copy.xlsx'",
 		},
 	},
 	Required: []string{"src_path", "dst_path"},
@@ -69,7 +173,7 @@ var RenameFileInputSchema = &jsonschema.Schema{
 	Properties: map[string]*jsonschema.Schema{
 		"path": {
 			Type:        "string",
-			Description: "Current file/folder path (relative to root folder). Example: 'Archive/old-name.xlsx'",
+			Description: "Current file/folder path (relative to root folder). Write a '/' that is part of a name as '\\/'. Example: 'Archive/old-name.xlsx'",
 		},
 		"new_name": {
 			Type:        "string",
@@ -86,29 +190,25 @@ func (gd *GoogleDrive) getFileIDByPath(ctx context.Context, filePath string) (st
 
 // コンテキスト付きでパスからファイルIDを取得する
 func (gd *GoogleDrive) getFileIDByPathWithContext(ctx context.Context, filePath string) (string, error) {
-	// パスの正規化と検証
-	filePath = path.Clean(filePath)
-	if strings.HasPrefix(filePath, "..") || strings.HasPrefix(filePath, "/") {
-		return "", fmt.Errorf("invalid path: directory traversal is not allowed")
+	// パスの分割と検証
+	parts, err := splitDrivePath(filePath)
+	if err != nil {
+		return "", err
 	}
+	return gd.getFileIDByParts(ctx, parts)
+}
 
-	// ルートフォルダから開始
+// パスの各要素を順番に辿ってファイルIDを取得する
+func (gd *GoogleDrive) getFileIDByParts(ctx context.Context, parts []string) (string, error) {
+	// ルートフォルダから開始（パスが空の場合はルートフォルダを返す）
 	parentID := gd.cfg.FolderID
 
-	// パスが空の場合はルートフォルダを返す
-	if filePath == "." || filePath == "" {
-		return parentID, nil
-	}
-
-	// パスを分割
-	parts := strings.Split(filePath, "/")
-
 	// 各パスの部分を順番に検索
 	for i, part := range parts {
 		isLast := i == len(parts)-1
 
 		// 現在のフォルダ内のファイル/フォルダを検索
-		query := fmt.Sprintf("'%s' in parents and name = '%s' and trashed = false", parentID, part)
+		query := newDriveQuery().inParents(parentID).nameEquals(part).notTrashed().String()
 		service, err := gd.auth.GetDriveService(ctx)
 		if err != nil {
 			return "", fmt.Errorf("failed to get drive service: %w", err)
@@ -127,9 +227,9 @@ func (gd *GoogleDrive) getFileIDByPathWithContext(ctx context.Context, filePath
 		if len(fileList.Files) == 0 {
 			// 最後のパス部分で、ファイルが存在しない場合はエラー
 			if isLast {
-				return "", fmt.Errorf("file not found: '%s'. Please check the file name and path. Use google_drive_list_files to browse available files", filePath)
+				return "", fmt.Errorf("file not found: '%s'. Please check the file name and path. Use google_drive_list_files to browse available files", formatDrivePath(parts))
 			}
-			return "", fmt.Errorf("folder not found: '%s'. Please check the folder path. Use google_drive_list_files to browse available folders", strings.Join(parts[:i+1], "/"))
+			return "", fmt.Errorf("folder not found: '%s'. Please check the folder path. Use google_drive_list_files to browse available folders", formatDrivePath(parts[:i+1]))
 		}
 
 		// 次の親IDを設定（同名ファイルが複数ある場合は最初のものを使用）
@@ -141,42 +241,243 @@ func (gd *GoogleDrive) getFileIDByPathWithContext(ctx context.Context, filePath
 
 // パスからファイルの親フォルダIDとファイル名を取得する
 func (gd *GoogleDrive) getParentIDAndFileName(ctx context.Context, filePath string) (string, string, error) {
-	// パスの正規化と検証
-	filePath = path.Clean(filePath)
-	if strings.HasPrefix(filePath, "..") || strings.HasPrefix(filePath, "/") {
-		return "", "", fmt.Errorf("invalid path: directory traversal is not allowed")
+	// パスの分割と検証
+	parts, err := splitDrivePath(filePath)
+	if err != nil {
+		return "", "", err
 	}
 
 	// パスが空の場合はエラー
-	if filePath == "." || filePath == "" {
+	if len(parts) == 0 {
 		return "", "", fmt.Errorf("invalid path: path is empty")
 	}
 
-	// パスを親ディレクトリとファイル名に分割
-	dir, fileName := path.Split(filePath)
-
 	// 親ディレクトリのIDを取得
-	parentID := gd.cfg.FolderID
-	if dir != "" {
-		// 末尾のスラッシュを削除
-		dir = strings.TrimSuffix(dir, "/")
-		var err error
-		parentID, err = gd.getFileIDByPath(ctx, dir)
+	parentID, err := gd.getFileIDByParts(ctx, parts[:len(parts)-1])
+	if err != nil {
+		return "", "", err
+	}
+
+	return parentID, parts[len(parts)-1], nil
+}
+
+// ファイル一覧のフィルタ条件
+type fileFilter struct {
+	mimeType      string
+	nameContains  string
+	modifiedAfter time.Time
+}
+
+func newFileFilter(mimeType, nameContains, modifiedAfter string) (*fileFilter, error) {
+	filter := &fileFilter{
+		mimeType:     mimeType,
+		nameContains: strings.ToLower(nameContains),
+	}
+	if alias, ok := mimeTypeAliases[mimeType]; ok {
+		filter.mimeType = alias
+	}
+	if modifiedAfter != "" {
+		t, err := parseDateTime(modifiedAfter)
 		if err != nil {
-			return "", "", err
+			return nil, fmt.Errorf("invalid modified_after: %w", err)
+		}
+		filter.modifiedAfter = t
+	}
+	return filter, nil
+}
+
+// フィルタ条件が1つでも指定されているか
+func (f *fileFilter) active() bool {
+	return f.mimeType != "" || f.nameContains != "" || !f.modifiedAfter.IsZero()
+}
+
+func (f *fileFilter) match(file *drive.File) bool {
+	if f.mimeType != "" && file.MimeType != f.mimeType {
+		return false
+	}
+	if f.nameContains != "" && !strings.Contains(strings.ToLower(file.Name), f.nameContains) {
+		return false
+	}
+	if !f.modifiedAfter.IsZero() {
+		modified, err := time.Parse(time.RFC3339, file.ModifiedTime)
+		if err != nil || !modified.After(f.modifiedAfter) {
+			return false
+		}
+	}
+	return true
+}
+
+// 'YYYY-MM-DD' または RFC 3339 形式の日時を解析する
+func parseDateTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is not a date (YYYY-MM-DD) or RFC 3339 date-time", value)
+	}
+	return t, nil
+}
+
+// フォルダ一覧の1要素（再帰取得時はサブフォルダの中身を Children に持つ）
+type driveEntry struct {
+	File     *drive.File
+	Path     string
+	Matched  bool
+	Children []*driveEntry
+}
+
+// ページングしながらクエリに一致するファイルをすべて取得する
+func listAllFiles(ctx context.Context, call *drive.FilesListCall) ([]*drive.File, error) {
+	var files []*drive.File
+	err := call.Pages(ctx, func(page *drive.FileList) error {
+		files = append(files, page.Files...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// フォルダ内のファイルとフォルダを取得する（maxDepth まで再帰的に取得）
+func (gd *GoogleDrive) listFolderEntries(ctx context.Context, service *drive.Service, folderID, parentPath string, depth, maxDepth int64, orderBy string) ([]*driveEntry, error) {
+	query := newDriveQuery().inParents(folderID).notTrashed().String()
+	files, err := listAllFiles(ctx, service.Files.List().
+		Q(query).
+		SupportsAllDrives(true).         // 共有ドライブ対応
+		IncludeItemsFromAllDrives(true). // 共有ドライブ対応
+		Fields("nextPageToken, files(id, name, mimeType, createdTime, modifiedTime, size)").
+		OrderBy(orderBy).
+		PageSize(1000))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	entries := make([]*driveEntry, 0, len(files))
+	for _, file := range files {
+		// 名前に含まれる '/' はパスの区切りと区別できるようにエスケープする
+		entry := &driveEntry{
+			File:    file,
+			Path:    joinDrivePath(parentPath, file.Name),
+			Matched: true,
+		}
+		if file.MimeType == folderMimeType && depth < maxDepth {
+			entry.Children, err = gd.listFolderEntries(ctx, service, file.Id, entry.Path, depth+1, maxDepth, orderBy)
+			if err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// フィルタに一致する要素と、一致する要素を含むフォルダだけを残す
+func filterEntries(entries []*driveEntry, filter *fileFilter) []*driveEntry {
+	var result []*driveEntry
+	for _, entry := range entries {
+		entry.Children = filterEntries(entry.Children, filter)
+		entry.Matched = filter.match(entry.File)
+		if entry.Matched || len(entry.Children) > 0 {
+			result = append(result, entry)
 		}
 	}
+	return result
+}
 
-	return parentID, fileName, nil
+// 階層構造を深さ優先でフラットな一覧に変換する
+func flattenEntries(entries []*driveEntry) []*driveEntry {
+	var result []*driveEntry
+	for _, entry := range entries {
+		result = append(result, entry)
+		result = append(result, flattenEntries(entry.Children)...)
+	}
+	return result
+}
+
+// Google Docsなどの特殊なファイルタイプを判別する
+func fileTypeLabel(mimeType string) string {
+	switch mimeType {
+	case folderMimeType:
+		return "Folder"
+	case "application/vnd.google-apps.document":
+		return "Google Doc"
+	case spreadsheetMimeType:
+		return "Google Spreadsheet"
+	case "application/vnd.google-apps.presentation":
+		return "Google Presentation"
+	case "application/vnd.google-apps.form":
+		return "Google Form"
+	}
+	return "File"
+}
+
+// ファイルの種類・サイズ・更新日時を表示用に整形する
+func fileDetails(file *drive.File) string {
+	details := fileTypeLabel(file.MimeType)
+	// ファイルサイズ（Google Docsなどは表示されない）
+	if file.Size > 0 {
+		details += fmt.Sprintf(", Size: %d bytes", file.Size)
+	}
+	if file.ModifiedTime != "" {
+		details += fmt.Sprintf(", Modified: %s", file.ModifiedTime)
+	}
+	return details
+}
+
+// 階層構造をツリー形式で出力する
+func writeEntryTree(builder *strings.Builder, entries []*driveEntry, indent string) {
+	for i, entry := range entries {
+		branch, childIndent := "├── ", indent+"│   "
+		if i == len(entries)-1 {
+			branch, childIndent = "└── ", indent+"    "
+		}
+		if entry.File.MimeType == folderMimeType {
+			builder.WriteString(fmt.Sprintf("%s%s%s/\n", indent, branch, entry.File.Name))
+			writeEntryTree(builder, entry.Children, childIndent)
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("%s%s%s (%s)\n", indent, branch, entry.File.Name, fileDetails(entry.File)))
+	}
 }
 
 func (gd *GoogleDrive) ListFilesHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ListFilesRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
 	// パスが指定されていない場合はルートディレクトリを使用
-	dirPath := params.Arguments.Path
+	dirPath := request.Path
 	if dirPath == "" {
 		dirPath = "."
 	}
 
+	filter, err := newFileFilter(request.MimeType, request.NameContains, request.ModifiedAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	// ソート順を決定（フォルダを常に先に表示）
+	orderKey := request.OrderBy
+	if orderKey == "" {
+		orderKey = "name"
+	}
+	orderField, ok := listOrderByFields[orderKey]
+	if !ok {
+		return nil, fmt.Errorf("invalid order_by: '%s'", request.OrderBy)
+	}
+	if request.Descending {
+		orderField += " desc"
+	}
+	orderBy := "folder," + orderField
+
+	// 再帰しない場合は直下のみを取得
+	var maxDepth int64 = 1
+	if request.Recursive {
+		maxDepth = request.MaxDepth
+		if maxDepth <= 0 {
+			maxDepth = defaultListMaxDepth
+		}
+	}
+
 	// 指定されたパスのフォルダIDを取得
 	folderID, err := gd.getFileIDByPathWithContext(ctx, dirPath)
 	if err != nil {
@@ -184,34 +485,55 @@ func (gd *GoogleDrive) ListFilesHandler(ctx context.Context, cc *mcp.ServerSessi
 	}
 
 	// フォルダ内のファイルとフォルダを取得
-	query := fmt.Sprintf("'%s' in parents and trashed = false", folderID)
 	service, err := gd.auth.GetDriveService(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get drive service: %w", err)
 	}
 
-	fileList, err := service.Files.List().
-		Q(query).
-		SupportsAllDrives(true).         // 共有ドライブ対応
-		IncludeItemsFromAllDrives(true). // 共有ドライブ対応
-		Fields("files(id, name, mimeType, createdTime, modifiedTime, size)").
-		OrderBy("name").
-		Do()
+	entries, err := gd.listFolderEntries(ctx, service, folderID, "", 1, maxDepth, orderBy)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list files: %w", err)
+		return nil, err
+	}
+	if filter.active() {
+		entries = filterEntries(entries, filter)
+	}
+
+	// 一致したフォルダ・ファイルを数える
+	folderCount, fileCount := 0, 0
+	allEntries := flattenEntries(entries)
+	for _, entry := range allEntries {
+		if !entry.Matched {
+			continue
+		}
+		if entry.File.MimeType == folderMimeType {
+			folderCount++
+		} else {
+			fileCount++
+		}
 	}
 
 	// 結果を整形
 	var result strings.Builder
+	if request.Format == "tree" {
+		result.WriteString(fmt.Sprintf("Tree of directory '%s':\n\n", dirPath))
+		result.WriteString(dirPath + "\n")
+		writeEntryTree(&result, entries, "")
+		if len(entries) == 0 {
+			result.WriteString("  No files or folders found\n")
+		}
+		result.WriteString(fmt.Sprintf("\nTotal: %d folders, %d files\n", folderCount, fileCount))
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+		}, nil
+	}
+
 	result.WriteString(fmt.Sprintf("Files in directory '%s':\n\n", dirPath))
 
 	// フォルダを先に表示
 	result.WriteString("Folders:\n")
-	folderCount := 0
-	for _, file := range fileList.Files {
-		if file.MimeType == "application/vnd.google-apps.folder" {
-			result.WriteString(fmt.Sprintf("- %s (Folder)\n", file.Name))
-			folderCount++
+	for _, entry := range allEntries {
+		if entry.Matched && entry.File.MimeType == folderMimeType {
+			result.WriteString(fmt.Sprintf("- %s (Folder)\n", entry.Path))
 		}
 	}
 	if folderCount == 0 {
@@ -220,30 +542,9 @@ func (gd *GoogleDrive) ListFilesHandler(ctx context.Context, cc *mcp.ServerSessi
 
 	// ファイルを表示
 	result.WriteString("\nFiles:\n")
-	fileCount := 0
-	for _, file := range fileList.Files {
-		if file.MimeType != "application/vnd.google-apps.folder" {
-			// Google Docsなどの特殊なファイルタイプを判別
-			fileType := "File"
-			switch file.MimeType {
-			case "application/vnd.google-apps.document":
-				fileType = "Google Doc"
-			case "application/vnd.google-apps.spreadsheet":
-				fileType = "Google Spreadsheet"
-			case "application/vnd.google-apps.presentation":
-				fileType = "Google Presentation"
-			case "application/vnd.google-apps.form":
-				fileType = "Google Form"
-			}
-
-			// ファイルサイズ（Google Docsなどは表示されない）
-			sizeInfo := ""
-			if file.Size > 0 {
-				sizeInfo = fmt.Sprintf(", Size: %d bytes", file.Size)
-			}
-
-			result.WriteString(fmt.Sprintf("- %s (%s%s)\n", file.Name, fileType, sizeInfo))
-			fileCount++
+	for _, entry := range allEntries {
+		if entry.Matched && entry.File.MimeType != folderMimeType {
+			result.WriteString(fmt.Sprintf("- %s (%s)\n", entry.Path, fileDetails(entry.File)))
 		}
 	}
 	if fileCount == 0 {
@@ -259,6 +560,191 @@ func (gd *GoogleDrive) ListFilesHandler(ctx context.Context, cc *mcp.ServerSessi
 	}, nil
 }
 
+// 検索結果のデフォルトの最大件数
+const defaultSearchMaxResults = 50
+
+// 検索結果が最大件数に達したことを示す（ページングを打ち切るために使用）
+var errSearchLimitReached = errors.New("search result limit reached")
+
+// フォルダのパス解決結果
+type resolvedPath struct {
+	path   string
+	inside bool // ルートフォルダ配下かどうか
+}
+
+// ファイルの親を辿ってルートフォルダからの相対パスを組み立てる
+type drivePathResolver struct {
+	service *drive.Service
+	rootID  string
+	folders map[string]resolvedPath
+}
+
+func newDrivePathResolver(service *drive.Service, rootID string) *drivePathResolver {
+	return &drivePathResolver{
+		service: service,
+		rootID:  rootID,
+		folders: make(map[string]resolvedPath),
+	}
+}
+
+// ファイルのルートフォルダからの相対パスを返す（ルートフォルダ配下でなければ false）
+func (r *drivePathResolver) filePath(ctx context.Context, file *drive.File) (string, bool, error) {
+	for _, parentID := range file.Parents {
+		parent, err := r.folderPath(ctx, parentID)
+		if err != nil {
+			return "", false, err
+		}
+		if parent.inside {
+			return joinDrivePath(parent.path, file.Name), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+func (r *drivePathResolver) folderPath(ctx context.Context, folderID string) (resolvedPath, error) {
+	if folderID == r.rootID {
+		return resolvedPath{inside: true}, nil
+	}
+	if cached, ok := r.folders[folderID]; ok {
+		return cached, nil
+	}
+	// 循環参照に備えて、解決中のフォルダはルート配下ではないものとして仮登録する
+	r.folders[folderID] = resolvedPath{}
+
+	folder, err := r.service.Files.Get(folderID).
+		SupportsAllDrives(true).
+		Fields("id, name, parents").
+		Context(ctx).
+		Do()
+	if err != nil {
+		// アクセスできないフォルダはルートフォルダ配下ではない
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && (apiErr.Code == http.StatusNotFound || apiErr.Code == http.StatusForbidden) {
+			return resolvedPath{}, nil
+		}
+		return resolvedPath{}, fmt.Errorf("failed to get folder: %w", err)
+	}
+
+	folderPath, inside, err := r.filePath(ctx, folder)
+	if err != nil {
+		return resolvedPath{}, err
+	}
+	result := resolvedPath{path: folderPath, inside: inside}
+	r.folders[folderID] = result
+	return result, nil
+}
+
+func (gd *GoogleDrive) SearchFilesHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[SearchFilesRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// 検索語が空でないことを確認
+	if strings.TrimSpace(request.Query) == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+
+	maxResults := request.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultSearchMaxResults
+	}
+
+	// 検索クエリを作成
+	query := newDriveQuery()
+	switch request.Mode {
+	case "name":
+		query.nameContains(request.Query)
+	case "full_text":
+		query.fullTextContains(request.Query)
+	case "any", "":
+		query.anyOf(
+			newDriveQuery().nameContains(request.Query),
+			newDriveQuery().fullTextContains(request.Query),
+		)
+	default:
+		return nil, fmt.Errorf("invalid mode: '%s'", request.Mode)
+	}
+	query.notTrashed()
+	if request.MimeType != "" {
+		mimeType := request.MimeType
+		if alias, ok := mimeTypeAliases[mimeType]; ok {
+			mimeType = alias
+		}
+		query.mimeType(mimeType)
+	}
+
+	service, err := gd.auth.GetDriveService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drive service: %w", err)
+	}
+
+	// ルートフォルダが共有ドライブ内にある場合は、そのドライブを検索対象にする
+	root, err := service.Files.Get(gd.cfg.FolderID).
+		SupportsAllDrives(true).
+		Fields("id, driveId").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get root folder: %w", err)
+	}
+
+	call := service.Files.List().
+		Q(query.String()).
+		SupportsAllDrives(true).         // 共有ドライブ対応
+		IncludeItemsFromAllDrives(true). // 共有ドライブ対応
+		Fields("nextPageToken, files(id, name, mimeType, modifiedTime, size, parents)").
+		PageSize(100)
+	if root.DriveId != "" {
+		call = call.Corpora("drive").DriveId(root.DriveId)
+	}
+
+	// ルートフォルダ配下のファイルだけを結果に含める
+	type searchHit struct {
+		path string
+		file *drive.File
+	}
+	var hits []searchHit
+	resolver := newDrivePathResolver(service, gd.cfg.FolderID)
+	err = call.Pages(ctx, func(page *drive.FileList) error {
+		for _, file := range page.Files {
+			if file.Id == gd.cfg.FolderID {
+				continue
+			}
+			filePath, inside, err := resolver.filePath(ctx, file)
+			if err != nil {
+				return err
+			}
+			if !inside {
+				continue
+			}
+			// 最大件数を超えるヒットがあった時点で打ち切る（ちょうど最大件数の場合は打ち切りではない）
+			if int64(len(hits)) >= maxResults {
+				return errSearchLimitReached
+			}
+			hits = append(hits, searchHit{path: filePath, file: file})
+		}
+		return nil
+	})
+	limitReached := errors.Is(err, errSearchLimitReached)
+	if err != nil && !limitReached {
+		return nil, fmt.Errorf("failed to search files: %w", err)
+	}
+
+	// 結果を整形
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Search results for '%s' (%d hits):\n\n", request.Query, len(hits)))
+	if len(hits) == 0 {
+		result.WriteString("No matching files found.\n")
+	}
+	for _, hit := range hits {
+		result.WriteString(fmt.Sprintf("- %s (%s)\n", hit.path, fileDetails(hit.file)))
+	}
+	if limitReached {
+		result.WriteString(fmt.Sprintf("\nShowing the first %d results. Narrow the query or increase max_results to see more.\n", maxResults))
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil
+}
+
 func (gd *GoogleDrive) CopyFileHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[CopyFileRequest]) (*mcp.CallToolResultFor[any], error) {
 	// ソースファイルのIDを取得
 	srcFileID, err := gd.getFileIDByPathWithContext(ctx, params.Arguments.SrcPath)
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+// テスト用サーバーが一覧で一度に返すファイル数
+const testDrivePageSize = 2
+
+// フォルダごとのファイル一覧を返す Drive API のテスト用サーバー
+func newTestDriveService(t *testing.T, folders map[string][]*drive.File) *drive.Service {
+	t.Helper()
+	service, _ := newTestDriveServer(t, folders, nil)
+	return service
+}
+
+// フォルダごとのファイル一覧と各ファイルの取得に応答する Drive API のテスト用サーバー
+// ファイルの親は一覧を返すフォルダから求め、statuses に指定したファイルはそのステータスのエラーを返す
+// 戻り値の map にはファイルごとの取得回数を記録する
+func newTestDriveServer(t *testing.T, folders map[string][]*drive.File, statuses map[string]int) (*drive.Service, map[string]int) {
+	t.Helper()
+	gets := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, fileID, ok := strings.Cut(r.URL.Path, "/files/"); ok {
+			gets[fileID]++
+			if status, ok := statuses[fileID]; ok {
+				w.WriteHeader(status)
+				fmt.Fprintf(w, `{"error": {"code": %d, "message": "%s"}}`, status, http.StatusText(status))
+				return
+			}
+			file := &drive.File{Id: fileID}
+			found := false
+			for folderID, children := range folders {
+				for _, child := range children {
+					if child.Id == fileID {
+						file.Name, file.MimeType = child.Name, child.MimeType
+						file.Parents = append(file.Parents, folderID)
+						found = true
+					}
+				}
+			}
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"error": {"code": 404, "message": "File not found"}}`)
+				return
+			}
+			if err := json.NewEncoder(w).Encode(file); err != nil {
+				t.Errorf("failed to encode response: %v", err)
+			}
+			return
+		}
+
+		// クエリは "'<folderID>' in parents and trashed = false" の形式
+		query := r.URL.Query().Get("q")
+		var files []*drive.File
+		for folderID, children := range folders {
+			if strings.HasPrefix(query, "'"+folderID+"' in parents") {
+				files = children
+			}
+		}
+		// ページングを確認できるように 2 件ずつ返す（ページトークンは開始位置）
+		start := 0
+		fmt.Sscan(r.URL.Query().Get("pageToken"), &start)
+		list := &drive.FileList{Files: files[min(start, len(files)):min(start+testDrivePageSize, len(files))]}
+		if start+testDrivePageSize < len(files) {
+			list.NextPageToken = fmt.Sprint(start + testDrivePageSize)
+		}
+		if err := json.NewEncoder(w).Encode(list); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	service, err := drive.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("failed to create drive service: %v", err)
+	}
+	return service, gets
+}
+
+func TestListFolderEntriesEscapesSlashes(t *testing.T) {
+	service := newTestDriveService(t, map[string][]*drive.File{
+		"root": {
+			{Id: "f1", Name: "2024/25 Budget", MimeType: spreadsheetMimeType},
+			{Id: "d1", Name: "Reports/Archive", MimeType: folderMimeType},
+		},
+		"d1": {
+			{Id: "f2", Name: "Q1/Q2", MimeType: spreadsheetMimeType},
+		},
+	})
+	gd := &GoogleDrive{cfg: &Config{FolderID: "root"}}
+
+	entries, err := gd.listFolderEntries(context.Background(), service, "root", "", 1, 2, "name")
+	if err != nil {
+		t.Fatalf("listFolderEntries failed: %v", err)
+	}
+	var paths []string
+	for _, entry := range flattenEntries(entries) {
+		paths = append(paths, entry.Path)
+		// 一覧のパスは元の名前に戻せる
+		parts, err := splitDrivePath(entry.Path)
+		if err != nil || parts[len(parts)-1] != entry.File.Name {
+			t.Errorf("splitDrivePath(%q) = %v, %v", entry.Path, parts, err)
+		}
+	}
+	want := []string{`2024\/25 Budget`, `Reports\/Archive`, `Reports\/Archive/Q1\/Q2`}
+	if strings.Join(paths, "\n") != strings.Join(want, "\n") {
+		t.Errorf("paths = %q, want %q", paths, want)
+	}
+}
+
+func TestDrivePathResolver(t *testing.T) {
+	// root 配下: Reports/2024、外部: Outside、循環: LoopA <-> LoopB
+	service, gets := newTestDriveServer(t, map[string][]*drive.File{
+		"root":    {{Id: "reports", Name: "Reports", MimeType: folderMimeType}},
+		"reports": {{Id: "y2024", Name: "2024", MimeType: folderMimeType}},
+		"other":   {{Id: "outside", Name: "Outside", MimeType: folderMimeType}},
+		"loopA":   {{Id: "loopB", Name: "LoopB", MimeType: folderMimeType}},
+		"loopB":   {{Id: "loopA", Name: "LoopA", MimeType: folderMimeType}},
+	}, map[string]int{
+		"other":     http.StatusNotFound,
+		"forbidden": http.StatusForbidden,
+		"broken":    http.StatusInternalServerError,
+	})
+	resolver := newDrivePathResolver(service, "root")
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		file       *drive.File
+		wantPath   string
+		wantInside bool
+		wantErr    bool
+	}{
+		{name: "in root", file: &drive.File{Name: "Budget", Parents: []string{"root"}}, wantPath: "Budget", wantInside: true},
+		{name: "nested", file: &drive.File{Name: "Q1/Q2", Parents: []string{"y2024"}}, wantPath: `Reports/2024/Q1\/Q2`, wantInside: true},
+		{name: "outside root", file: &drive.File{Name: "Secret", Parents: []string{"outside"}}},
+		{name: "forbidden parent", file: &drive.File{Name: "Secret", Parents: []string{"forbidden"}}},
+		{name: "cycle", file: &drive.File{Name: "Lost", Parents: []string{"loopA"}}},
+		{name: "second parent inside", file: &drive.File{Name: "Shared", Parents: []string{"forbidden", "reports"}}, wantPath: "Reports/Shared", wantInside: true},
+		{name: "server error", file: &drive.File{Name: "Broken", Parents: []string{"broken"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath, gotInside, err := resolver.filePath(ctx, tt.file)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("filePath(%s) succeeded, want an error", tt.file.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("filePath(%s) failed: %v", tt.file.Name, err)
+			}
+			if gotPath != tt.wantPath || gotInside != tt.wantInside {
+				t.Errorf("filePath(%s) = %q, %v, want %q, %v", tt.file.Name, gotPath, gotInside, tt.wantPath, tt.wantInside)
+			}
+		})
+	}
+
+	// 解決済みのフォルダは取得し直さない（403 / 404 のフォルダも外部として記録する）
+	for _, folderID := range []string{"reports", "y2024", "outside", "other", "forbidden", "loopA", "loopB"} {
+		if gets[folderID] != 1 {
+			t.Errorf("folder %s was fetched %d times, want 1", folderID, gets[folderID])
+		}
+	}
+	if gets["root"] != 0 {
+		t.Errorf("root folder was fetched %d times, want 0", gets["root"])
+	}
+}
+
+func TestSearchFilesHandlerLimit(t *testing.T) {
+	inside := func(name string) *drive.File {
+		return &drive.File{Id: name, Name: name, MimeType: spreadsheetMimeType, Parents: []string{"root"}}
+	}
+	outside := &drive.File{Id: "x", Name: "Outside", MimeType: spreadsheetMimeType, Parents: []string{"elsewhere"}}
+
+	tests := []struct {
+		name          string
+		pages         [][]*drive.File
+		wantHits      int
+		wantTruncated bool
+	}{
+		{name: "fewer than max_results", pages: [][]*drive.File{{inside("a"), inside("b")}}, wantHits: 2},
+		{name: "exactly max_results", pages: [][]*drive.File{{inside("a"), inside("b")}, {inside("c")}}, wantHits: 3},
+		// ルートフォルダ外のヒットは打ち切りの判定に含めない
+		{name: "exactly max_results followed by outside hits", pages: [][]*drive.File{{inside("a"), inside("b"), inside("c")}, {outside}}, wantHits: 3},
+		{name: "more than max_results", pages: [][]*drive.File{{inside("a"), inside("b")}, {outside, inside("c"), inside("d")}}, wantHits: 3, wantTruncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/drive/v3/files/root":
+					writeTestJSON(t, w, &drive.File{Id: "root"})
+				case "/drive/v3/files/elsewhere":
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"error": {"code": 404, "message": "File not found"}}`)
+				case "/drive/v3/files":
+					// ページトークンはページ番号
+					page := 0
+					fmt.Sscan(r.URL.Query().Get("pageToken"), &page)
+					list := &drive.FileList{Files: tt.pages[page]}
+					if page+1 < len(tt.pages) {
+						list.NextPageToken = fmt.Sprint(page + 1)
+					}
+					writeTestJSON(t, w, list)
+				default:
+					t.Errorf("unexpected request: %s", r.URL)
+					http.NotFound(w, r)
+				}
+			})
+			gd := &GoogleDrive{cfg: gs.cfg, auth: gs.auth}
+
+			result, err := gd.SearchFilesHandler(ctx, nil, &mcp.CallToolParamsFor[SearchFilesRequest]{Arguments: SearchFilesRequest{
+				Query:      "budget",
+				MaxResults: 3,
+			}})
+			if err != nil {
+				t.Fatalf("SearchFilesHandler failed: %v", err)
+			}
+			text := result.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, fmt.Sprintf("(%d hits)", tt.wantHits)) {
+				t.Errorf("result does not have %d hits:\n%s", tt.wantHits, text)
+			}
+			if truncated := strings.Contains(text, "Showing the first 3 results"); truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v:\n%s", truncated, tt.wantTruncated, text)
+			}
+			if strings.Contains(text, "Outside") {
+				t.Errorf("result contains a file outside the root folder:\n%s", text)
+			}
+		})
+	}
+}
+
+// 一覧のテストに使うフォルダ構成
+//
+//	root
+//	├── Budget 2024（スプレッドシート）
+//	├── Notes（ドキュメント）
+//	├── Reports/
+//	│   ├── Q1 Sales（スプレッドシート）
+//	│   └── Archive/
+//	│       └── Old Budget（スプレッドシート）
+//	└── Empty/
+func newListTestDriveService(t *testing.T) *drive.Service {
+	t.Helper()
+	return newTestDriveService(t, map[string][]*drive.File{
+		"root": {
+			{Id: "budget", Name: "Budget 2024", MimeType: spreadsheetMimeType, ModifiedTime: "2024-05-01T00:00:00Z"},
+			{Id: "notes", Name: "Notes", MimeType: "application/vnd.google-apps.document", ModifiedTime: "2024-01-01T00:00:00Z"},
+			{Id: "reports", Name: "Reports", MimeType: folderMimeType},
+			{Id: "empty", Name: "Empty", MimeType: folderMimeType},
+		},
+		"reports": {
+			{Id: "q1", Name: "Q1 Sales", MimeType: spreadsheetMimeType, ModifiedTime: "2024-04-01T00:00:00Z"},
+			{Id: "archive", Name: "Archive", MimeType: folderMimeType},
+		},
+		"archive": {
+			{Id: "old", Name: "Old Budget", MimeType: spreadsheetMimeType, ModifiedTime: "2020-01-01T00:00:00Z"},
+		},
+	})
+}
+
+// 一覧の各要素のパスを並べる
+func entryPaths(entries []*driveEntry) []string {
+	var paths []string
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+	return paths
+}
+
+func TestFlattenEntries(t *testing.T) {
+	tests := []struct {
+		maxDepth int64
+		want     []string
+	}{
+		{maxDepth: 1, want: []string{"Budget 2024", "Notes", "Reports", "Empty"}},
+		{maxDepth: 2, want: []string{"Budget 2024", "Notes", "Reports", "Reports/Q1 Sales", "Reports/Archive", "Empty"}},
+		{maxDepth: 3, want: []string{"Budget 2024", "Notes", "Reports", "Reports/Q1 Sales", "Reports/Archive", "Reports/Archive/Old Budget", "Empty"}},
+	}
+	for _, tt := range tests {
+		gd := &GoogleDrive{cfg: &Config{FolderID: "root"}}
+		// root は 2 ページに分かれて返される
+		entries, err := gd.listFolderEntries(context.Background(), newListTestDriveService(t), "root", "", 1, tt.maxDepth, "name")
+		if err != nil {
+			t.Fatalf("listFolderEntries failed: %v", err)
+		}
+		if got := entryPaths(flattenEntries(entries)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("depth %d: paths = %q, want %q", tt.maxDepth, got, tt.want)
+		}
+	}
+}
+
+func TestFilterEntries(t *testing.T) {
+	tests := []struct {
+		name          string
+		mimeType      string
+		nameContains  string
+		modifiedAfter string
+		want          []string
+		wantMatched   []string
+	}{
+		// 一致する要素を含むフォルダは一致しなくても残す
+		{
+			name:        "mime type alias",
+			mimeType:    "spreadsheet",
+			want:        []string{"Budget 2024", "Reports", "Reports/Q1 Sales", "Reports/Archive", "Reports/Archive/Old Budget"},
+			wantMatched: []string{"Budget 2024", "Reports/Q1 Sales", "Reports/Archive/Old Budget"},
+		},
+		{
+			name:         "name contains is case insensitive",
+			nameContains: "BUDGET",
+			want:         []string{"Budget 2024", "Reports", "Reports/Archive", "Reports/Archive/Old Budget"},
+			wantMatched:  []string{"Budget 2024", "Reports/Archive/Old Budget"},
+		},
+		{
+			name:          "modified after",
+			modifiedAfter: "2024-03-01",
+			want:          []string{"Budget 2024", "Reports", "Reports/Q1 Sales"},
+			wantMatched:   []string{"Budget 2024", "Reports/Q1 Sales"},
+		},
+		{
+			name:        "folders",
+			mimeType:    "folder",
+			want:        []string{"Reports", "Reports/Archive", "Empty"},
+			wantMatched: []string{"Reports", "Reports/Archive", "Empty"},
+		},
+		{name: "nothing matches", nameContains: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gd := &GoogleDrive{cfg: &Config{FolderID: "root"}}
+			entries, err := gd.listFolderEntries(context.Background(), newListTestDriveService(t), "root", "", 1, 3, "name")
+			if err != nil {
+				t.Fatalf("listFolderEntries failed: %v", err)
+			}
+			filter, err := newFileFilter(tt.mimeType, tt.nameContains, tt.modifiedAfter)
+			if err != nil {
+				t.Fatalf("newFileFilter failed: %v", err)
+			}
+
+			flat := flattenEntries(filterEntries(entries, filter))
+			if got := entryPaths(flat); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paths = %q, want %q", got, tt.want)
+			}
+			var matched []*driveEntry
+			for _, entry := range flat {
+				if entry.Matched {
+					matched = append(matched, entry)
+				}
+			}
+			if got := entryPaths(matched); !reflect.DeepEqual(got, tt.wantMatched) {
+				t.Errorf("matched = %q, want %q", got, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestWriteEntryTree(t *testing.T) {
+	gd := &GoogleDrive{cfg: &Config{FolderID: "root"}}
+	entries, err := gd.listFolderEntries(context.Background(), newListTestDriveService(t), "root", "", 1, 3, "name")
+	if err != nil {
+		t.Fatalf("listFolderEntries failed: %v", err)
+	}
+
+	var builder strings.Builder
+	writeEntryTree(&builder, entries, "")
+	want := `├── Budget 2024 (Google Spreadsheet, Modified: 2024-05-01T00:00:00Z)
+├── Notes (Google Doc, Modified: 2024-01-01T00:00:00Z)
+├── Reports/
+│   ├── Q1 Sales (Google Spreadsheet, Modified: 2024-04-01T00:00:00Z)
+│   └── Archive/
+│       └── Old Budget (Google Spreadsheet, Modified: 2020-01-01T00:00:00Z)
+└── Empty/
+`
+	if got := builder.String(); got != want {
+		t.Errorf("tree =\n%s\nwant\n%s", got, want)
+	}
+}
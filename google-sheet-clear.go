@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+type ClearRangeRequest struct {
+	SpreadsheetName  string   `json:"spreadsheet_name"`
+	SheetName        string   `json:"sheet_name"`
+	Ranges           []string `json:"ranges"`
+	ClearFormats     bool     `json:"clear_formats"`
+	ClearNotes       bool     `json:"clear_notes"`
+	IgnoreProtection bool     `json:"ignore_protection"`
+}
+
+var ClearRangeInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to modify",
+		},
+		"ranges": {
+			Type:        "array",
+			Description: "Cell ranges in A1 notation to clear. Examples: [\"A2:D100\"], [\"B:B\", \"F1:F10\"]",
+			Items:       &jsonschema.Schema{Type: "string"},
+		},
+		"clear_formats": {
+			Type:        "boolean",
+			Description: "Also clear cell formatting (number formats, colors, borders, ...). Formatting is not saved and cannot be restored afterwards",
+		},
+		"clear_notes": {
+			Type:        "boolean",
+			Description: "Also clear cell notes",
+		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Write even if the target overlaps a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "ranges"},
+}
+
+func (gs *GoogleSheets) ClearRangeHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ClearRangeRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	if len(request.Ranges) == 0 {
+		return nil, fmt.Errorf("ranges cannot be empty")
+	}
+
+	sheetName := request.SheetName
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	ranges, err := gs.resolveRanges(ctx, spreadsheetId, sheetName, request.Ranges)
+	if err != nil {
+		return nil, err
+	}
+	fullRanges := make([]string, len(ranges))
+	for i, rangeStr := range ranges {
+		if rangeStr == "" {
+			return nil, fmt.Errorf("named range '%s' covers the whole sheet. Please specify a cell range", request.Ranges[i])
+		}
+		fullRanges[i] = sheetRange(sheetName, rangeStr)
+	}
+
+	// 保護された範囲への書き込みを確認
+	if !request.IgnoreProtection {
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, sheetName, ranges); err != nil {
+			return nil, err
+		}
+	}
+
+	// 消去前のデータを取得
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	prevData, err := service.Spreadsheets.Values.BatchGet(spreadsheetId).Ranges(fullRanges...).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data before clearing: %w", err)
+	}
+	// メモも消去する場合は元に戻せるように消去前のメモを取得
+	var prevNotes []cellNote
+	if request.ClearNotes {
+		for _, rangeStr := range ranges {
+			notes, err := gs.getCellNotes(ctx, spreadsheetId, sheetName, rangeStr)
+			if err != nil {
+				return nil, err
+			}
+			prevNotes = append(prevNotes, notes...)
+		}
+	}
+
+	if request.ClearFormats || request.ClearNotes {
+		// 書式やメモも消去する場合は UpdateCells のフィールドマスクで消去する
+		sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, sheetName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+		}
+		fields := []string{"userEnteredValue"}
+		if request.ClearFormats {
+			fields = append(fields, "userEnteredFormat")
+		}
+		if request.ClearNotes {
+			fields = append(fields, "note")
+		}
+
+		var requests []*sheets.Request
+		for _, rangeStr := range ranges {
+			gridRange, err := gridRangeFromA1(sheetId, rangeStr)
+			if err != nil {
+				return nil, err
+			}
+			requests = append(requests, &sheets.Request{
+				UpdateCells: &sheets.UpdateCellsRequest{
+					Range:  gridRange,
+					Fields: strings.Join(fields, ","),
+				},
+			})
+		}
+		_, err = service.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{Requests: requests}).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to clear ranges: %w", err)
+		}
+	} else {
+		// 値のみ消去する
+		_, err = service.Spreadsheets.Values.BatchClear(spreadsheetId, &sheets.BatchClearValuesRequest{
+			Ranges: fullRanges,
+		}).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to clear ranges: %w", err)
+		}
+	}
+
+	// 成功メッセージを作成
+	cleared := "values"
+	switch {
+	case request.ClearFormats && request.ClearNotes:
+		cleared = "values, formats and notes"
+	case request.ClearFormats:
+		cleared = "values and formats"
+	case request.ClearNotes:
+		cleared = "values and notes"
+	}
+	message := fmt.Sprintf("Successfully cleared %s of %s in sheet '%s' of spreadsheet '%s'",
+		cleared, strings.Join(request.Ranges, ", "), request.SheetName, request.SpreadsheetName)
+	message += "\n\nPrevious data has been saved. To undo this change, you can use the previous data."
+	if request.ClearFormats {
+		// 書式は保存していないため、このツールでは元に戻せない
+		message += "\nCell formatting was not saved and cannot be restored with these tools. Use the version history of the spreadsheet to recover it if needed."
+	}
+
+	// 消去前のデータを表示用に整形
+	var prevDataStr strings.Builder
+	prevDataStr.WriteString("\n\nPrevious data details:\n\n")
+	for i, valueRange := range prevData.ValueRanges {
+		target, err := parseA1Range(ranges[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse range: %w", err)
+		}
+		prevDataStr.WriteString(fmt.Sprintf("%s:\n\n", request.Ranges[i]))
+		prevDataStr.WriteString(formatTableData(target, valueRange.Values))
+		prevDataStr.WriteString("\n")
+	}
+	if len(prevNotes) > 0 {
+		prevDataStr.WriteString("Previous notes (restore them with google_sheets_set_note):\n\n")
+		writeCellNotes(&prevDataStr, prevNotes)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message + prevDataStr.String(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestClearRangeHandlerSavesNotes(t *testing.T) {
+	cleared := false
+	gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1"}}})
+		case "/v4/spreadsheets/ss1":
+			if r.URL.Query().Get("includeGridData") != "true" {
+				writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{
+					{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1"}},
+				}})
+				return
+			}
+			// メモは消去する前に取得する
+			if cleared {
+				t.Errorf("notes were read after the range was cleared")
+			}
+			writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{{Data: []*sheets.GridData{{
+				StartRow:    1,
+				StartColumn: 1,
+				RowData: []*sheets.RowData{
+					{Values: []*sheets.CellData{{Note: "checked by Alice"}, {}}},
+				},
+			}}}}})
+		case "/v4/spreadsheets/ss1/values:batchGet":
+			writeTestJSON(t, w, &sheets.BatchGetValuesResponse{ValueRanges: []*sheets.ValueRange{
+				{Values: [][]interface{}{{"10", "20"}}},
+			}})
+		case "/v4/spreadsheets/ss1:batchUpdate":
+			cleared = true
+			writeTestJSON(t, w, &sheets.BatchUpdateSpreadsheetResponse{})
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+
+	result, err := gs.ClearRangeHandler(ctx, nil, &mcp.CallToolParamsFor[ClearRangeRequest]{Arguments: ClearRangeRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Ranges:          []string{"B2:C2"},
+		ClearFormats:    true,
+		ClearNotes:      true,
+	}})
+	if err != nil {
+		t.Fatalf("ClearRangeHandler failed: %v", err)
+	}
+	if !cleared {
+		t.Fatalf("the range was not cleared")
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "- B2: checked by Alice") {
+		t.Errorf("previous notes are missing:\n%s", text)
+	}
+	if !strings.Contains(text, "formatting was not saved") {
+		t.Errorf("message does not say that formatting cannot be restored:\n%s", text)
+	}
+}
@@ -0,0 +1,703 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+// 条件付き書式で適用する書式
+type ConditionalFormatStyle struct {
+	BackgroundColor string `json:"background_color"`
+	TextColor       string `json:"text_color"`
+	Bold            bool   `json:"bold"`
+	Italic          bool   `json:"italic"`
+	Strikethrough   bool   `json:"strikethrough"`
+	Underline       bool   `json:"underline"`
+}
+
+// カラースケールの基準点
+type GradientPoint struct {
+	Color string `json:"color"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type ConditionalFormatGradient struct {
+	Min *GradientPoint `json:"min"`
+	Mid *GradientPoint `json:"mid"`
+	Max *GradientPoint `json:"max"`
+}
+
+// 条件付き書式ルールの内容（追加・更新で共通）
+type ConditionalFormatRuleFields struct {
+	Ranges    []string                   `json:"ranges"`
+	RuleType  string                     `json:"rule_type"`
+	Condition string                     `json:"condition"`
+	Values    []string                   `json:"values"`
+	Format    *ConditionalFormatStyle    `json:"format"`
+	Gradient  *ConditionalFormatGradient `json:"gradient"`
+}
+
+var gradientPointSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"color": {
+			Type:        "string",
+			Description: "Hex color of this point. Example: '#57BB8A'",
+		},
+		"type": {
+			Type:        "string",
+			Description: "How value is interpreted. Default: 'min' for min, 'percentile' (50) for mid, 'max' for max",
+			Enum:        []any{"min", "max", "number", "percent", "percentile"},
+		},
+		"value": {
+			Type:        "string",
+			Description: "Value for 'number', 'percent' and 'percentile' points",
+		},
+	},
+	Required: []string{"color"},
+}
+
+// 条件付き書式ルールの内容のスキーマ（追加・更新で共通）
+func conditionalFormatRuleProperties() map[string]*jsonschema.Schema {
+	return map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to modify",
+		},
+		"ranges": {
+			Type:        "array",
+			Description: "Cell ranges in A1 notation the rule applies to. Examples: [\"A2:F100\"], [\"C:C\", \"E:E\"]",
+			Items:       &jsonschema.Schema{Type: "string"},
+		},
+		"rule_type": {
+			Type: "string",
+			Description: "Kind of rule: 'boolean' (format cells matching condition), 'custom_formula' (format cells where the formula in values[0] is TRUE, " +
+				"relative to the top-left cell of the first range), or 'gradient' (color scale)",
+			Enum: []any{"boolean", "custom_formula", "gradient"},
+		},
+		"condition": {
+			Type:        "string",
+			Description: "For 'boolean': the condition type. Example: 'TEXT_EQ' with values [\"Done\"], 'NUMBER_LESS' with values [\"0\"], 'BLANK'",
+			Enum:        booleanConditionTypes,
+		},
+		"values": {
+			Type:        "array",
+			Description: "Condition values for 'boolean', or the formula for 'custom_formula'. Example: [\"=$D2<TODAY()\"]",
+			Items:       &jsonschema.Schema{Type: "string"},
+		},
+		"format": {
+			Type:        "object",
+			Description: "For 'boolean' and 'custom_formula': format applied to matching cells",
+			Properties: map[string]*jsonschema.Schema{
+				"background_color": {
+					Type:        "string",
+					Description: "Hex background color. Example: '#F4CCCC'",
+				},
+				"text_color": {
+					Type:        "string",
+					Description: "Hex text color. Example: '#CC0000'",
+				},
+				"bold":          {Type: "boolean"},
+				"italic":        {Type: "boolean"},
+				"strikethrough": {Type: "boolean"},
+				"underline":     {Type: "boolean"},
+			},
+		},
+		"gradient": {
+			Type:        "object",
+			Description: "For 'gradient': the color scale points. min and max are required, mid is optional",
+			Properties: map[string]*jsonschema.Schema{
+				"min": gradientPointSchema,
+				"mid": gradientPointSchema,
+				"max": gradientPointSchema,
+			},
+			Required: []string{"min", "max"},
+		},
+	}
+}
+
+type ListConditionalFormatsRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+}
+
+var ListConditionalFormatsInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to inspect",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name"},
+}
+
+type AddConditionalFormatRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	ConditionalFormatRuleFields
+	Index int64 `json:"index"`
+}
+
+var AddConditionalFormatInputSchema = func() *jsonschema.Schema {
+	properties := conditionalFormatRuleProperties()
+	properties["index"] = &jsonschema.Schema{
+		Type:        "integer",
+		Description: "Position of the new rule (0-based). Rules with lower indexes take precedence. Default: 0 (highest priority)",
+	}
+	return &jsonschema.Schema{
+		Type:       "object",
+		Properties: properties,
+		Required:   []string{"spreadsheet_name", "sheet_name", "ranges", "rule_type"},
+	}
+}()
+
+type UpdateConditionalFormatRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	Index           int64  `json:"index"`
+	ConditionalFormatRuleFields
+	NewIndex *int64 `json:"new_index"`
+}
+
+var UpdateConditionalFormatInputSchema = func() *jsonschema.Schema {
+	properties := conditionalFormatRuleProperties()
+	properties["index"] = &jsonschema.Schema{
+		Type:        "integer",
+		Description: "Index of the rule to update, as shown by google_sheets_list_conditional_formats",
+	}
+	properties["new_index"] = &jsonschema.Schema{
+		Type:        "integer",
+		Description: "Move the rule to this index. Can be used alone to only change the rule's priority",
+	}
+	return &jsonschema.Schema{
+		Type:       "object",
+		Properties: properties,
+		Required:   []string{"spreadsheet_name", "sheet_name", "index"},
+	}
+}()
+
+type DeleteConditionalFormatRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	Index           int64  `json:"index"`
+}
+
+var DeleteConditionalFormatInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to modify",
+		},
+		"index": {
+			Type:        "integer",
+			Description: "Index of the rule to delete, as shown by google_sheets_list_conditional_formats",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "index"},
+}
+
+// 16進数のカラーコード（#RRGGBB または #RGB）を Color に変換する
+func parseHexColor(hex string) (*sheets.Color, error) {
+	code := strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(code) == 3 {
+		code = string([]byte{code[0], code[0], code[1], code[1], code[2], code[2]})
+	}
+	if len(code) != 6 {
+		return nil, fmt.Errorf("invalid color: '%s'. Use a hex color such as '#FF0000'", hex)
+	}
+	rgb, err := strconv.ParseUint(code, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: '%s'. Use a hex color such as '#FF0000'", hex)
+	}
+	return &sheets.Color{
+		Red:   float64(rgb>>16&0xff) / 255,
+		Green: float64(rgb>>8&0xff) / 255,
+		Blue:  float64(rgb&0xff) / 255,
+		// 0 の成分も省略せずに送る
+		ForceSendFields: []string{"Red", "Green", "Blue"},
+	}, nil
+}
+
+// Color を 16進数のカラーコードに変換する
+func formatHexColor(color *sheets.Color) string {
+	if color == nil {
+		return ""
+	}
+	component := func(value float64) int {
+		return int(math.Round(math.Max(0, math.Min(1, value)) * 255))
+	}
+	return fmt.Sprintf("#%02X%02X%02X", component(color.Red), component(color.Green), component(color.Blue))
+}
+
+// ColorStyle と旧形式の Color のどちらかから色を取り出す
+func styleColor(style *sheets.ColorStyle, color *sheets.Color) string {
+	if style != nil {
+		if style.ThemeColor != "" {
+			return "theme:" + strings.ToLower(style.ThemeColor)
+		}
+		if style.RgbColor != nil {
+			return formatHexColor(style.RgbColor)
+		}
+	}
+	return formatHexColor(color)
+}
+
+// カラースケールの基準点を InterpolationPoint に変換する
+func buildInterpolationPoint(point *GradientPoint, defaultType, defaultValue string) (*sheets.InterpolationPoint, error) {
+	color, err := parseHexColor(point.Color)
+	if err != nil {
+		return nil, err
+	}
+	pointType, value := point.Type, point.Value
+	if pointType == "" {
+		pointType, value = defaultType, defaultValue
+	}
+	switch pointType {
+	case "min", "max":
+		value = ""
+	case "number", "percent", "percentile":
+		if value == "" {
+			return nil, fmt.Errorf("a '%s' gradient point needs a value", pointType)
+		}
+	default:
+		return nil, fmt.Errorf("invalid gradient point type: '%s'", pointType)
+	}
+	return &sheets.InterpolationPoint{
+		Color: color,
+		Type:  strings.ToUpper(pointType),
+		Value: value,
+	}, nil
+}
+
+// リクエストから条件付き書式ルールを作成する
+func buildConditionalFormatRule(sheetId int64, fields ConditionalFormatRuleFields) (*sheets.ConditionalFormatRule, error) {
+	if len(fields.Ranges) == 0 {
+		return nil, fmt.Errorf("ranges cannot be empty")
+	}
+	rule := &sheets.ConditionalFormatRule{}
+	for _, rangeStr := range fields.Ranges {
+		gridRange, err := gridRangeFromA1(sheetId, rangeStr)
+		if err != nil {
+			return nil, err
+		}
+		rule.Ranges = append(rule.Ranges, gridRange)
+	}
+
+	switch fields.RuleType {
+	case "boolean", "custom_formula":
+		condition := &sheets.BooleanCondition{Type: fields.Condition}
+		if fields.RuleType == "custom_formula" {
+			if len(fields.Values) != 1 {
+				return nil, fmt.Errorf("a 'custom_formula' rule needs exactly one formula in values")
+			}
+			condition.Type = "CUSTOM_FORMULA"
+		} else if fields.Condition == "" {
+			return nil, fmt.Errorf("condition must be specified for a 'boolean' rule")
+		}
+		for _, value := range fields.Values {
+			condition.Values = append(condition.Values, &sheets.ConditionValue{UserEnteredValue: value})
+		}
+		format, err := buildConditionalCellFormat(fields.Format)
+		if err != nil {
+			return nil, err
+		}
+		rule.BooleanRule = &sheets.BooleanRule{Condition: condition, Format: format}
+	case "gradient":
+		gradient := fields.Gradient
+		if gradient == nil || gradient.Min == nil || gradient.Max == nil {
+			return nil, fmt.Errorf("gradient.min and gradient.max must be specified for a 'gradient' rule")
+		}
+		gradientRule := &sheets.GradientRule{}
+		var err error
+		if gradientRule.Minpoint, err = buildInterpolationPoint(gradient.Min, "min", ""); err != nil {
+			return nil, err
+		}
+		if gradient.Mid != nil {
+			if gradientRule.Midpoint, err = buildInterpolationPoint(gradient.Mid, "percentile", "50"); err != nil {
+				return nil, err
+			}
+		}
+		if gradientRule.Maxpoint, err = buildInterpolationPoint(gradient.Max, "max", ""); err != nil {
+			return nil, err
+		}
+		rule.GradientRule = gradientRule
+	default:
+		return nil, fmt.Errorf("invalid rule type: '%s'", fields.RuleType)
+	}
+	return rule, nil
+}
+
+// 条件に一致したセルに適用する書式を作成する
+func buildConditionalCellFormat(style *ConditionalFormatStyle) (*sheets.CellFormat, error) {
+	if style == nil || *style == (ConditionalFormatStyle{}) {
+		return nil, fmt.Errorf("format must set at least one of background_color, text_color, bold, italic, strikethrough or underline")
+	}
+	format := &sheets.CellFormat{}
+	if style.BackgroundColor != "" {
+		color, err := parseHexColor(style.BackgroundColor)
+		if err != nil {
+			return nil, err
+		}
+		format.BackgroundColor = color
+	}
+	textFormat := &sheets.TextFormat{
+		Bold:          style.Bold,
+		Italic:        style.Italic,
+		Strikethrough: style.Strikethrough,
+		Underline:     style.Underline,
+	}
+	if style.TextColor != "" {
+		color, err := parseHexColor(style.TextColor)
+		if err != nil {
+			return nil, err
+		}
+		textFormat.ForegroundColor = color
+	}
+	if textFormat.ForegroundColor != nil || style.Bold || style.Italic || style.Strikethrough || style.Underline {
+		format.TextFormat = textFormat
+	}
+	return format, nil
+}
+
+// 条件付き書式ルールを説明する文字列
+func describeConditionalFormatRule(rule *sheets.ConditionalFormatRule) string {
+	ranges := make([]string, len(rule.Ranges))
+	for i, gridRange := range rule.Ranges {
+		ranges[i] = a1RangeFromGridRange(gridRange).String()
+		if ranges[i] == "" {
+			ranges[i] = "(whole sheet)"
+		}
+	}
+	description := "ranges: " + strings.Join(ranges, ", ")
+
+	switch {
+	case rule.BooleanRule != nil:
+		condition := rule.BooleanRule.Condition
+		if condition != nil {
+			values := make([]string, len(condition.Values))
+			for i, value := range condition.Values {
+				values[i] = value.UserEnteredValue
+				if value.RelativeDate != "" {
+					values[i] = value.RelativeDate
+				}
+			}
+			description += " | condition: " + condition.Type
+			if len(values) > 0 {
+				description += " [" + strings.Join(values, ", ") + "]"
+			}
+		}
+		description += " | format: " + describeConditionalCellFormat(rule.BooleanRule.Format)
+	case rule.GradientRule != nil:
+		var points []string
+		for _, point := range []*sheets.InterpolationPoint{rule.GradientRule.Minpoint, rule.GradientRule.Midpoint, rule.GradientRule.Maxpoint} {
+			if point == nil {
+				continue
+			}
+			text := strings.ToLower(point.Type)
+			if point.Value != "" {
+				text += " " + point.Value
+			}
+			points = append(points, text+" "+styleColor(point.ColorStyle, point.Color))
+		}
+		description += " | gradient: " + strings.Join(points, " → ")
+	}
+	return description
+}
+
+// 条件付き書式の書式を説明する文字列
+func describeConditionalCellFormat(format *sheets.CellFormat) string {
+	if format == nil {
+		return "none"
+	}
+	var parts []string
+	if color := styleColor(format.BackgroundColorStyle, format.BackgroundColor); color != "" {
+		parts = append(parts, "background "+color)
+	}
+	if text := format.TextFormat; text != nil {
+		if color := styleColor(text.ForegroundColorStyle, text.ForegroundColor); color != "" {
+			parts = append(parts, "text "+color)
+		}
+		for _, flag := range []struct {
+			set  bool
+			name string
+		}{{text.Bold, "bold"}, {text.Italic, "italic"}, {text.Strikethrough, "strikethrough"}, {text.Underline, "underline"}} {
+			if flag.set {
+				parts = append(parts, flag.name)
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// シートの ID と条件付き書式ルールの一覧を取得する
+func (gs *GoogleSheets) getConditionalFormatRules(ctx context.Context, spreadsheetId, sheetName string) (int64, []*sheets.ConditionalFormatRule, error) {
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).
+		Fields("sheets(properties(sheetId,title),conditionalFormats)").
+		Do()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get conditional format rules: %w", err)
+	}
+
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.Title == sheetName {
+			return sheet.Properties.SheetId, sheet.ConditionalFormats, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("sheet not found: '%s'. Please check the sheet name. Use google_sheets_list_sheets to see available sheets in this spreadsheet", sheetName)
+}
+
+// ルールの番号が範囲内か確認する
+func checkConditionalFormatIndex(index int64, rules []*sheets.ConditionalFormatRule, sheetName string) error {
+	if index < 0 || index >= int64(len(rules)) {
+		return fmt.Errorf("conditional format rule index %d is out of range: sheet '%s' has %d rules. Use google_sheets_list_conditional_formats to see them",
+			index, sheetName, len(rules))
+	}
+	return nil
+}
+
+func (gs *GoogleSheets) ListConditionalFormatsHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ListConditionalFormatsRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	_, rules, err := gs.getConditionalFormatRules(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	var result strings.Builder
+	if len(rules) == 0 {
+		result.WriteString(fmt.Sprintf("Sheet '%s' in spreadsheet '%s' has no conditional format rules.", request.SheetName, request.SpreadsheetName))
+	} else {
+		result.WriteString(fmt.Sprintf("Conditional format rules of sheet '%s' in spreadsheet '%s' (%d rules, lower indexes take precedence):\n\n",
+			request.SheetName, request.SpreadsheetName, len(rules)))
+		for i, rule := range rules {
+			result.WriteString(fmt.Sprintf("- [%d] %s\n", i, describeConditionalFormatRule(rule)))
+		}
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: result.String(),
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) AddConditionalFormatHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[AddConditionalFormatRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	sheetId, rules, err := gs.getConditionalFormatRules(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, err
+	}
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	if request.Ranges, err = gs.resolveRanges(ctx, spreadsheetId, request.SheetName, request.Ranges); err != nil {
+		return nil, err
+	}
+	if request.Index < 0 || request.Index > int64(len(rules)) {
+		return nil, fmt.Errorf("index %d is out of range: sheet '%s' has %d rules", request.Index, request.SheetName, len(rules))
+	}
+
+	rule, err := buildConditionalFormatRule(sheetId, request.ConditionalFormatRuleFields)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		AddConditionalFormatRule: &sheets.AddConditionalFormatRuleRequest{
+			Rule:            rule,
+			Index:           request.Index,
+			ForceSendFields: []string{"Index"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add conditional format rule: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Successfully added a conditional format rule at index %d of sheet '%s' in spreadsheet '%s':\n%s",
+					request.Index, request.SheetName, request.SpreadsheetName, describeConditionalFormatRule(rule)),
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) UpdateConditionalFormatHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[UpdateConditionalFormatRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	sheetId, rules, err := gs.getConditionalFormatRules(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, err
+	}
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	if request.Ranges, err = gs.resolveRanges(ctx, spreadsheetId, request.SheetName, request.Ranges); err != nil {
+		return nil, err
+	}
+	if err := checkConditionalFormatIndex(request.Index, rules, request.SheetName); err != nil {
+		return nil, err
+	}
+	previous := rules[request.Index]
+
+	// ルールの置き換えと移動は同時に指定できないため、別のリクエストにする
+	var requests []*sheets.Request
+	current := previous
+	if request.RuleType != "" {
+		rule, err := buildConditionalFormatRule(sheetId, request.ConditionalFormatRuleFields)
+		if err != nil {
+			return nil, err
+		}
+		current = rule
+		requests = append(requests, &sheets.Request{
+			UpdateConditionalFormatRule: &sheets.UpdateConditionalFormatRuleRequest{
+				SheetId:         sheetId,
+				Index:           request.Index,
+				Rule:            rule,
+				ForceSendFields: []string{"Index", "SheetId"},
+			},
+		})
+	}
+	if request.NewIndex != nil {
+		if err := checkConditionalFormatIndex(*request.NewIndex, rules, request.SheetName); err != nil {
+			return nil, err
+		}
+		requests = append(requests, &sheets.Request{
+			UpdateConditionalFormatRule: &sheets.UpdateConditionalFormatRuleRequest{
+				SheetId:         sheetId,
+				Index:           request.Index,
+				NewIndex:        *request.NewIndex,
+				ForceSendFields: []string{"Index", "NewIndex", "SheetId"},
+			},
+		})
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("specify the new rule (rule_type, ranges, ...) and/or new_index")
+	}
+	if err := gs.batchUpdateSheet(ctx, spreadsheetId, requests...); err != nil {
+		return nil, fmt.Errorf("failed to update conditional format rule: %w", err)
+	}
+
+	newIndex := request.Index
+	if request.NewIndex != nil {
+		newIndex = *request.NewIndex
+	}
+	message := fmt.Sprintf("Successfully updated the conditional format rule of sheet '%s' in spreadsheet '%s' (now at index %d):\n%s",
+		request.SheetName, request.SpreadsheetName, newIndex, describeConditionalFormatRule(current))
+	message += fmt.Sprintf("\n\nPrevious rule (index %d): %s\nTo undo this change, you can use the previous rule.",
+		request.Index, describeConditionalFormatRule(previous))
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) DeleteConditionalFormatHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[DeleteConditionalFormatRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	sheetId, rules, err := gs.getConditionalFormatRules(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConditionalFormatIndex(request.Index, rules, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		DeleteConditionalFormatRule: &sheets.DeleteConditionalFormatRuleRequest{
+			SheetId:         sheetId,
+			Index:           request.Index,
+			ForceSendFields: []string{"Index", "SheetId"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete conditional format rule: %w", err)
+	}
+
+	message := fmt.Sprintf("Successfully deleted conditional format rule %d of sheet '%s' in spreadsheet '%s'. Rules after it moved up by one.",
+		request.Index, request.SheetName, request.SpreadsheetName)
+	message += fmt.Sprintf("\n\nDeleted rule: %s\nTo undo this change, you can add the rule again at index %d.",
+		describeConditionalFormatRule(rules[request.Index]), request.Index)
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import "testing"
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "#FF0000", want: "#FF0000"},
+		{input: "57bb8a", want: "#57BB8A"},
+		{input: "#abc", want: "#AABBCC"},
+		{input: "#000000", want: "#000000"},
+		{input: "red", wantErr: true},
+		{input: "#12345G", wantErr: true},
+	}
+	for _, tt := range tests {
+		color, err := parseHexColor(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseHexColor(%q) should fail", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseHexColor(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got := formatHexColor(color); got != tt.want {
+			t.Errorf("parseHexColor(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestBuildConditionalFormatRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  ConditionalFormatRuleFields
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "boolean condition",
+			fields: ConditionalFormatRuleFields{
+				Ranges: []string{"D2:D100"}, RuleType: "boolean", Condition: "TEXT_EQ", Values: []string{"Done"},
+				Format: &ConditionalFormatStyle{BackgroundColor: "#B7E1CD", Strikethrough: true},
+			},
+			want: "ranges: D2:D100 | condition: TEXT_EQ [Done] | format: background #B7E1CD, strikethrough",
+		},
+		{
+			name: "custom formula",
+			fields: ConditionalFormatRuleFields{
+				Ranges: []string{"A2:F100"}, RuleType: "custom_formula", Values: []string{"=$E2<TODAY()"},
+				Format: &ConditionalFormatStyle{TextColor: "#CC0000", Bold: true},
+			},
+			want: "ranges: A2:F100 | condition: CUSTOM_FORMULA [=$E2<TODAY()] | format: text #CC0000, bold",
+		},
+		{
+			name: "gradient with default point types",
+			fields: ConditionalFormatRuleFields{
+				Ranges: []string{"C:C"}, RuleType: "gradient",
+				Gradient: &ConditionalFormatGradient{
+					Min: &GradientPoint{Color: "#FFFFFF"},
+					Mid: &GradientPoint{Color: "#FFD666"},
+					Max: &GradientPoint{Color: "#57BB8A", Type: "number", Value: "100"},
+				},
+			},
+			want: "ranges: C:C | gradient: min #FFFFFF → percentile 50 #FFD666 → number 100 #57BB8A",
+		},
+		{
+			name:    "boolean without condition",
+			fields:  ConditionalFormatRuleFields{Ranges: []string{"A1"}, RuleType: "boolean", Format: &ConditionalFormatStyle{Bold: true}},
+			wantErr: true,
+		},
+		{
+			name:    "boolean without format",
+			fields:  ConditionalFormatRuleFields{Ranges: []string{"A1"}, RuleType: "boolean", Condition: "BLANK"},
+			wantErr: true,
+		},
+		{
+			name:    "gradient without max",
+			fields:  ConditionalFormatRuleFields{Ranges: []string{"A1"}, RuleType: "gradient", Gradient: &ConditionalFormatGradient{Min: &GradientPoint{Color: "#FFFFFF"}}},
+			wantErr: true,
+		},
+		{
+			name:    "no ranges",
+			fields:  ConditionalFormatRuleFields{RuleType: "custom_formula", Values: []string{"=TRUE"}, Format: &ConditionalFormatStyle{Bold: true}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := buildConditionalFormatRule(0, tt.fields)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", describeConditionalFormatRule(rule))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := describeConditionalFormatRule(rule); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,407 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+const (
+	// プロファイリングに使うデフォルトの最大行数
+	defaultDescribeSampleRows = 1000
+	// 列ごとに表示するサンプル値の数
+	describeSampleValues = 3
+)
+
+type DescribeRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	SampleRows      int64  `json:"sample_rows"`
+}
+
+var DescribeInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to describe. Leave empty to describe all sheets",
+		},
+		"sample_rows": {
+			Type:        "integer",
+			Description: "Maximum number of rows per sheet to profile. Default: 1000",
+			Default:     json.RawMessage(`1000`),
+		},
+	},
+	Required: []string{"spreadsheet_name"},
+}
+
+// セルの値から推定した型
+const (
+	cellTypeEmpty   = "empty"
+	cellTypeNumber  = "number"
+	cellTypeDate    = "date"
+	cellTypeBoolean = "boolean"
+	cellTypeText    = "text"
+)
+
+// 日付として解釈する書式
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"2006/1/2",
+	"1/2/2006",
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+	"2006/1/2 15:04:05",
+	"1/2/2006 15:04:05",
+	time.RFC3339,
+}
+
+// 文字列を日付として解析する
+func parseDate(value string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// セルの値の型を推定する（UNFORMATTED_VALUE で取得した値を想定）
+func inferCellType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return cellTypeEmpty
+	case float64, int, int64:
+		return cellTypeNumber
+	case bool:
+		return cellTypeBoolean
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return cellTypeEmpty
+		}
+		if _, ok := parseDate(v); ok {
+			return cellTypeDate
+		}
+		return cellTypeText
+	}
+	return cellTypeText
+}
+
+// 列のプロファイル
+type columnProfile struct {
+	Column   string
+	Name     string
+	Type     string
+	Total    int
+	Nulls    int
+	Distinct int
+	Min      string
+	Max      string
+	Samples  []string
+}
+
+// シートのプロファイル
+type sheetProfile struct {
+	UsedRange string
+	Rows      int
+	HasHeader bool
+	Columns   []columnProfile
+}
+
+// 1行目がヘッダー行かどうかを推定する
+// すべての値が重複のない文字列で、2行目以降に文字列以外の値を含む列がある場合、
+// または1行目に空のセルがない場合にヘッダー行とみなす
+func detectHeader(values [][]interface{}, width int) bool {
+	if len(values) < 2 || len(values[0]) == 0 {
+		return false
+	}
+	seen := make(map[string]bool)
+	empty := width - len(values[0])
+	for _, cell := range values[0] {
+		switch inferCellType(cell) {
+		case cellTypeEmpty:
+			empty++
+			continue
+		case cellTypeText:
+		default:
+			return false
+		}
+		name := fmt.Sprintf("%v", cell)
+		if seen[name] {
+			return false
+		}
+		seen[name] = true
+	}
+	if empty == 0 {
+		return true
+	}
+	for col := 0; col < width; col++ {
+		for _, row := range values[1:] {
+			if col < len(row) {
+				if t := inferCellType(row[col]); t != cellTypeEmpty && t != cellTypeText {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// シートの値をプロファイリングする
+func profileSheet(values [][]interface{}) sheetProfile {
+	// 値が入っている範囲を求める
+	firstRow, lastRow, firstCol, lastCol := -1, -1, -1, -1
+	for i, row := range values {
+		for j, cell := range row {
+			if inferCellType(cell) == cellTypeEmpty {
+				continue
+			}
+			if firstRow < 0 {
+				firstRow = i
+			}
+			lastRow = i
+			if firstCol < 0 || j < firstCol {
+				firstCol = j
+			}
+			if j > lastCol {
+				lastCol = j
+			}
+		}
+	}
+	if firstRow < 0 {
+		return sheetProfile{}
+	}
+
+	// 使用範囲だけを切り出す
+	used := make([][]interface{}, 0, lastRow-firstRow+1)
+	for _, row := range values[firstRow : lastRow+1] {
+		trimmed := make([]interface{}, 0, lastCol-firstCol+1)
+		for j := firstCol; j <= lastCol; j++ {
+			if j < len(row) {
+				trimmed = append(trimmed, row[j])
+			} else {
+				trimmed = append(trimmed, nil)
+			}
+		}
+		used = append(used, trimmed)
+	}
+	width := lastCol - firstCol + 1
+
+	profile := sheetProfile{
+		UsedRange: a1Range{
+			startCol: int64(firstCol + 1), startRow: int64(firstRow + 1),
+			endCol: int64(lastCol + 1), endRow: int64(lastRow + 1),
+		}.String(),
+		Rows:      len(used),
+		HasHeader: detectHeader(used, width),
+	}
+	body := used
+	if profile.HasHeader {
+		body = used[1:]
+	}
+	for j := 0; j < width; j++ {
+		column := columnProfile{
+			Column: columnIndexToLetter(int64(firstCol + j + 1)),
+		}
+		if profile.HasHeader {
+			column.Name = fmt.Sprintf("%v", used[0][j])
+		}
+		profileColumn(&column, body, j)
+		profile.Columns = append(profile.Columns, column)
+	}
+	return profile
+}
+
+// 列の値の型・空の割合・値の種類数・最小値・最大値・サンプル値を求める
+func profileColumn(column *columnProfile, rows [][]interface{}, index int) {
+	typeCounts := make(map[string]int)
+	distinct := make(map[string]bool)
+	var (
+		minNum, maxNum   float64
+		minDate, maxDate time.Time
+		hasNum, hasDate  bool
+	)
+	for _, row := range rows {
+		column.Total++
+		cell := row[index]
+		cellType := inferCellType(cell)
+		if cellType == cellTypeEmpty {
+			column.Nulls++
+			continue
+		}
+		typeCounts[cellType]++
+		text := fmt.Sprintf("%v", cell)
+		if !distinct[text] && len(column.Samples) < describeSampleValues {
+			column.Samples = append(column.Samples, text)
+		}
+		distinct[text] = true
+
+		switch cellType {
+		case cellTypeNumber:
+			n, _ := strconv.ParseFloat(text, 64)
+			if !hasNum || n < minNum {
+				minNum = n
+			}
+			if !hasNum || n > maxNum {
+				maxNum = n
+			}
+			hasNum = true
+		case cellTypeDate:
+			d, _ := parseDate(text)
+			if !hasDate || d.Before(minDate) {
+				minDate = d
+			}
+			if !hasDate || d.After(maxDate) {
+				maxDate = d
+			}
+			hasDate = true
+		}
+	}
+	column.Distinct = len(distinct)
+
+	// 最も多い型を列の型とする（9割未満の場合は混在とみなす）
+	nonNull := column.Total - column.Nulls
+	if nonNull == 0 {
+		column.Type = cellTypeEmpty
+		return
+	}
+	types := make([]string, 0, len(typeCounts))
+	for t := range typeCounts {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if typeCounts[types[i]] != typeCounts[types[j]] {
+			return typeCounts[types[i]] > typeCounts[types[j]]
+		}
+		return types[i] < types[j]
+	})
+	column.Type = types[0]
+	if typeCounts[types[0]]*10 < nonNull*9 {
+		parts := make([]string, len(types))
+		for i, t := range types {
+			parts[i] = fmt.Sprintf("%s %d%%", t, typeCounts[t]*100/nonNull)
+		}
+		column.Type = "mixed (" + strings.Join(parts, ", ") + ")"
+	}
+	switch types[0] {
+	case cellTypeNumber:
+		column.Min = strconv.FormatFloat(minNum, 'f', -1, 64)
+		column.Max = strconv.FormatFloat(maxNum, 'f', -1, 64)
+	case cellTypeDate:
+		column.Min = minDate.Format("2006-01-02")
+		column.Max = maxDate.Format("2006-01-02")
+	}
+}
+
+func (gs *GoogleSheets) DescribeHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[DescribeRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシート名からスプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	sampleRows := request.SampleRows
+	if sampleRows <= 0 {
+		sampleRows = defaultDescribeSampleRows
+	}
+
+	// スプレッドシートの情報を取得
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).Fields("sheets.properties").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+
+	var targets []*sheets.SheetProperties
+	for _, sheet := range spreadsheet.Sheets {
+		if request.SheetName == "" || sheet.Properties.Title == request.SheetName {
+			targets = append(targets, sheet.Properties)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("sheet not found: '%s'. Please check the sheet name. Use google_sheets_list_sheets to see available sheets in this spreadsheet", request.SheetName)
+	}
+
+	// 結果を整形
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Description of spreadsheet '%s':\n", request.SpreadsheetName))
+
+	for _, properties := range targets {
+		result.WriteString(fmt.Sprintf("\n## Sheet '%s' (ID: %d)\n\n", properties.Title, properties.SheetId))
+		if properties.GridProperties != nil {
+			result.WriteString(fmt.Sprintf("Grid size: %d rows x %d columns\n",
+				properties.GridProperties.RowCount, properties.GridProperties.ColumnCount))
+		}
+
+		// 先頭から sampleRows 行を取得（日付は書式付き文字列、それ以外は書式なしの値）
+		resp, err := service.Spreadsheets.Values.Get(spreadsheetId, sheetRange(properties.Title, a1Range{startRow: 1, endRow: int64(sampleRows)}.String())).
+			ValueRenderOption("UNFORMATTED_VALUE").
+			DateTimeRenderOption("FORMATTED_STRING").
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get data of sheet '%s': %w", properties.Title, err)
+		}
+
+		profile := profileSheet(resp.Values)
+		if profile.Rows == 0 {
+			result.WriteString("Used range: none (sheet is empty)\n")
+			continue
+		}
+		usedRange := profile.UsedRange
+		if int64(len(resp.Values)) >= sampleRows {
+			usedRange += fmt.Sprintf(" (only the first %d rows were profiled; the sheet may contain more)", sampleRows)
+		}
+		result.WriteString(fmt.Sprintf("Used range: %s\n", usedRange))
+		if profile.HasHeader {
+			result.WriteString(fmt.Sprintf("Header row: detected (%d data rows)\n\n", profile.Rows-1))
+		} else {
+			result.WriteString(fmt.Sprintf("Header row: not detected (%d data rows)\n\n", profile.Rows))
+		}
+
+		result.WriteString("| Column | Name | Type | Nulls | Distinct | Min | Max | Samples |\n")
+		result.WriteString("|---|---|---|---|---|---|---|---|\n")
+		for _, column := range profile.Columns {
+			nullRatio := 0
+			if column.Total > 0 {
+				nullRatio = column.Nulls * 100 / column.Total
+			}
+			result.WriteString(fmt.Sprintf("| %s | %s | %s | %d%% | %d | %s | %s | %s |\n",
+				column.Column, column.Name, column.Type, nullRatio, column.Distinct,
+				column.Min, column.Max, strings.Join(column.Samples, ", ")))
+		}
+	}
+
+	// 合計数
+	result.WriteString(fmt.Sprintf("\nTotal: %d sheets described\n", len(targets)))
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: result.String(),
+			},
+		},
+	}, nil
+}
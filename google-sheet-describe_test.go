@@ -0,0 +1,107 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInferCellType(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, cellTypeEmpty},
+		{"", cellTypeEmpty},
+		{"  ", cellTypeEmpty},
+		{float64(12.5), cellTypeNumber},
+		{true, cellTypeBoolean},
+		{"2024-04-01", cellTypeDate},
+		{"2024/4/1", cellTypeDate},
+		{"4/1/2024 09:30:00", cellTypeDate},
+		{"East", cellTypeText},
+		{"123", cellTypeText},
+	}
+	for _, tt := range tests {
+		if got := inferCellType(tt.value); got != tt.want {
+			t.Errorf("inferCellType(%#v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestProfileSheet(t *testing.T) {
+	values := [][]interface{}{
+		{},
+		{nil, "Region", "Revenue", "Date", "Active"},
+		{nil, "East", float64(100), "2024-01-05", true},
+		{nil, "West", float64(250.5), "2024-03-01", false},
+		{nil, "East", nil, "2023-12-31", true},
+		{nil, "North", float64(-10), "", true},
+	}
+	profile := profileSheet(values)
+
+	if profile.UsedRange != "B2:E6" {
+		t.Errorf("UsedRange = %s, want B2:E6", profile.UsedRange)
+	}
+	if !profile.HasHeader {
+		t.Fatalf("HasHeader = false, want true")
+	}
+	if len(profile.Columns) != 4 {
+		t.Fatalf("len(Columns) = %d, want 4", len(profile.Columns))
+	}
+
+	region := profile.Columns[0]
+	if region.Column != "B" || region.Name != "Region" || region.Type != cellTypeText || region.Distinct != 3 {
+		t.Errorf("region = %+v", region)
+	}
+	if !slices.Equal(region.Samples, []string{"East", "West", "North"}) {
+		t.Errorf("region samples = %q", region.Samples)
+	}
+
+	revenue := profile.Columns[1]
+	if revenue.Type != cellTypeNumber || revenue.Nulls != 1 || revenue.Total != 4 || revenue.Min != "-10" || revenue.Max != "250.5" {
+		t.Errorf("revenue = %+v", revenue)
+	}
+
+	date := profile.Columns[2]
+	if date.Type != cellTypeDate || date.Min != "2023-12-31" || date.Max != "2024-03-01" {
+		t.Errorf("date = %+v", date)
+	}
+
+	active := profile.Columns[3]
+	if active.Type != cellTypeBoolean || active.Distinct != 2 {
+		t.Errorf("active = %+v", active)
+	}
+}
+
+func TestProfileSheetWithoutHeader(t *testing.T) {
+	values := [][]interface{}{
+		{float64(1), "a"},
+		{float64(2), "b"},
+	}
+	profile := profileSheet(values)
+	if profile.HasHeader {
+		t.Errorf("HasHeader = true, want false")
+	}
+	if profile.Columns[0].Type != cellTypeNumber || profile.Columns[0].Total != 2 {
+		t.Errorf("column A = %+v", profile.Columns[0])
+	}
+}
+
+func TestProfileSheetMixedTypes(t *testing.T) {
+	values := [][]interface{}{
+		{"Value"},
+		{float64(1)},
+		{"n/a"},
+		{float64(3)},
+	}
+	profile := profileSheet(values)
+	if got := profile.Columns[0].Type; got != "mixed (number 66%, text 33%)" {
+		t.Errorf("Type = %s", got)
+	}
+}
+
+func TestProfileSheetEmpty(t *testing.T) {
+	if profile := profileSheet([][]interface{}{{}, {""}}); profile.Rows != 0 {
+		t.Errorf("Rows = %d, want 0", profile.Rows)
+	}
+}
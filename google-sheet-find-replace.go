@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+// 一致したセルとして表示するデフォルトの最大件数
+const defaultFindMaxResults = 200
+
+type FindReplaceRequest struct {
+	SpreadsheetName  string `json:"spreadsheet_name"`
+	SheetName        string `json:"sheet_name"`
+	Range            string `json:"range"`
+	Scope            string `json:"scope"`
+	Find             string `json:"find"`
+	Replacement      string `json:"replacement"`
+	Regex            bool   `json:"regex"`
+	MatchCase        bool   `json:"match_case"`
+	EntireCell       bool   `json:"entire_cell"`
+	IncludeFormulas  bool   `json:"include_formulas"`
+	FindOnly         bool   `json:"find_only"`
+	MaxResults       int64  `json:"max_results"`
+	IgnoreProtection bool   `json:"ignore_protection"`
+}
+
+var FindReplaceInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to search. Required for the 'sheet' and 'range' scopes",
+		},
+		"range": {
+			Type:        "string",
+			Description: "Cell range in A1 notation to search when scope is 'range'. Examples: 'A1:C10', 'B:B'",
+		},
+		"scope": {
+			Type:        "string",
+			Description: "Where to search: 'range' (the given range of sheet_name), 'sheet' (all of sheet_name) or 'all_sheets'. Default: 'range' if range is given, 'sheet' if only sheet_name is given, otherwise 'all_sheets'",
+			Enum:        []any{"range", "sheet", "all_sheets"},
+		},
+		"find": {
+			Type:        "string",
+			Description: "Text (or regular expression if regex is true) to find",
+		},
+		"replacement": {
+			Type:        "string",
+			Description: "Replacement text. With regex, $1, $2, ... refer to capture groups. Ignored when find_only is true",
+		},
+		"regex": {
+			Type:        "boolean",
+			Description: "Treat find as a regular expression (RE2 syntax)",
+		},
+		"match_case": {
+			Type:        "boolean",
+			Description: "Match upper and lower case exactly",
+		},
+		"entire_cell": {
+			Type:        "boolean",
+			Description: "Only match cells whose whole content matches",
+		},
+		"include_formulas": {
+			Type:        "boolean",
+			Description: "Also search and replace inside formulas",
+		},
+		"find_only": {
+			Type:        "boolean",
+			Description: "Only list the matching cells with their values without modifying anything",
+		},
+		"max_results": {
+			Type:        "integer",
+			Description: "Maximum number of matching cells to list with find_only. When replacing, every matching cell is listed as previous data. Default: 200",
+			Default:     json.RawMessage(`200`),
+		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Write even if the target overlaps a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
+	},
+	Required: []string{"spreadsheet_name", "find"},
+}
+
+// 検索条件から正規表現を作成する（Google Sheets の検索と同じ RE2 構文）
+func newFindMatcher(find string, regex, matchCase, entireCell bool) (*regexp.Regexp, error) {
+	pattern := find
+	if !regex {
+		pattern = regexp.QuoteMeta(find)
+	}
+	if entireCell {
+		pattern = "^(?:" + pattern + ")$"
+	}
+	if !matchCase {
+		pattern = "(?i)" + pattern
+	}
+	matcher, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return matcher, nil
+}
+
+// 検索で一致したセル
+type findMatch struct {
+	sheet   string
+	cell    string
+	value   string
+	formula bool
+}
+
+// 値（表示値）と数式のグリッドから一致するセルを探す
+func findMatches(sheetName string, startCol, startRow int64, formatted, formulas [][]interface{}, matcher *regexp.Regexp, includeFormulas bool) []findMatch {
+	var matches []findMatch
+	for i, row := range formulas {
+		for j, cell := range row {
+			text := fmt.Sprintf("%v", cell)
+			formula := strings.HasPrefix(text, "=")
+			if formula {
+				if !includeFormulas {
+					continue
+				}
+			} else if i < len(formatted) && j < len(formatted[i]) {
+				text = fmt.Sprintf("%v", formatted[i][j])
+			}
+			if text == "" || !matcher.MatchString(text) {
+				continue
+			}
+			matches = append(matches, findMatch{
+				sheet:   sheetName,
+				cell:    a1Cell(startCol+int64(j), startRow+int64(i)).String(),
+				value:   text,
+				formula: formula,
+			})
+		}
+	}
+	return matches
+}
+
+func (gs *GoogleSheets) FindReplaceHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[FindReplaceRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	if request.Find == "" {
+		return nil, fmt.Errorf("find cannot be empty")
+	}
+
+	// 検索範囲を決定
+	scope := request.Scope
+	if scope == "" {
+		switch {
+		case request.Range != "":
+			scope = "range"
+		case request.SheetName != "":
+			scope = "sheet"
+		default:
+			scope = "all_sheets"
+		}
+	}
+	if scope != "all_sheets" && request.SheetName == "" {
+		return nil, fmt.Errorf("sheet_name must be specified for scope '%s'", scope)
+	}
+	if scope == "range" && request.Range == "" {
+		return nil, fmt.Errorf("range must be specified for scope 'range'")
+	}
+
+	// 名前付き範囲が指定された場合は A1 表記に解決する（シート全体を指す場合はシート単位で検索する）
+	rangeStr := ""
+	if scope == "range" {
+		if rangeStr, err = gs.resolveRange(ctx, spreadsheetId, request.SheetName, request.Range); err != nil {
+			return nil, err
+		}
+		if rangeStr == "" {
+			scope = "sheet"
+		}
+	}
+
+	maxResults := request.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultFindMaxResults
+	}
+
+	matcher, err := newFindMatcher(request.Find, request.Regex, request.MatchCase, request.EntireCell)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).Fields("sheets.properties").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+
+	var targets []*sheets.SheetProperties
+	for _, sheet := range spreadsheet.Sheets {
+		if scope == "all_sheets" || sheet.Properties.Title == request.SheetName {
+			targets = append(targets, sheet.Properties)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("sheet not found: '%s'. Please check the sheet name. Use google_sheets_list_sheets to see available sheets in this spreadsheet", request.SheetName)
+	}
+
+	// 一致するセルを探す（置換する場合は変更前のデータになる）
+	startCol, startRow := int64(1), int64(1)
+	var gridRange *sheets.GridRange
+	if scope == "range" {
+		gridRange, err = gridRangeFromA1(targets[0].SheetId, rangeStr)
+		if err != nil {
+			return nil, err
+		}
+		startCol, startRow = gridRange.StartColumnIndex+1, gridRange.StartRowIndex+1
+	}
+
+	var matches []findMatch
+	for _, properties := range targets {
+		readRange := quoteSheetName(properties.Title)
+		if scope == "range" {
+			readRange = sheetRange(properties.Title, rangeStr)
+		}
+		formatted, err := service.Spreadsheets.Values.Get(spreadsheetId, readRange).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get data of sheet '%s': %w", properties.Title, err)
+		}
+		formulas, err := service.Spreadsheets.Values.Get(spreadsheetId, readRange).ValueRenderOption("FORMULA").Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get formulas of sheet '%s': %w", properties.Title, err)
+		}
+		matches = append(matches, findMatches(properties.Title, startCol, startRow, formatted.Values, formulas.Values, matcher, request.IncludeFormulas)...)
+	}
+
+	var result strings.Builder
+	if request.FindOnly {
+		result.WriteString(fmt.Sprintf("Found %d matching cells for '%s' in spreadsheet '%s'", len(matches), request.Find, request.SpreadsheetName))
+	} else {
+		// 保護された範囲への書き込みを確認（一致したセルのみが書き換わる）
+		if !request.IgnoreProtection {
+			for _, properties := range targets {
+				var cells []string
+				for _, match := range matches {
+					if match.sheet == properties.Title {
+						cells = append(cells, match.cell)
+					}
+				}
+				if len(cells) == 0 {
+					continue
+				}
+				if err := gs.checkProtectedRanges(ctx, spreadsheetId, properties.Title, cells); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		// 置換を実行
+		findReplace := &sheets.FindReplaceRequest{
+			Find:            request.Find,
+			Replacement:     request.Replacement,
+			MatchCase:       request.MatchCase,
+			MatchEntireCell: request.EntireCell,
+			SearchByRegex:   request.Regex,
+			IncludeFormulas: request.IncludeFormulas,
+		}
+		switch scope {
+		case "range":
+			findReplace.Range = gridRange
+		case "sheet":
+			findReplace.SheetId = targets[0].SheetId
+			// シートIDが 0 の場合も送信する
+			findReplace.ForceSendFields = []string{"SheetId"}
+		default:
+			findReplace.AllSheets = true
+		}
+
+		resp, err := service.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+			Requests: []*sheets.Request{{FindReplace: findReplace}},
+		}).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find and replace: %w", err)
+		}
+
+		var changed *sheets.FindReplaceResponse
+		if len(resp.Replies) > 0 {
+			changed = resp.Replies[0].FindReplace
+		}
+		if changed == nil {
+			changed = &sheets.FindReplaceResponse{}
+		}
+		result.WriteString(fmt.Sprintf("Successfully replaced '%s' with '%s' in spreadsheet '%s': %d occurrences in %d cells (%d values, %d formulas) across %d rows and %d sheets",
+			request.Find, request.Replacement, request.SpreadsheetName,
+			changed.OccurrencesChanged, changed.ValuesChanged+changed.FormulasChanged, changed.ValuesChanged, changed.FormulasChanged,
+			changed.RowsChanged, changed.SheetsChanged))
+		// 事前に探したセルと実際に置換されたセルの数が違う場合は保存したデータが不完全な可能性がある
+		if predicted := int64(len(matches)); predicted != changed.ValuesChanged+changed.FormulasChanged {
+			result.WriteString(fmt.Sprintf("\n\nWarning: %d cells were expected to match but %d cells were changed, so the previous data below may be incomplete. Please check the result with google_sheets_read_data.",
+				predicted, changed.ValuesChanged+changed.FormulasChanged))
+		}
+		if len(matches) > 0 {
+			result.WriteString("\n\nPrevious values of the matching cells have been saved. To undo this change, you can use the previous data.")
+		}
+	}
+
+	if len(matches) > 0 {
+		if request.FindOnly {
+			result.WriteString(":\n\n")
+		} else {
+			result.WriteString("\n\nPrevious data details:\n\n")
+		}
+		result.WriteString("| Sheet | Cell | Value |\n")
+		result.WriteString("|---|---|---|\n")
+		for i, match := range matches {
+			// 置換した場合は元に戻せるように一致したセルをすべて表示する
+			if request.FindOnly && int64(i) >= maxResults {
+				result.WriteString(fmt.Sprintf("\n... and %d more cells\n", len(matches)-i))
+				break
+			}
+			value := match.value
+			if match.formula {
+				value = "`" + value + "`"
+			}
+			result.WriteString(fmt.Sprintf("| %s | %s | %s |\n", match.sheet, match.cell, value))
+		}
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: result.String(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestFindMatches(t *testing.T) {
+	formatted := [][]interface{}{
+		{"Widget", "widget pro", "1,000"},
+		{"Gadget", "", "Widget"},
+	}
+	formulas := [][]interface{}{
+		{"Widget", "widget pro", float64(1000)},
+		{"Gadget", `=CONCAT("Wid","get")`, "=B1"},
+	}
+
+	tests := []struct {
+		name            string
+		find            string
+		regex           bool
+		matchCase       bool
+		entireCell      bool
+		includeFormulas bool
+		want            []string
+	}{
+		{name: "case insensitive substring", find: "widget", want: []string{"B2", "C2"}},
+		{name: "match case", find: "Widget", matchCase: true, want: []string{"B2"}},
+		{name: "entire cell", find: "widget", entireCell: true, want: []string{"B2"}},
+		{name: "include formulas", find: "wid", includeFormulas: true, want: []string{"B2", "C2", "C3"}},
+		{name: "regex", find: `^[GW]\w+t$`, regex: true, want: []string{"B2", "B3"}},
+		{name: "formatted numbers", find: "1,000", want: []string{"D2"}},
+		{name: "special characters are literal", find: "wid.et", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := newFindMatcher(tt.find, tt.regex, tt.matchCase, tt.entireCell)
+			if err != nil {
+				t.Fatalf("newFindMatcher error: %v", err)
+			}
+			matches := findMatches("Sheet1", 2, 2, formatted, formulas, matcher, tt.includeFormulas)
+			var got []string
+			for _, match := range matches {
+				got = append(got, match.cell)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("cells = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("cells = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	if _, err := newFindMatcher("(", true, false, false); err == nil {
+		t.Errorf("newFindMatcher with invalid regex: expected error")
+	}
+}
+
+// 検索置換用の Google API の偽物（BatchUpdate で送られた FindReplace の JSON を記録する）
+func newFindReplaceTestGoogleSheets(t *testing.T, values [][]interface{}, changed *sheets.FindReplaceResponse) (*GoogleSheets, context.Context, *[]map[string]interface{}) {
+	t.Helper()
+	var sent []map[string]interface{}
+	gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1"}}})
+		case r.URL.Path == "/v4/spreadsheets/ss1":
+			writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1"}},
+				{Properties: &sheets.SheetProperties{SheetId: 4, Title: "Data"}},
+			}})
+		case r.URL.Path == "/v4/spreadsheets/ss1:batchUpdate":
+			var batch struct {
+				Requests []struct {
+					FindReplace map[string]interface{} `json:"findReplace"`
+				} `json:"requests"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				t.Fatal(err)
+			}
+			for _, request := range batch.Requests {
+				sent = append(sent, request.FindReplace)
+			}
+			writeTestJSON(t, w, &sheets.BatchUpdateSpreadsheetResponse{Replies: []*sheets.Response{{FindReplace: changed}}})
+		case strings.HasPrefix(r.URL.Path, "/v4/spreadsheets/ss1/values/"):
+			writeTestJSON(t, w, &sheets.ValueRange{Values: values})
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+	return gs, ctx, &sent
+}
+
+func TestFindReplaceHandlerScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    FindReplaceRequest
+		want       map[string]interface{}
+		wantErrMsg string
+	}{
+		{
+			name:    "range is given",
+			request: FindReplaceRequest{SheetName: "Data", Range: "B2:C3"},
+			want: map[string]interface{}{"range": map[string]interface{}{
+				"sheetId": float64(4), "startRowIndex": float64(1), "endRowIndex": float64(3), "startColumnIndex": float64(1), "endColumnIndex": float64(3),
+			}},
+		},
+		// シートIDが 0 でも省略されずに送信される
+		{name: "only sheet_name is given", request: FindReplaceRequest{SheetName: "Sheet1"}, want: map[string]interface{}{"sheetId": float64(0)}},
+		{name: "sheet given by id", request: FindReplaceRequest{SheetName: "4"}, want: map[string]interface{}{"sheetId": float64(4)}},
+		{name: "nothing is given", request: FindReplaceRequest{}, want: map[string]interface{}{"allSheets": true}},
+		{name: "explicit all_sheets", request: FindReplaceRequest{SheetName: "Sheet1", Scope: "all_sheets"}, want: map[string]interface{}{"allSheets": true}},
+		{name: "sheet scope without sheet_name", request: FindReplaceRequest{Scope: "sheet"}, wantErrMsg: "sheet_name must be specified"},
+		{name: "range scope without range", request: FindReplaceRequest{SheetName: "Sheet1", Scope: "range"}, wantErrMsg: "range must be specified"},
+		{name: "range on another sheet", request: FindReplaceRequest{SheetName: "Sheet1", Range: "Data!A1:B2"}, wantErrMsg: "refers to sheet 'Data'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs, ctx, sent := newFindReplaceTestGoogleSheets(t, [][]interface{}{{"old"}}, &sheets.FindReplaceResponse{ValuesChanged: 1, OccurrencesChanged: 1})
+			tt.request.SpreadsheetName = "Budget"
+			tt.request.Find = "old"
+			tt.request.Replacement = "new"
+
+			_, err := gs.FindReplaceHandler(ctx, nil, &mcp.CallToolParamsFor[FindReplaceRequest]{Arguments: tt.request})
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindReplaceHandler failed: %v", err)
+			}
+			if len(*sent) != 1 {
+				t.Fatalf("sent %d find and replace requests, want 1", len(*sent))
+			}
+			got := (*sent)[0]
+			for _, key := range []string{"range", "sheetId", "allSheets"} {
+				if !reflect.DeepEqual(got[key], tt.want[key]) {
+					t.Errorf("%s = %v, want %v", key, got[key], tt.want[key])
+				}
+			}
+		})
+	}
+}
+
+func TestFindReplaceHandlerListsEveryReplacedCell(t *testing.T) {
+	values := [][]interface{}{{"old"}, {"old"}, {"old"}}
+
+	// 置換した場合は max_results を超えても変更前の値をすべて返す
+	gs, ctx, _ := newFindReplaceTestGoogleSheets(t, values, &sheets.FindReplaceResponse{ValuesChanged: 3, OccurrencesChanged: 3})
+	result, err := gs.FindReplaceHandler(ctx, nil, &mcp.CallToolParamsFor[FindReplaceRequest]{Arguments: FindReplaceRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Find:            "old",
+		Replacement:     "new",
+		MaxResults:      1,
+	}})
+	if err != nil {
+		t.Fatalf("FindReplaceHandler failed: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, cell := range []string{"| Sheet1 | A1 | old |", "| Sheet1 | A3 | old |"} {
+		if !strings.Contains(text, cell) {
+			t.Errorf("result is missing %q:\n%s", cell, text)
+		}
+	}
+	if strings.Contains(text, "more cells") || strings.Contains(text, "Warning") {
+		t.Errorf("unexpected truncation or warning:\n%s", text)
+	}
+
+	// 検索のみの場合は max_results で打ち切る
+	result, err = gs.FindReplaceHandler(ctx, nil, &mcp.CallToolParamsFor[FindReplaceRequest]{Arguments: FindReplaceRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Find:            "old",
+		FindOnly:        true,
+		MaxResults:      1,
+	}})
+	if err != nil {
+		t.Fatalf("FindReplaceHandler with find_only failed: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "... and 2 more cells") {
+		t.Errorf("find_only result is not truncated:\n%s", text)
+	}
+}
+
+func TestFindReplaceHandlerWarnsOnCountMismatch(t *testing.T) {
+	gs, ctx, _ := newFindReplaceTestGoogleSheets(t, [][]interface{}{{"old"}, {"old"}}, &sheets.FindReplaceResponse{ValuesChanged: 3, OccurrencesChanged: 3})
+	result, err := gs.FindReplaceHandler(ctx, nil, &mcp.CallToolParamsFor[FindReplaceRequest]{Arguments: FindReplaceRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Find:            "old",
+		Replacement:     "new",
+	}})
+	if err != nil {
+		t.Fatalf("FindReplaceHandler failed: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "Warning: 2 cells were expected to match but 3 cells were changed") {
+		t.Errorf("count mismatch is not reported:\n%s", text)
+	}
+}
@@ -0,0 +1,499 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+// 1回の書き込みリクエストで送るセル数の上限
+const importChunkCells = 40000
+
+// インポート元の形式と、Drive にアップロードする場合の MIME タイプ
+var importFormats = map[string]string{
+	"csv":  "text/csv",
+	"tsv":  "text/tab-separated-values",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+type ImportRequest struct {
+	SpreadsheetName   string `json:"spreadsheet_name"`
+	SheetName         string `json:"sheet_name"`
+	FilePath          string `json:"file_path"`
+	Content           string `json:"content"`
+	Format            string `json:"format"`
+	Mode              string `json:"mode"`
+	AnchorCell        string `json:"anchor_cell"`
+	CreateSpreadsheet bool   `json:"create_spreadsheet"`
+	ValueInputOption  string `json:"value_input_option"`
+	IgnoreProtection  bool   `json:"ignore_protection"`
+}
+
+var ImportInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file to import into. With create_spreadsheet, the path of the new spreadsheet",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to write to. Created if it does not exist. Not used with create_spreadsheet",
+		},
+		"file_path": {
+			Type:        "string",
+			Description: "Local file to import, relative to the import directory (MCPGS_IMPORT_DIR). Either file_path or content is required",
+		},
+		"content": {
+			Type:        "string",
+			Description: "Inline CSV/TSV text to import. Either file_path or content is required",
+		},
+		"format": {
+			Type:        "string",
+			Description: "Format of the source data. Inferred from the file extension if omitted, 'csv' for inline content. xlsx requires create_spreadsheet",
+			Enum:        []any{"csv", "tsv", "xlsx"},
+		},
+		"mode": {
+			Type:        "string",
+			Description: "'replace' clears the sheet and writes from A1, 'append' adds rows after the existing data, 'anchor' writes with the top-left cell at anchor_cell",
+			Enum:        []any{"replace", "append", "anchor"},
+			Default:     json.RawMessage(`"replace"`),
+		},
+		"anchor_cell": {
+			Type:        "string",
+			Description: "Top-left cell for mode 'anchor'. Example: 'B5'",
+		},
+		"create_spreadsheet": {
+			Type:        "boolean",
+			Description: "Upload the file to Drive and convert it to a new Google Spreadsheet at spreadsheet_name instead of writing into an existing sheet",
+		},
+		"value_input_option": {
+			Type:        "string",
+			Description: "How fields are written. 'RAW' (default) stores every field as text, so a field such as '=IMPORTDATA(...)' is not run as a formula. 'USER_ENTERED' parses fields as if typed into the sheet (numbers, dates and formulas); use it only for trusted data",
+			Enum:        []any{"RAW", "USER_ENTERED"},
+			Default:     json.RawMessage(`"RAW"`),
+		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Write even if the target overlaps a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
+	},
+	Required: []string{"spreadsheet_name"},
+}
+
+// インポートディレクトリ内のファイルパスを解決する（ディレクトリ外のファイルは拒否する）
+func resolveImportPath(importDir, filePath string) (string, error) {
+	if importDir == "" {
+		return "", fmt.Errorf("import directory is not configured. Set MCPGS_IMPORT_DIR to import local files")
+	}
+	dir, err := filepath.Abs(importDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve import directory: %w", err)
+	}
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve import directory: %w", err)
+	}
+
+	target := filePath
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(dir, target)
+	}
+	// シンボリックリンクを解決してからディレクトリ内かどうかを判定する
+	target, err = filepath.EvalSymlinks(target)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve file path: %w", err)
+	}
+	rel, err := filepath.Rel(dir, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid file path: '%s' is outside the import directory", filePath)
+	}
+	return target, nil
+}
+
+// CSV/TSV を2次元配列に変換する
+func parseDelimited(data []byte, format string) ([][]interface{}, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	reader := csv.NewReader(bytes.NewReader(data))
+	if format == "tsv" {
+		reader.Comma = '\t'
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var values [][]interface{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", format, err)
+		}
+		row := make([]interface{}, len(record))
+		for i, field := range record {
+			row[i] = field
+		}
+		values = append(values, row)
+	}
+	return values, nil
+}
+
+// 行数・列数が足りない場合はシートを拡張する
+func (gs *GoogleSheets) ensureGridSize(ctx context.Context, spreadsheetId string, properties *sheets.SheetProperties, rowCount, columnCount int64) error {
+	var requests []*sheets.Request
+	if grid := properties.GridProperties; grid != nil {
+		if rowCount > grid.RowCount {
+			requests = append(requests, &sheets.Request{
+				AppendDimension: &sheets.AppendDimensionRequest{
+					SheetId:   properties.SheetId,
+					Dimension: "ROWS",
+					Length:    rowCount - grid.RowCount,
+				},
+			})
+		}
+		if columnCount > grid.ColumnCount {
+			requests = append(requests, &sheets.Request{
+				AppendDimension: &sheets.AppendDimensionRequest{
+					SheetId:   properties.SheetId,
+					Dimension: "COLUMNS",
+					Length:    columnCount - grid.ColumnCount,
+				},
+			})
+		}
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get sheets service: %w", err)
+	}
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{Requests: requests}).Do()
+	if err != nil {
+		return fmt.Errorf("failed to resize sheet: %w", err)
+	}
+	return nil
+}
+
+// シートのプロパティを取得する（存在しない場合は作成する）
+func (gs *GoogleSheets) getOrCreateSheet(ctx context.Context, spreadsheetId, sheetName string) (*sheets.SheetProperties, bool, error) {
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).Fields("sheets.properties").Do()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.Title == sheetName {
+			return sheet.Properties, false, nil
+		}
+	}
+
+	resp, err := service.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				AddSheet: &sheets.AddSheetRequest{
+					Properties: &sheets.SheetProperties{Title: sheetName},
+				},
+			},
+		},
+	}).Do()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to add sheet: %w", err)
+	}
+	return resp.Replies[0].AddSheet.Properties, true, nil
+}
+
+// ファイルを Drive にアップロードして新しいスプレッドシートに変換する
+func (gs *GoogleSheets) importAsNewSpreadsheet(ctx context.Context, request ImportRequest, data []byte, format string) (*mcp.CallToolResultFor[any], error) {
+	gd, err := NewGoogleDrive(gs.cfg, gs.auth)
+	if err != nil {
+		return nil, err
+	}
+	parentID, fileName, err := gd.getParentIDAndFileName(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get destination parent ID and file name: %w", err)
+	}
+
+	service, err := gs.auth.GetDriveService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drive service: %w", err)
+	}
+
+	// 同名のファイルがある場合は上書きせずにエラーにする
+	query := newDriveQuery().inParents(parentID).nameEquals(fileName).notTrashed().String()
+	existing, err := service.Files.List().
+		Q(query).
+		SupportsAllDrives(true).
+		IncludeItemsFromAllDrives(true).
+		Fields("files(id)").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	if len(existing.Files) > 0 {
+		return nil, fmt.Errorf("a file named '%s' already exists. Choose another spreadsheet_name", request.SpreadsheetName)
+	}
+
+	created, err := service.Files.Create(&drive.File{
+		Name:     fileName,
+		Parents:  []string{parentID},
+		MimeType: spreadsheetMimeType, // アップロード時に Google スプレッドシートに変換する
+	}).
+		Media(bytes.NewReader(data), googleapi.ContentType(importFormats[format])).
+		SupportsAllDrives(true).
+		Fields("id, name").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Successfully imported %s data (%d bytes) as new spreadsheet '%s' (ID: %s)",
+					format, len(data), request.SpreadsheetName, created.Id),
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) ImportHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ImportRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+
+	// インポート元のデータを読み込む
+	if (request.FilePath == "") == (request.Content == "") {
+		return nil, fmt.Errorf("specify either file_path or content")
+	}
+	format := request.Format
+	var data []byte
+	if request.FilePath != "" {
+		filePath, err := resolveImportPath(gs.cfg.ImportDir, request.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		data, err = os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(filePath)), ".")
+		}
+	} else {
+		data = []byte(request.Content)
+		if format == "" {
+			format = "csv"
+		}
+	}
+	if _, ok := importFormats[format]; !ok {
+		return nil, fmt.Errorf("unsupported format: '%s'. Supported formats: csv, tsv, xlsx", format)
+	}
+
+	if request.CreateSpreadsheet {
+		return gs.importAsNewSpreadsheet(ctx, request, data, format)
+	}
+	if format == "xlsx" {
+		return nil, fmt.Errorf("xlsx files can only be imported as a new spreadsheet. Set create_spreadsheet to true")
+	}
+	if request.SheetName == "" {
+		return nil, fmt.Errorf("sheet_name must be specified")
+	}
+
+	values, err := parseDelimited(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no data to import")
+	}
+	width := 0
+	for _, row := range values {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	// 書き込み開始位置を決定
+	var startCol, startRow int64 = 1, 1
+	mode := request.Mode
+	if mode == "" {
+		mode = "replace"
+	}
+	switch mode {
+	case "replace", "append":
+	case "anchor":
+		if request.AnchorCell == "" {
+			return nil, fmt.Errorf("anchor_cell must be specified for mode 'anchor'")
+		}
+		anchor, err := parseA1Range(request.AnchorCell)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse anchor_cell: %w", err)
+		}
+		startCol, startRow = anchor.start()
+	default:
+		return nil, fmt.Errorf("invalid mode: '%s'", request.Mode)
+	}
+
+	// ファイルの内容が数式として実行されないよう、デフォルトでは値をそのまま書き込む
+	valueInputOption := request.ValueInputOption
+	if valueInputOption == "" {
+		valueInputOption = "RAW"
+	}
+	if valueInputOption != "RAW" && valueInputOption != "USER_ENTERED" {
+		return nil, fmt.Errorf("invalid value_input_option: '%s'. Use 'RAW' or 'USER_ENTERED'", request.ValueInputOption)
+	}
+
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	sheetName := request.SheetName
+	properties, created, err := gs.getOrCreateSheet(ctx, spreadsheetId, sheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	// 保護された範囲への書き込みを確認（append は新しい行を挿入するため既存のセルを上書きしない）
+	if !request.IgnoreProtection && !created && mode != "append" {
+		target := ""
+		if mode == "anchor" {
+			target = a1Range{startCol: startCol, startRow: startRow, endCol: startCol + int64(width) - 1, endRow: startRow + int64(len(values)) - 1}.String()
+		}
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, sheetName, []string{target}); err != nil {
+			return nil, err
+		}
+	}
+
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	// 上書きする前のデータを取得（replace はシート全体、anchor は書き込む範囲のうちシートに収まる部分）
+	previousDetails := ""
+	if !created && mode != "append" {
+		origin, readRange := a1Cell(1, 1), quoteSheetName(sheetName)
+		if mode == "anchor" {
+			origin = a1Cell(startCol, startRow)
+			readRange = ""
+			if grid := properties.GridProperties; grid != nil && startRow <= grid.RowCount && startCol <= grid.ColumnCount {
+				readRange = sheetRange(sheetName, a1Range{
+					startCol: startCol,
+					startRow: startRow,
+					endCol:   min(startCol+int64(width)-1, grid.ColumnCount),
+					endRow:   min(startRow+int64(len(values))-1, grid.RowCount),
+				}.String())
+			}
+		}
+		if readRange != "" {
+			prevData, err := service.Spreadsheets.Values.Get(spreadsheetId, readRange).Do()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get previous data: %w", err)
+			}
+			if len(prevData.Values) > 0 {
+				previousDetails = "Previous data details:\n\n" + formatTableData(origin, prevData.Values)
+			}
+		}
+	}
+	// 途中で失敗した場合に元に戻せるよう、エラーにも変更前のデータを添える
+	withPreviousData := func(err error) error {
+		if previousDetails == "" {
+			return err
+		}
+		return fmt.Errorf("%w\nThe sheet may be partially changed. To undo this change, you can use the previous data.\n\n%s", err, previousDetails)
+	}
+
+	if mode == "replace" && !created {
+		// 既存の値をクリア
+		_, err = service.Spreadsheets.Values.Clear(spreadsheetId, quoteSheetName(sheetName), &sheets.ClearValuesRequest{}).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to clear sheet: %w", err)
+		}
+	}
+	if mode != "append" {
+		// 書き込み範囲がシートに収まるように拡張
+		err = gs.ensureGridSize(ctx, spreadsheetId, properties, startRow+int64(len(values))-1, startCol+int64(width)-1)
+		if err != nil {
+			return nil, withPreviousData(err)
+		}
+	}
+
+	// リクエストの上限を超えないように分割して書き込む
+	chunkRows := importChunkCells / width
+	if chunkRows < 1 {
+		chunkRows = 1
+	}
+	requestCount := 0
+	for offset := 0; offset < len(values); offset += chunkRows {
+		end := offset + chunkRows
+		if end > len(values) {
+			end = len(values)
+		}
+		chunk := values[offset:end]
+
+		service, err = gs.auth.GetSheetsService(ctx)
+		if err != nil {
+			return nil, withPreviousData(fmt.Errorf("failed to get sheets service: %w", err))
+		}
+		if mode == "append" {
+			_, err = service.Spreadsheets.Values.Append(spreadsheetId, quoteSheetName(sheetName), &sheets.ValueRange{Values: chunk}).
+				ValueInputOption(valueInputOption).
+				InsertDataOption("INSERT_ROWS").
+				Do()
+		} else {
+			fullRange := sheetRange(sheetName, a1Cell(startCol, startRow+int64(offset)).String())
+			_, err = service.Spreadsheets.Values.Update(spreadsheetId, fullRange, &sheets.ValueRange{
+				Range:  fullRange,
+				Values: chunk,
+			}).ValueInputOption(valueInputOption).Do()
+		}
+		if err != nil {
+			return nil, withPreviousData(fmt.Errorf("failed to write rows %d-%d: %w", offset+1, end, err))
+		}
+		requestCount++
+	}
+
+	// 成功メッセージを作成
+	message := fmt.Sprintf("Successfully imported %d rows x %d columns into sheet '%s' of spreadsheet '%s' (mode: %s, value input: %s, %d write requests)",
+		len(values), width, request.SheetName, request.SpreadsheetName, mode, valueInputOption, requestCount)
+	if created {
+		message += fmt.Sprintf("\nSheet '%s' did not exist and was created.", request.SheetName)
+	} else if mode == "replace" {
+		message += "\nExisting values in the sheet were cleared before the import."
+	}
+	if previousDetails != "" {
+		message += "\n\nPrevious data has been saved. To undo this change, you can use the previous data.\n\n" + previousDetails
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+)
+
+// インポート先のシートを返す Google API の偽物（values には値の取得・書き込みを処理する関数を渡す）
+func newImportTestGoogleSheets(t *testing.T, grid *sheets.GridProperties, values http.HandlerFunc) (*GoogleSheets, context.Context) {
+	t.Helper()
+	return newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1"}}})
+		case r.URL.Path == "/v4/spreadsheets/ss1":
+			writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1", GridProperties: grid}},
+			}})
+		case r.URL.Path == "/v4/spreadsheets/ss1:batchUpdate":
+			writeTestJSON(t, w, &sheets.BatchUpdateSpreadsheetResponse{})
+		case strings.HasPrefix(r.URL.Path, "/v4/spreadsheets/ss1/values/"):
+			values(w, r)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func TestImportHandlerReplaceFailureReturnsPreviousData(t *testing.T) {
+	cleared := false
+	gs, ctx := newImportTestGoogleSheets(t, &sheets.GridProperties{RowCount: 1000, ColumnCount: 26}, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v4/spreadsheets/ss1/values/Sheet1" && r.Method == http.MethodGet:
+			if cleared {
+				t.Errorf("previous data was read after the sheet was cleared")
+			}
+			writeTestJSON(t, w, &sheets.ValueRange{Values: [][]interface{}{{"id", "name"}, {"1", "old"}}})
+		case r.URL.Path == "/v4/spreadsheets/ss1/values/Sheet1:clear":
+			cleared = true
+			writeTestJSON(t, w, &sheets.ClearValuesResponse{})
+		case r.URL.Path == "/v4/spreadsheets/ss1/values/Sheet1!A1":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": 400, "message": "write failed"}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	})
+
+	_, err := gs.ImportHandler(ctx, nil, &mcp.CallToolParamsFor[ImportRequest]{Arguments: ImportRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Content:         "id,name\n2,new\n",
+	}})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !cleared {
+		t.Fatal("the sheet was not cleared")
+	}
+	if !strings.Contains(err.Error(), "Previous data details:") || !strings.Contains(err.Error(), "| **2** | 1 | old |") {
+		t.Errorf("error does not include the previous data: %v", err)
+	}
+}
+
+func TestImportHandlerAnchorSavesPreviousData(t *testing.T) {
+	var readRanges []string
+	gs, ctx := newImportTestGoogleSheets(t, &sheets.GridProperties{RowCount: 5, ColumnCount: 3}, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			readRanges = append(readRanges, strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/ss1/values/"))
+			writeTestJSON(t, w, &sheets.ValueRange{Values: [][]interface{}{{"b4", "c4"}}})
+		case http.MethodPut:
+			writeTestJSON(t, w, &sheets.UpdateValuesResponse{})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	})
+
+	result, err := gs.ImportHandler(ctx, nil, &mcp.CallToolParamsFor[ImportRequest]{Arguments: ImportRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Content:         "a,b,c\nd,e,f\ng,h,i\n",
+		Mode:            "anchor",
+		AnchorCell:      "B4",
+	}})
+	if err != nil {
+		t.Fatalf("ImportHandler failed: %v", err)
+	}
+	// 書き込む範囲のうちシートに収まる部分だけを読み取る
+	if len(readRanges) != 1 || readRanges[0] != "Sheet1!B4:C5" {
+		t.Errorf("previous data was read from %v, want [Sheet1!B4:C5]", readRanges)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "| **4** | b4 | c4 |") {
+		t.Errorf("previous data is missing:\n%s", text)
+	}
+}
+
+func TestResolveImportPath(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "import")
+	outside := filepath.Join(root, "outside")
+	for _, d := range []string{filepath.Join(dir, "sub"), outside} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{filepath.Join(dir, "a.csv"), filepath.Join(dir, "sub", "b.csv"), filepath.Join(outside, "secret.csv")} {
+		if err := os.WriteFile(f, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// ディレクトリ内からディレクトリ外を指すシンボリックリンク
+	if err := os.Symlink(filepath.Join(outside, "secret.csv"), filepath.Join(dir, "link.csv")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "linkdir")); err != nil {
+		t.Fatal(err)
+	}
+	resolvedDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		filePath string
+		want     string // 空の場合はエラーになる
+	}{
+		{"a.csv", filepath.Join(resolvedDir, "a.csv")},
+		{"sub/b.csv", filepath.Join(resolvedDir, "sub", "b.csv")},
+		{"sub/../a.csv", filepath.Join(resolvedDir, "a.csv")},
+		{filepath.Join(dir, "sub", "b.csv"), filepath.Join(resolvedDir, "sub", "b.csv")},
+		{"../outside/secret.csv", ""},
+		{"sub/../../outside/secret.csv", ""},
+		{filepath.Join(outside, "secret.csv"), ""},
+		{"link.csv", ""},
+		{"linkdir/secret.csv", ""},
+		{"missing.csv", ""},
+	}
+	for _, tt := range tests {
+		got, err := resolveImportPath(dir, tt.filePath)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("resolveImportPath(%q) = %q, want an error", tt.filePath, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("resolveImportPath(%q) returned error: %v", tt.filePath, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resolveImportPath(%q) = %q, want %q", tt.filePath, got, tt.want)
+		}
+	}
+
+	if _, err := resolveImportPath("", "a.csv"); err == nil {
+		t.Error("expected an error when the import directory is not configured")
+	}
+}
+
+func TestParseDelimited(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		want   [][]interface{}
+	}{
+		{
+			name:   "quoted fields",
+			data:   "name,note\n\"Smith, John\",\"say \"\"hi\"\"\"\n",
+			format: "csv",
+			want:   [][]interface{}{{"name", "note"}, {"Smith, John", `say "hi"`}},
+		},
+		{
+			name:   "embedded newline",
+			data:   "id,memo\n1,\"line1\nline2\"\n",
+			format: "csv",
+			want:   [][]interface{}{{"id", "memo"}, {"1", "line1\nline2"}},
+		},
+		{
+			name:   "ragged rows",
+			data:   "a,b,c\n1\n2,3\n",
+			format: "csv",
+			want:   [][]interface{}{{"a", "b", "c"}, {"1"}, {"2", "3"}},
+		},
+		{
+			name:   "tsv",
+			data:   "a\tb,c\n1\t2\n",
+			format: "tsv",
+			want:   [][]interface{}{{"a", "b,c"}, {"1", "2"}},
+		},
+		{
+			name:   "byte order mark",
+			data:   "\xef\xbb\xbfid,name\n",
+			format: "csv",
+			want:   [][]interface{}{{"id", "name"}},
+		},
+		{
+			name:   "empty",
+			data:   "",
+			format: "csv",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		got, err := parseDelimited([]byte(tt.data), tt.format)
+		if err != nil {
+			t.Errorf("%s: parseDelimited returned error: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseDelimited = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestImportHandlerValueInputOption(t *testing.T) {
+	tests := []struct {
+		option string
+		want   string
+	}{
+		{"", "RAW"},
+		{"RAW", "RAW"},
+		{"USER_ENTERED", "USER_ENTERED"},
+	}
+	for _, tt := range tests {
+		var got []string
+		gs, ctx := newImportTestGoogleSheets(t, &sheets.GridProperties{RowCount: 1000, ColumnCount: 26}, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet:
+				writeTestJSON(t, w, &sheets.ValueRange{})
+			case strings.HasSuffix(r.URL.Path, ":clear"):
+				writeTestJSON(t, w, &sheets.ClearValuesResponse{})
+			case r.Method == http.MethodPut:
+				got = append(got, r.URL.Query().Get("valueInputOption"))
+				writeTestJSON(t, w, &sheets.UpdateValuesResponse{})
+			default:
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+				http.NotFound(w, r)
+			}
+		})
+		_, err := gs.ImportHandler(ctx, nil, &mcp.CallToolParamsFor[ImportRequest]{Arguments: ImportRequest{
+			SpreadsheetName:  "Budget",
+			SheetName:        "Sheet1",
+			Content:          "=IMPORTDATA(\"https://example.com\"),+cmd\n",
+			ValueInputOption: tt.option,
+		}})
+		if err != nil {
+			t.Fatalf("value_input_option %q: ImportHandler failed: %v", tt.option, err)
+		}
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("value_input_option %q: sent %v, want [%s]", tt.option, got, tt.want)
+		}
+	}
+
+	gs, ctx := newImportTestGoogleSheets(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+	})
+	_, err := gs.ImportHandler(ctx, nil, &mcp.CallToolParamsFor[ImportRequest]{Arguments: ImportRequest{
+		SpreadsheetName:  "Budget",
+		SheetName:        "Sheet1",
+		Content:          "a\n",
+		ValueInputOption: "FORMULA",
+	}})
+	if err == nil {
+		t.Error("expected an error for an invalid value_input_option")
+	}
+}
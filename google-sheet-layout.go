@@ -0,0 +1,663 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+// セル結合の種類
+var mergeTypes = map[string]string{
+	"all":     "MERGE_ALL",
+	"rows":    "MERGE_ROWS",
+	"columns": "MERGE_COLUMNS",
+}
+
+type MergeCellsRequest struct {
+	SpreadsheetName  string `json:"spreadsheet_name"`
+	SheetName        string `json:"sheet_name"`
+	Range            string `json:"range"`
+	MergeType        string `json:"merge_type"`
+	IgnoreProtection bool   `json:"ignore_protection"`
+}
+
+var MergeCellsInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to modify",
+		},
+		"range": {
+			Type:        "string",
+			Description: "Cell range in A1 notation to merge. Example: 'A1:D1'",
+		},
+		"merge_type": {
+			Type:        "string",
+			Description: "'all' merges the range into one cell, 'rows' merges each row, 'columns' merges each column. Default: 'all'",
+			Enum:        []any{"all", "rows", "columns"},
+		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Merge even if the range overlaps a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "range"},
+}
+
+type UnmergeCellsRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	Range           string `json:"range"`
+}
+
+var UnmergeCellsInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to modify",
+		},
+		"range": {
+			Type:        "string",
+			Description: "Cell range in A1 notation. All merged cells inside the range are unmerged. Example: 'A1:D10'",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "range"},
+}
+
+type FreezeRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	Rows            *int64 `json:"rows"`
+	Columns         *int64 `json:"columns"`
+}
+
+var FreezeInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to modify",
+		},
+		"rows": {
+			Type:        "integer",
+			Description: "Number of rows to freeze at the top. 0 unfreezes rows. If not specified, frozen rows are unchanged",
+		},
+		"columns": {
+			Type:        "integer",
+			Description: "Number of columns to freeze at the left. 0 unfreezes columns. If not specified, frozen columns are unchanged",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name"},
+}
+
+var dimensionSchema = &jsonschema.Schema{
+	Type:        "string",
+	Description: "Whether to change rows or columns",
+	Enum:        []any{"rows", "columns"},
+}
+
+type ResizeDimensionRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	Dimension       string `json:"dimension"`
+	Start           int64  `json:"start"`
+	Count           int64  `json:"count"`
+	PixelSize       int64  `json:"pixel_size"`
+}
+
+var ResizeDimensionInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to modify",
+		},
+		"dimension": dimensionSchema,
+		"start": {
+			Type:        "integer",
+			Description: "First row or column to resize (1-based). Example: 3 for row 3 or column C",
+		},
+		"count": {
+			Type:        "integer",
+			Description: "Number of rows or columns to resize",
+		},
+		"pixel_size": {
+			Type:        "integer",
+			Description: "Height of the rows or width of the columns in pixels. If not specified, they are auto-resized to fit their contents",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "dimension", "start", "count"},
+}
+
+type DimensionVisibilityRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	Dimension       string `json:"dimension"`
+	Start           int64  `json:"start"`
+	Count           int64  `json:"count"`
+}
+
+var DimensionVisibilityInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to modify",
+		},
+		"dimension": dimensionSchema,
+		"start": {
+			Type:        "integer",
+			Description: "First row or column (1-based). Example: 3 for row 3 or column C",
+		},
+		"count": {
+			Type:        "integer",
+			Description: "Number of rows or columns",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "dimension", "start", "count"},
+}
+
+type MoveDimensionRequest struct {
+	SpreadsheetName  string `json:"spreadsheet_name"`
+	SheetName        string `json:"sheet_name"`
+	Dimension        string `json:"dimension"`
+	Start            int64  `json:"start"`
+	Count            int64  `json:"count"`
+	Destination      int64  `json:"destination"`
+	IgnoreProtection bool   `json:"ignore_protection"`
+}
+
+var MoveDimensionInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to modify",
+		},
+		"dimension": dimensionSchema,
+		"start": {
+			Type:        "integer",
+			Description: "First row or column to move (1-based). Example: 6 for row 6 or column F",
+		},
+		"count": {
+			Type:        "integer",
+			Description: "Number of rows or columns to move",
+		},
+		"destination": {
+			Type:        "integer",
+			Description: "Row or column (1-based, position before the move) in front of which the block is placed. Example: to move column F before column C, use start 6 and destination 3. Use the last row/column + 1 to move to the end",
+		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Move even if the rows or columns overlap a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "dimension", "start", "count", "destination"},
+}
+
+// 行・列の範囲を DimensionRange に変換する（start は 1-based）
+func newDimensionRange(sheetId int64, dimension string, start, count int64) (*sheets.DimensionRange, error) {
+	var apiDimension string
+	switch strings.ToLower(dimension) {
+	case "rows":
+		apiDimension = "ROWS"
+	case "columns":
+		apiDimension = "COLUMNS"
+	default:
+		return nil, fmt.Errorf("invalid dimension: '%s'. Use 'rows' or 'columns'", dimension)
+	}
+	if start <= 0 {
+		return nil, fmt.Errorf("start must be a positive number")
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be a positive number")
+	}
+	return &sheets.DimensionRange{
+		SheetId:    sheetId,
+		Dimension:  apiDimension,
+		StartIndex: start - 1,
+		EndIndex:   start - 1 + count,
+	}, nil
+}
+
+// 行・列の範囲の説明（例: "rows 3-5", "columns C-E"）
+func describeDimensionRange(dimensionRange *sheets.DimensionRange) string {
+	first, last := dimensionRange.StartIndex+1, dimensionRange.EndIndex
+	if dimensionRange.Dimension == "COLUMNS" {
+		if first == last {
+			return "column " + columnIndexToLetter(first)
+		}
+		return fmt.Sprintf("columns %s-%s", columnIndexToLetter(first), columnIndexToLetter(last))
+	}
+	if first == last {
+		return fmt.Sprintf("row %d", first)
+	}
+	return fmt.Sprintf("rows %d-%d", first, last)
+}
+
+// シートに対して1件の BatchUpdate リクエストを実行する
+func (gs *GoogleSheets) batchUpdateSheet(ctx context.Context, spreadsheetId string, requests ...*sheets.Request) error {
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get sheets service: %w", err)
+	}
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{Requests: requests}).Do()
+	return err
+}
+
+func (gs *GoogleSheets) MergeCellsHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[MergeCellsRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	mergeType := request.MergeType
+	if mergeType == "" {
+		mergeType = "all"
+	}
+	apiMergeType, ok := mergeTypes[mergeType]
+	if !ok {
+		return nil, fmt.Errorf("invalid merge type: '%s'. Use 'all', 'rows' or 'columns'", request.MergeType)
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, request.SheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+	target, err := parseA1Range(rangeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// 保護された範囲への書き込みを確認
+	if !request.IgnoreProtection {
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, request.SheetName, []string{rangeStr}); err != nil {
+			return nil, err
+		}
+	}
+
+	// 結合すると左上以外の値が失われるため、結合前のデータを取得
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+	prevData, err := service.Spreadsheets.Values.Get(spreadsheetId, sheetRange(request.SheetName, rangeStr)).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous data: %w", err)
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		MergeCells: &sheets.MergeCellsRequest{
+			Range:     target.gridRange(sheetId),
+			MergeType: apiMergeType,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge cells: %w", err)
+	}
+
+	message := fmt.Sprintf("Successfully merged %s (%s) in sheet '%s' of spreadsheet '%s'. Only the top-left value of each merged area is kept.",
+		request.Range, apiMergeType, request.SheetName, request.SpreadsheetName)
+	if len(prevData.Values) > 0 {
+		message += "\n\nPrevious data has been saved. To undo this change, unmerge the cells and use the previous data."
+		message += "\n\nPrevious data details:\n\n" + formatTableData(target, prevData.Values)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) UnmergeCellsHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[UnmergeCellsRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, request.SheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+	gridRange, err := gridRangeFromA1(sheetId, rangeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		UnmergeCells: &sheets.UnmergeCellsRequest{
+			Range: gridRange,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmerge cells: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Successfully unmerged all merged cells in %s of sheet '%s' in spreadsheet '%s'",
+					request.Range, request.SheetName, request.SpreadsheetName),
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) FreezeHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[FreezeRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	if request.Rows == nil && request.Columns == nil {
+		return nil, fmt.Errorf("rows or columns must be specified")
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+
+	// 指定された項目だけを更新する（0 も送信する）
+	gridProperties := &sheets.GridProperties{}
+	var fields, changes []string
+	if request.Rows != nil {
+		if *request.Rows < 0 {
+			return nil, fmt.Errorf("rows must not be negative")
+		}
+		gridProperties.FrozenRowCount = *request.Rows
+		gridProperties.ForceSendFields = append(gridProperties.ForceSendFields, "FrozenRowCount")
+		fields = append(fields, "gridProperties.frozenRowCount")
+		changes = append(changes, fmt.Sprintf("%d frozen rows", *request.Rows))
+	}
+	if request.Columns != nil {
+		if *request.Columns < 0 {
+			return nil, fmt.Errorf("columns must not be negative")
+		}
+		gridProperties.FrozenColumnCount = *request.Columns
+		gridProperties.ForceSendFields = append(gridProperties.ForceSendFields, "FrozenColumnCount")
+		fields = append(fields, "gridProperties.frozenColumnCount")
+		changes = append(changes, fmt.Sprintf("%d frozen columns", *request.Columns))
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+			Properties: &sheets.SheetProperties{
+				SheetId:        sheetId,
+				GridProperties: gridProperties,
+			},
+			Fields: strings.Join(fields, ","),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to freeze panes: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Successfully set %s in sheet '%s' of spreadsheet '%s'",
+					strings.Join(changes, " and "), request.SheetName, request.SpreadsheetName),
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) ResizeDimensionHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ResizeDimensionRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	if request.PixelSize < 0 {
+		return nil, fmt.Errorf("pixel size must not be negative")
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	dimensionRange, err := newDimensionRange(sheetId, request.Dimension, request.Start, request.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	// サイズが指定されていない場合は内容に合わせて自動調整する
+	var resizeRequest *sheets.Request
+	var result string
+	if request.PixelSize == 0 {
+		resizeRequest = &sheets.Request{
+			AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{
+				Dimensions: dimensionRange,
+			},
+		}
+		result = "auto-resized to fit their contents"
+	} else {
+		resizeRequest = &sheets.Request{
+			UpdateDimensionProperties: &sheets.UpdateDimensionPropertiesRequest{
+				Range: dimensionRange,
+				Properties: &sheets.DimensionProperties{
+					PixelSize: request.PixelSize,
+				},
+				Fields: "pixelSize",
+			},
+		}
+		result = fmt.Sprintf("resized to %d pixels", request.PixelSize)
+	}
+
+	if err := gs.batchUpdateSheet(ctx, spreadsheetId, resizeRequest); err != nil {
+		return nil, fmt.Errorf("failed to resize %s: %w", strings.ToLower(request.Dimension), err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Successfully %s %s in sheet '%s' of spreadsheet '%s'",
+					result, describeDimensionRange(dimensionRange), request.SheetName, request.SpreadsheetName),
+			},
+		},
+	}, nil
+}
+
+// 行・列の表示・非表示を切り替える
+func (gs *GoogleSheets) setDimensionHidden(ctx context.Context, request DimensionVisibilityRequest, hidden bool) (*mcp.CallToolResultFor[any], error) {
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	dimensionRange, err := newDimensionRange(sheetId, request.Dimension, request.Start, request.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		UpdateDimensionProperties: &sheets.UpdateDimensionPropertiesRequest{
+			Range: dimensionRange,
+			Properties: &sheets.DimensionProperties{
+				HiddenByUser:    hidden,
+				ForceSendFields: []string{"HiddenByUser"},
+			},
+			Fields: "hiddenByUser",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to change visibility of %s: %w", describeDimensionRange(dimensionRange), err)
+	}
+
+	action := "hid"
+	if !hidden {
+		action = "unhid"
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Successfully %s %s in sheet '%s' of spreadsheet '%s'",
+					action, describeDimensionRange(dimensionRange), request.SheetName, request.SpreadsheetName),
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) HideDimensionHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[DimensionVisibilityRequest]) (*mcp.CallToolResultFor[any], error) {
+	return gs.setDimensionHidden(ctx, params.Arguments, true)
+}
+
+func (gs *GoogleSheets) UnhideDimensionHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[DimensionVisibilityRequest]) (*mcp.CallToolResultFor[any], error) {
+	return gs.setDimensionHidden(ctx, params.Arguments, false)
+}
+
+func (gs *GoogleSheets) MoveDimensionHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[MoveDimensionRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	source, err := newDimensionRange(sheetId, request.Dimension, request.Start, request.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	// 移動先は移動前の位置で指定する（移動する範囲の内側は指定できない）
+	if request.Destination <= 0 {
+		return nil, fmt.Errorf("destination must be a positive number")
+	}
+	if request.Destination >= request.Start && request.Destination <= request.Start+request.Count {
+		return nil, fmt.Errorf("destination %d is inside or right after the moved block, so nothing would move", request.Destination)
+	}
+
+	// 保護された範囲にかかる行・列の移動を確認
+	if !request.IgnoreProtection {
+		target := a1Range{startRow: request.Start, endRow: request.Start + request.Count - 1}
+		if source.Dimension == "COLUMNS" {
+			target = a1Range{startCol: request.Start, endCol: request.Start + request.Count - 1}
+		}
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, request.SheetName, []string{target.String()}); err != nil {
+			return nil, err
+		}
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		MoveDimension: &sheets.MoveDimensionRequest{
+			Source:           source,
+			DestinationIndex: request.Destination - 1,
+			ForceSendFields:  []string{"DestinationIndex"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to move %s: %w", describeDimensionRange(source), err)
+	}
+
+	// 移動後の位置（後ろに移動した場合は移動した分だけ前にずれる）
+	newStart := request.Destination
+	if request.Destination > request.Start {
+		newStart = request.Destination - request.Count
+	}
+	moved := &sheets.DimensionRange{
+		Dimension:  source.Dimension,
+		StartIndex: newStart - 1,
+		EndIndex:   newStart - 1 + request.Count,
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Successfully moved %s to %s in sheet '%s' of spreadsheet '%s'. Formatting moved with the data and references in formulas were updated.",
+					describeDimensionRange(source), describeDimensionRange(moved), request.SheetName, request.SpreadsheetName),
+			},
+		},
+	}, nil
+}
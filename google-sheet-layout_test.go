@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+)
+
+// 行・列の操作用の Google API の偽物（BatchUpdate で送られたリクエストを記録する）
+func newLayoutTestGoogleSheets(t *testing.T, sheet *sheets.Sheet, values [][]interface{}) (*GoogleSheets, context.Context, *[]*sheets.Request) {
+	t.Helper()
+	var requests []*sheets.Request
+	gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1"}}})
+		case r.URL.Path == "/v4/spreadsheets/ss1":
+			writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{sheet}})
+		case r.URL.Path == "/v4/spreadsheets/ss1:batchUpdate":
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var batch sheets.BatchUpdateSpreadsheetRequest
+			if err := json.Unmarshal(body, &batch); err != nil {
+				t.Fatal(err)
+			}
+			requests = append(requests, batch.Requests...)
+			writeTestJSON(t, w, &sheets.BatchUpdateSpreadsheetResponse{})
+		case strings.HasPrefix(r.URL.Path, "/v4/spreadsheets/ss1/values/"):
+			writeTestJSON(t, w, &sheets.ValueRange{Values: values})
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+	return gs, ctx, &requests
+}
+
+func TestMergeCellsHandler(t *testing.T) {
+	sheet := &sheets.Sheet{Properties: &sheets.SheetProperties{SheetId: 3, Title: "Sheet1"}}
+	gs, ctx, requests := newLayoutTestGoogleSheets(t, sheet, [][]interface{}{{"Title", "lost"}})
+
+	result, err := gs.MergeCellsHandler(ctx, nil, &mcp.CallToolParamsFor[MergeCellsRequest]{Arguments: MergeCellsRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Range:           "B2:C2",
+	}})
+	if err != nil {
+		t.Fatalf("MergeCellsHandler failed: %v", err)
+	}
+	if len(*requests) != 1 || (*requests)[0].MergeCells == nil {
+		t.Fatalf("unexpected requests: %+v", *requests)
+	}
+	merge := (*requests)[0].MergeCells
+	if merge.MergeType != "MERGE_ALL" || merge.Range.SheetId != 3 || merge.Range.StartRowIndex != 1 || merge.Range.EndColumnIndex != 3 {
+		t.Errorf("unexpected merge request: %+v %+v", merge, merge.Range)
+	}
+	// 失われる値を元に戻せるように結合前のデータを返す
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "| **2** | Title | lost |") {
+		t.Errorf("previous data is missing:\n%s", text)
+	}
+}
+
+func TestMergeCellsHandlerProtectedRange(t *testing.T) {
+	sheet := &sheets.Sheet{
+		Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1"},
+		ProtectedRanges: []*sheets.ProtectedRange{{
+			ProtectedRangeId:      7,
+			Range:                 &sheets.GridRange{SheetId: 0, StartRowIndex: 0, EndRowIndex: 1},
+			RequestingUserCanEdit: true,
+		}},
+	}
+	gs, ctx, requests := newLayoutTestGoogleSheets(t, sheet, nil)
+	gs.cfg.WarnProtectedRanges = true
+
+	_, err := gs.MergeCellsHandler(ctx, nil, &mcp.CallToolParamsFor[MergeCellsRequest]{Arguments: MergeCellsRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Range:           "A1:C1",
+	}})
+	if err == nil || !strings.Contains(err.Error(), "ignore_protection") {
+		t.Fatalf("expected a protected range error, got %v", err)
+	}
+	if len(*requests) != 0 {
+		t.Errorf("cells were merged despite the protected range: %+v", *requests)
+	}
+
+	_, err = gs.MergeCellsHandler(ctx, nil, &mcp.CallToolParamsFor[MergeCellsRequest]{Arguments: MergeCellsRequest{
+		SpreadsheetName:  "Budget",
+		SheetName:        "Sheet1",
+		Range:            "A1:C1",
+		IgnoreProtection: true,
+	}})
+	if err != nil {
+		t.Fatalf("MergeCellsHandler with ignore_protection failed: %v", err)
+	}
+	if len(*requests) != 1 {
+		t.Errorf("sent %d requests, want 1", len(*requests))
+	}
+}
+
+func TestNewDimensionRange(t *testing.T) {
+	tests := []struct {
+		dimension  string
+		start      int64
+		count      int64
+		wantDim    string
+		wantStart  int64
+		wantEnd    int64
+		wantErrMsg string
+	}{
+		{dimension: "rows", start: 3, count: 2, wantDim: "ROWS", wantStart: 2, wantEnd: 4},
+		{dimension: "Columns", start: 1, count: 1, wantDim: "COLUMNS", wantStart: 0, wantEnd: 1},
+		{dimension: "cells", start: 1, count: 1, wantErrMsg: "invalid dimension"},
+		{dimension: "rows", start: 0, count: 1, wantErrMsg: "start must be a positive number"},
+		{dimension: "rows", start: 1, count: 0, wantErrMsg: "count must be a positive number"},
+	}
+	for _, tt := range tests {
+		got, err := newDimensionRange(5, tt.dimension, tt.start, tt.count)
+		if tt.wantErrMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+				t.Errorf("newDimensionRange(%q, %d, %d) error = %v, want %q", tt.dimension, tt.start, tt.count, err, tt.wantErrMsg)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("newDimensionRange(%q, %d, %d) returned error: %v", tt.dimension, tt.start, tt.count, err)
+			continue
+		}
+		if got.SheetId != 5 || got.Dimension != tt.wantDim || got.StartIndex != tt.wantStart || got.EndIndex != tt.wantEnd {
+			t.Errorf("newDimensionRange(%q, %d, %d) = %+v", tt.dimension, tt.start, tt.count, got)
+		}
+	}
+}
+
+func TestDescribeDimensionRange(t *testing.T) {
+	tests := []struct {
+		dimensionRange *sheets.DimensionRange
+		want           string
+	}{
+		{&sheets.DimensionRange{Dimension: "ROWS", StartIndex: 2, EndIndex: 5}, "rows 3-5"},
+		{&sheets.DimensionRange{Dimension: "ROWS", StartIndex: 0, EndIndex: 1}, "row 1"},
+		{&sheets.DimensionRange{Dimension: "COLUMNS", StartIndex: 2, EndIndex: 5}, "columns C-E"},
+		{&sheets.DimensionRange{Dimension: "COLUMNS", StartIndex: 26, EndIndex: 27}, "column AA"},
+	}
+	for _, tt := range tests {
+		if got := describeDimensionRange(tt.dimensionRange); got != tt.want {
+			t.Errorf("describeDimensionRange(%+v) = %q, want %q", tt.dimensionRange, got, tt.want)
+		}
+	}
+}
+
+func TestMoveDimensionHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		dimension       string
+		start           int64
+		count           int64
+		destination     int64
+		wantDestination int64
+		wantMoved       string
+		wantErrMsg      string
+	}{
+		// 後ろへの移動は移動した分だけ前にずれる
+		{name: "forward", dimension: "rows", start: 2, count: 2, destination: 6, wantDestination: 5, wantMoved: "moved rows 2-3 to rows 4-5"},
+		{name: "backward", dimension: "rows", start: 5, count: 2, destination: 1, wantDestination: 0, wantMoved: "moved rows 5-6 to rows 1-2"},
+		{name: "move to end", dimension: "columns", start: 1, count: 1, destination: 11, wantDestination: 10, wantMoved: "moved column A to column J"},
+		{name: "zero destination", dimension: "rows", start: 2, count: 2, destination: 0, wantErrMsg: "destination must be a positive number"},
+		{name: "inside the block", dimension: "rows", start: 2, count: 2, destination: 3, wantErrMsg: "inside or right after"},
+		{name: "right after the block", dimension: "rows", start: 2, count: 2, destination: 4, wantErrMsg: "inside or right after"},
+		{name: "same position", dimension: "columns", start: 2, count: 1, destination: 2, wantErrMsg: "inside or right after"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet := &sheets.Sheet{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1"}}
+			gs, ctx, requests := newLayoutTestGoogleSheets(t, sheet, nil)
+
+			result, err := gs.MoveDimensionHandler(ctx, nil, &mcp.CallToolParamsFor[MoveDimensionRequest]{Arguments: MoveDimensionRequest{
+				SpreadsheetName: "Budget",
+				SheetName:       "Sheet1",
+				Dimension:       tt.dimension,
+				Start:           tt.start,
+				Count:           tt.count,
+				Destination:     tt.destination,
+			}})
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErrMsg)
+				}
+				if len(*requests) != 0 {
+					t.Errorf("sent requests for an invalid destination: %+v", *requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MoveDimensionHandler failed: %v", err)
+			}
+			if len(*requests) != 1 || (*requests)[0].MoveDimension == nil {
+				t.Fatalf("unexpected requests: %+v", *requests)
+			}
+			if got := (*requests)[0].MoveDimension.DestinationIndex; got != tt.wantDestination {
+				t.Errorf("DestinationIndex = %d, want %d", got, tt.wantDestination)
+			}
+			if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, tt.wantMoved) {
+				t.Errorf("result = %q, want it to contain %q", text, tt.wantMoved)
+			}
+		})
+	}
+}
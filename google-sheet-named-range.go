@@ -0,0 +1,428 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+// 名前付き範囲に使える名前（英数字とアンダースコア、数字以外で始まる）
+var namedRangeNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,249}$`)
+
+type ListNamedRangesRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+}
+
+var ListNamedRangesInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+	},
+	Required: []string{"spreadsheet_name"},
+}
+
+type CreateNamedRangeRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	Name            string `json:"name"`
+	Range           string `json:"range"`
+}
+
+var CreateNamedRangeInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab the range is on",
+		},
+		"name": {
+			Type:        "string",
+			Description: "Name of the named range: letters, numbers and underscores, not starting with a number and not looking like a cell reference. Example: 'Tax_Rates'",
+		},
+		"range": {
+			Type:        "string",
+			Description: "Cell range in A1 notation or an existing named range. Examples: 'A1:C20', 'B:B'",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "name", "range"},
+}
+
+type UpdateNamedRangeRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	Name            string `json:"name"`
+	NewName         string `json:"new_name"`
+	SheetName       string `json:"sheet_name"`
+	Range           string `json:"range"`
+}
+
+var UpdateNamedRangeInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"name": {
+			Type:        "string",
+			Description: "Current name of the named range",
+		},
+		"new_name": {
+			Type:        "string",
+			Description: "New name for the named range. Leave empty to keep the name",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Sheet/tab of the new range. Defaults to the sheet the named range is currently on",
+		},
+		"range": {
+			Type:        "string",
+			Description: "New cell range in A1 notation or a named range. Leave empty to keep the range",
+		},
+	},
+	Required: []string{"spreadsheet_name", "name"},
+}
+
+type DeleteNamedRangeRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	Name            string `json:"name"`
+}
+
+var DeleteNamedRangeInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"name": {
+			Type:        "string",
+			Description: "Name of the named range to delete. The cells themselves are not changed",
+		},
+	},
+	Required: []string{"spreadsheet_name", "name"},
+}
+
+// 名前付き範囲の名前が使えるか確認する
+func validateNamedRangeName(name string) error {
+	switch {
+	case !namedRangeNamePattern.MatchString(name):
+		return fmt.Errorf("invalid named range name: '%s'. Use 1-250 letters, numbers and underscores, not starting with a number", name)
+	case strings.EqualFold(name, "true") || strings.EqualFold(name, "false"):
+		return fmt.Errorf("invalid named range name: '%s'. TRUE and FALSE cannot be used as names", name)
+	case cellReferencePattern.MatchString(name):
+		return fmt.Errorf("invalid named range name: '%s'. Names cannot look like a cell reference", name)
+	}
+	return nil
+}
+
+// 名前付き範囲の一覧とシートIDからシートのプロパティへの対応を取得する
+func (gs *GoogleSheets) getNamedRanges(ctx context.Context, spreadsheetId string) ([]*sheets.NamedRange, map[int64]*sheets.SheetProperties, error) {
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).
+		Fields("namedRanges,sheets(properties(sheetId,title,gridProperties(rowCount,columnCount)))").
+		Do()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get named ranges: %w", err)
+	}
+
+	sheetProps := make(map[int64]*sheets.SheetProperties, len(spreadsheet.Sheets))
+	for _, sheet := range spreadsheet.Sheets {
+		sheetProps[sheet.Properties.SheetId] = sheet.Properties
+	}
+	return spreadsheet.NamedRanges, sheetProps, nil
+}
+
+// 名前から名前付き範囲を探す
+func findNamedRange(namedRanges []*sheets.NamedRange, name string) (*sheets.NamedRange, error) {
+	for _, namedRange := range namedRanges {
+		if namedRange.Name == name {
+			return namedRange, nil
+		}
+	}
+	return nil, fmt.Errorf("named range not found: '%s'. Use google_sheets_list_named_ranges to see available named ranges", name)
+}
+
+// 範囲の指定をシート名を含まない A1 表記の範囲に正規化する（名前付き範囲も解決する）
+// シート全体を指す名前付き範囲は空文字列になる
+func (gs *GoogleSheets) resolveRange(ctx context.Context, spreadsheetId, sheetName, rangeStr string) (string, error) {
+	if rangeStr == "" {
+		return "", nil
+	}
+	if target, err := parseA1Range(rangeStr); err == nil {
+		// シート名付きの範囲は操作対象のシートを指している必要がある
+		if target.sheet != "" && target.sheet != sheetName {
+			return "", fmt.Errorf("range '%s' refers to sheet '%s', not '%s'", rangeStr, target.sheet, sheetName)
+		}
+		return target.withoutSheet().String(), nil
+	}
+
+	namedRanges, sheetProps, err := gs.getNamedRanges(ctx, spreadsheetId)
+	if err != nil {
+		return "", err
+	}
+	namedRange, err := findNamedRange(namedRanges, rangeStr)
+	if err != nil {
+		return "", fmt.Errorf("invalid range: '%s' is neither A1 notation nor a named range. Use google_sheets_list_named_ranges to see available named ranges", rangeStr)
+	}
+	target := a1RangeOnSheet(namedRange.Range, sheetProps)
+	if target.sheet != sheetName {
+		return "", fmt.Errorf("named range '%s' is on sheet '%s', not '%s'", rangeStr, target.sheet, sheetName)
+	}
+	return target.withoutSheet().String(), nil
+}
+
+// 複数の範囲の指定をまとめて解決する
+func (gs *GoogleSheets) resolveRanges(ctx context.Context, spreadsheetId, sheetName string, ranges []string) ([]string, error) {
+	resolved := make([]string, len(ranges))
+	for i, rangeStr := range ranges {
+		var err error
+		if resolved[i], err = gs.resolveRange(ctx, spreadsheetId, sheetName, rangeStr); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// 名前付き範囲を説明する文字列
+func describeNamedRange(namedRange *sheets.NamedRange, sheetProps map[int64]*sheets.SheetProperties) string {
+	target := a1RangeOnSheet(namedRange.Range, sheetProps)
+	if target.withoutSheet().String() == "" {
+		return fmt.Sprintf("%s: %s (whole sheet)", namedRange.Name, target.sheet)
+	}
+	return fmt.Sprintf("%s: %s", namedRange.Name, target)
+}
+
+func (gs *GoogleSheets) ListNamedRangesHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ListNamedRangesRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	namedRanges, sheetProps, err := gs.getNamedRanges(ctx, spreadsheetId)
+	if err != nil {
+		return nil, err
+	}
+
+	var result strings.Builder
+	if len(namedRanges) == 0 {
+		result.WriteString(fmt.Sprintf("Spreadsheet '%s' has no named ranges.", request.SpreadsheetName))
+	} else {
+		result.WriteString(fmt.Sprintf("Named ranges in spreadsheet '%s' (%d):\n\n", request.SpreadsheetName, len(namedRanges)))
+		for _, namedRange := range namedRanges {
+			result.WriteString("- " + describeNamedRange(namedRange, sheetProps) + "\n")
+		}
+		result.WriteString("\nA named range can be passed as the range of other tools instead of A1 notation.")
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: result.String(),
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) CreateNamedRangeHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[CreateNamedRangeRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	if err := validateNamedRangeName(request.Name); err != nil {
+		return nil, err
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	// 名前付き範囲が指定された場合は A1 表記に解決する（シート名付きの範囲は sheet_name と一致する必要がある）
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, request.SheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+	gridRange := &sheets.GridRange{SheetId: sheetId, ForceSendFields: []string{"SheetId"}}
+	if rangeStr != "" {
+		if gridRange, err = gridRangeFromA1(sheetId, rangeStr); err != nil {
+			return nil, err
+		}
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		AddNamedRange: &sheets.AddNamedRangeRequest{
+			NamedRange: &sheets.NamedRange{
+				Name:  request.Name,
+				Range: gridRange,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create named range: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Successfully created named range '%s' for %s in spreadsheet '%s'",
+					request.Name, sheetRange(request.SheetName, rangeStr), request.SpreadsheetName),
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) UpdateNamedRangeHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[UpdateNamedRangeRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	if request.NewName == "" && request.Range == "" && request.SheetName == "" {
+		return nil, fmt.Errorf("specify new_name, range and/or sheet_name to update")
+	}
+
+	namedRanges, sheetProps, err := gs.getNamedRanges(ctx, spreadsheetId)
+	if err != nil {
+		return nil, err
+	}
+	current, err := findNamedRange(namedRanges, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	previous := describeNamedRange(current, sheetProps)
+
+	updated := &sheets.NamedRange{NamedRangeId: current.NamedRangeId, Name: current.Name, Range: current.Range}
+	var fields []string
+	if request.NewName != "" {
+		if err := validateNamedRangeName(request.NewName); err != nil {
+			return nil, err
+		}
+		updated.Name = request.NewName
+		fields = append(fields, "name")
+	}
+	if request.Range != "" || request.SheetName != "" {
+		// シートまたは範囲の一方のみ指定された場合は、もう一方は現在の値を使う
+		sheetId := current.Range.SheetId
+		sheetName := request.SheetName
+		if sheetName != "" {
+			if sheetId, err = gs.getSheetIdWithContext(ctx, spreadsheetId, sheetName); err != nil {
+				return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+			}
+		} else if properties, ok := sheetProps[sheetId]; ok {
+			sheetName = properties.Title
+		}
+		if request.Range == "" {
+			// 範囲を変えない場合は現在の GridRange をそのまま使う
+			moved := *current.Range
+			updated.Range = &moved
+			updated.Range.SheetId = sheetId
+		} else {
+			// シート名付きの範囲は対象のシートを指している必要がある
+			rangeStr, err := gs.resolveRange(ctx, spreadsheetId, sheetName, request.Range)
+			if err != nil {
+				return nil, err
+			}
+			updated.Range = &sheets.GridRange{SheetId: sheetId}
+			if rangeStr != "" {
+				if updated.Range, err = gridRangeFromA1(sheetId, rangeStr); err != nil {
+					return nil, err
+				}
+			}
+		}
+		updated.Range.ForceSendFields = []string{"SheetId"}
+		fields = append(fields, "range")
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		UpdateNamedRange: &sheets.UpdateNamedRangeRequest{
+			NamedRange: updated,
+			Fields:     strings.Join(fields, ","),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update named range: %w", err)
+	}
+
+	message := fmt.Sprintf("Successfully updated named range in spreadsheet '%s':\n- %s", request.SpreadsheetName, describeNamedRange(updated, sheetProps))
+	message += fmt.Sprintf("\n\nPrevious named range: %s\nTo undo this change, you can use the previous named range.", previous)
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) DeleteNamedRangeHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[DeleteNamedRangeRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	namedRanges, sheetProps, err := gs.getNamedRanges(ctx, spreadsheetId)
+	if err != nil {
+		return nil, err
+	}
+	namedRange, err := findNamedRange(namedRanges, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		DeleteNamedRange: &sheets.DeleteNamedRangeRequest{
+			NamedRangeId: namedRange.NamedRangeId,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete named range: %w", err)
+	}
+
+	message := fmt.Sprintf("Successfully deleted named range '%s' from spreadsheet '%s'. The cells were not changed.",
+		request.Name, request.SpreadsheetName)
+	message += fmt.Sprintf("\n\nDeleted named range: %s\nTo undo this change, you can create the named range again.",
+		describeNamedRange(namedRange, sheetProps))
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestValidateNamedRangeName(t *testing.T) {
+	valid := []string{"Tax_Rates", "_total", "Sales2024", "ABCD1", "Region"}
+	for _, name := range valid {
+		if err := validateNamedRangeName(name); err != nil {
+			t.Errorf("validateNamedRangeName(%q) failed: %v", name, err)
+		}
+	}
+	invalid := []string{"", "2024_Sales", "Tax Rates", "Tax-Rates", "true", "FALSE", "A1", "xfd100", "R1C1", "RC", "R2C"}
+	for _, name := range invalid {
+		if err := validateNamedRangeName(name); err == nil {
+			t.Errorf("validateNamedRangeName(%q) should fail", name)
+		}
+	}
+}
+
+// 範囲を扱う操作用の Google API の偽物（BatchUpdate で送られたリクエストを記録する）
+func newRangeTestGoogleSheets(t *testing.T, spreadsheet *sheets.Spreadsheet) (*GoogleSheets, context.Context, *[]*sheets.Request) {
+	t.Helper()
+	var requests []*sheets.Request
+	gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1"}}})
+		case "/v4/spreadsheets/ss1":
+			writeTestJSON(t, w, spreadsheet)
+		case "/v4/spreadsheets/ss1:batchUpdate":
+			var batch sheets.BatchUpdateSpreadsheetRequest
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				t.Fatal(err)
+			}
+			requests = append(requests, batch.Requests...)
+			// 保護範囲の追加には追加した保護範囲を返す
+			resp := &sheets.BatchUpdateSpreadsheetResponse{}
+			for _, request := range batch.Requests {
+				reply := &sheets.Response{}
+				if request.AddProtectedRange != nil {
+					reply.AddProtectedRange = &sheets.AddProtectedRangeResponse{ProtectedRange: request.AddProtectedRange.ProtectedRange}
+				}
+				resp.Replies = append(resp.Replies, reply)
+			}
+			writeTestJSON(t, w, resp)
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+	return gs, ctx, &requests
+}
+
+// Sheet1（ID 0）と Data（ID 4）を持ち、Data!A1:B2 に名前付き範囲 Prices があるスプレッドシート
+func newRangeTestSpreadsheet() *sheets.Spreadsheet {
+	return &sheets.Spreadsheet{
+		Sheets: []*sheets.Sheet{
+			{
+				Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1"},
+				ProtectedRanges: []*sheets.ProtectedRange{{
+					ProtectedRangeId: 9,
+					Range:            &sheets.GridRange{SheetId: 0, StartRowIndex: 0, EndRowIndex: 1},
+				}},
+			},
+			{Properties: &sheets.SheetProperties{SheetId: 4, Title: "Data"}},
+		},
+		NamedRanges: []*sheets.NamedRange{{
+			NamedRangeId: "nr1",
+			Name:         "Prices",
+			Range:        &sheets.GridRange{SheetId: 4, StartRowIndex: 0, EndRowIndex: 2, StartColumnIndex: 0, EndColumnIndex: 2},
+		}},
+	}
+}
+
+func TestCreateNamedRangeHandlerSheetPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		sheetName  string
+		rangeStr   string
+		wantSheet  int64
+		wantRow    int64
+		wantErrMsg string
+	}{
+		{name: "plain range", sheetName: "Data", rangeStr: "A3:B4", wantSheet: 4, wantRow: 2},
+		{name: "matching sheet prefix", sheetName: "Data", rangeStr: "Data!A3:B4", wantSheet: 4, wantRow: 2},
+		{name: "sheet given by id", sheetName: "4", rangeStr: "Data!A3:B4", wantSheet: 4, wantRow: 2},
+		{name: "other sheet prefix", sheetName: "Sheet1", rangeStr: "Data!A3:B4", wantErrMsg: "refers to sheet 'Data', not 'Sheet1'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs, ctx, requests := newRangeTestGoogleSheets(t, newRangeTestSpreadsheet())
+			_, err := gs.CreateNamedRangeHandler(ctx, nil, &mcp.CallToolParamsFor[CreateNamedRangeRequest]{Arguments: CreateNamedRangeRequest{
+				SpreadsheetName: "Budget",
+				SheetName:       tt.sheetName,
+				Name:            "Totals",
+				Range:           tt.rangeStr,
+			}})
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErrMsg)
+				}
+				if len(*requests) != 0 {
+					t.Errorf("sent requests for a range on another sheet: %+v", *requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateNamedRangeHandler failed: %v", err)
+			}
+			if len(*requests) != 1 || (*requests)[0].AddNamedRange == nil {
+				t.Fatalf("unexpected requests: %+v", *requests)
+			}
+			got := (*requests)[0].AddNamedRange.NamedRange.Range
+			if got.SheetId != tt.wantSheet || got.StartRowIndex != tt.wantRow {
+				t.Errorf("range = %+v, want sheet %d from row index %d", got, tt.wantSheet, tt.wantRow)
+			}
+		})
+	}
+}
+
+func TestUpdateNamedRangeHandlerSheetPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		sheetName  string
+		rangeStr   string
+		wantSheet  int64
+		wantErrMsg string
+	}{
+		// sheet_name を省略した場合は現在のシートに対して確認する
+		{name: "prefix matches current sheet", rangeStr: "Data!C1:C5", wantSheet: 4},
+		{name: "prefix differs from current sheet", rangeStr: "Sheet1!C1:C5", wantErrMsg: "refers to sheet 'Sheet1', not 'Data'"},
+		{name: "prefix matches new sheet", sheetName: "Sheet1", rangeStr: "Sheet1!C1:C5", wantSheet: 0},
+		{name: "prefix differs from new sheet", sheetName: "Sheet1", rangeStr: "Data!C1:C5", wantErrMsg: "refers to sheet 'Data', not 'Sheet1'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs, ctx, requests := newRangeTestGoogleSheets(t, newRangeTestSpreadsheet())
+			_, err := gs.UpdateNamedRangeHandler(ctx, nil, &mcp.CallToolParamsFor[UpdateNamedRangeRequest]{Arguments: UpdateNamedRangeRequest{
+				SpreadsheetName: "Budget",
+				Name:            "Prices",
+				SheetName:       tt.sheetName,
+				Range:           tt.rangeStr,
+			}})
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErrMsg)
+				}
+				if len(*requests) != 0 {
+					t.Errorf("sent requests for a range on another sheet: %+v", *requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateNamedRangeHandler failed: %v", err)
+			}
+			if len(*requests) != 1 || (*requests)[0].UpdateNamedRange == nil {
+				t.Fatalf("unexpected requests: %+v", *requests)
+			}
+			got := (*requests)[0].UpdateNamedRange.NamedRange.Range
+			if got.SheetId != tt.wantSheet || got.StartColumnIndex != 2 || got.EndRowIndex != 5 {
+				t.Errorf("range = %+v, want C1:C5 on sheet %d", got, tt.wantSheet)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+type SetNoteRequest struct {
+	SpreadsheetName  string `json:"spreadsheet_name"`
+	SheetName        string `json:"sheet_name"`
+	Range            string `json:"range"`
+	Note             string `json:"note"`
+	IgnoreProtection bool   `json:"ignore_protection"`
+}
+
+var SetNoteInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to modify",
+		},
+		"range": {
+			Type:        "string",
+			Description: "Cell or range in A1 notation, or a named range. Every cell in the range gets the same note. Examples: 'B2', 'A1:C1'",
+		},
+		"note": {
+			Type:        "string",
+			Description: "Note text. Leave empty to remove the notes from the range",
+		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Write even if the target overlaps a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "range"},
+}
+
+type GetNotesRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	Range           string `json:"range"`
+}
+
+var GetNotesInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to inspect",
+		},
+		"range": {
+			Type:        "string",
+			Description: "Cell range in A1 notation or a named range to inspect. Leave empty to inspect the whole sheet",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name"},
+}
+
+// メモが設定されたセル
+type cellNote struct {
+	row  int64
+	col  int64
+	note string
+}
+
+// 範囲内のセルのメモを取得する（範囲が空の場合はシート全体）
+func (gs *GoogleSheets) getCellNotes(ctx context.Context, spreadsheetId, sheetName, rangeStr string) ([]cellNote, error) {
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).
+		Ranges(sheetRange(sheetName, rangeStr)).
+		IncludeGridData(true).
+		Fields("sheets(data(startRow,startColumn,rowData(values(note))))").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+
+	var notes []cellNote
+	for _, sheet := range spreadsheet.Sheets {
+		for _, data := range sheet.Data {
+			for i, row := range data.RowData {
+				for j, cell := range row.Values {
+					if cell == nil || cell.Note == "" {
+						continue
+					}
+					notes = append(notes, cellNote{
+						row:  data.StartRow + int64(i) + 1,
+						col:  data.StartColumn + int64(j) + 1,
+						note: cell.Note,
+					})
+				}
+			}
+		}
+	}
+	return notes, nil
+}
+
+// 範囲内のすべてのセルに同じメモを設定する行データを作成する
+func buildNoteRows(r a1Range, note string) []*sheets.RowData {
+	rows := make([]*sheets.RowData, r.endRow-r.startRow+1)
+	for i := range rows {
+		values := make([]*sheets.CellData, r.endCol-r.startCol+1)
+		for j := range values {
+			values[j] = &sheets.CellData{Note: note}
+		}
+		rows[i] = &sheets.RowData{Values: values}
+	}
+	return rows
+}
+
+// メモの一覧を1行ずつ書き出す（複数行のメモは続きの行を字下げする）
+func writeCellNotes(builder *strings.Builder, notes []cellNote) {
+	for _, n := range notes {
+		note := strings.ReplaceAll(n.note, "\n", "\n  ")
+		builder.WriteString(fmt.Sprintf("- %s: %s\n", a1Cell(n.col, n.row), note))
+	}
+}
+
+func (gs *GoogleSheets) SetNoteHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[SetNoteRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, request.SheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+	var target a1Range
+	if rangeStr != "" {
+		if target, err = parseA1Range(rangeStr); err != nil {
+			return nil, err
+		}
+	}
+
+	updateCells := &sheets.UpdateCellsRequest{
+		Range:  target.gridRange(sheetId),
+		Fields: "note",
+	}
+	if request.Note != "" {
+		// 書き込むセルを決めるため、メモを設定する場合は範囲の端がすべて必要
+		if target.startCol == 0 || target.startRow == 0 || target.endCol == 0 || target.endRow == 0 {
+			return nil, fmt.Errorf("range '%s' has no explicit bounds. Specify cells such as 'A1:C10' to set a note", request.Range)
+		}
+		updateCells.Rows = buildNoteRows(target, request.Note)
+	}
+
+	// 保護された範囲への書き込みを確認
+	if !request.IgnoreProtection {
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, request.SheetName, []string{rangeStr}); err != nil {
+			return nil, err
+		}
+	}
+
+	// 元に戻せるように変更前のメモを取得
+	previous, err := gs.getCellNotes(ctx, spreadsheetId, request.SheetName, rangeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// 行データを指定しない場合は範囲内のメモが削除される
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{UpdateCells: updateCells})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set note: %w", err)
+	}
+
+	var message strings.Builder
+	if request.Note == "" {
+		message.WriteString(fmt.Sprintf("Successfully removed notes from %s in sheet '%s' of spreadsheet '%s'",
+			describeRange(request.Range), request.SheetName, request.SpreadsheetName))
+	} else {
+		message.WriteString(fmt.Sprintf("Successfully set the note on %s in sheet '%s' of spreadsheet '%s'",
+			describeRange(request.Range), request.SheetName, request.SpreadsheetName))
+	}
+	if len(previous) > 0 {
+		message.WriteString("\n\nPrevious notes:\n")
+		writeCellNotes(&message, previous)
+		message.WriteString("To undo this change, you can set the previous notes again.")
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message.String(),
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) GetNotesHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[GetNotesRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, request.SheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := gs.getCellNotes(ctx, spreadsheetId, request.SheetName, rangeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Notes in %s of sheet '%s' in spreadsheet '%s':\n\n",
+		describeRange(request.Range), request.SheetName, request.SpreadsheetName))
+	if len(notes) == 0 {
+		result.WriteString("No notes found.\n")
+	}
+	writeCellNotes(&result, notes)
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: result.String(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildNoteRows(t *testing.T) {
+	rows := buildNoteRows(a1Range{startCol: 2, startRow: 3, endCol: 4, endRow: 4}, "check")
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	for i, row := range rows {
+		if len(row.Values) != 3 {
+			t.Fatalf("row %d has %d cells, want 3", i, len(row.Values))
+		}
+		for _, cell := range row.Values {
+			if cell.Note != "check" {
+				t.Errorf("row %d: note = %q, want %q", i, cell.Note, "check")
+			}
+		}
+	}
+}
+
+func TestWriteCellNotes(t *testing.T) {
+	var builder strings.Builder
+	writeCellNotes(&builder, []cellNote{
+		{row: 2, col: 2, note: "total"},
+		{row: 10, col: 28, note: "line 1\nline 2"},
+	})
+	want := "- B2: total\n- AB10: line 1\n  line 2\n"
+	if got := builder.String(); got != want {
+		t.Errorf("writeCellNotes = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,584 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+type ListProtectedRangesRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+}
+
+var ListProtectedRangesInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to inspect. Leave empty to list the protected ranges of all sheets",
+		},
+	},
+	Required: []string{"spreadsheet_name"},
+}
+
+type ProtectRangeRequest struct {
+	SpreadsheetName string   `json:"spreadsheet_name"`
+	SheetName       string   `json:"sheet_name"`
+	Range           string   `json:"range"`
+	Description     string   `json:"description"`
+	WarningOnly     bool     `json:"warning_only"`
+	Editors         []string `json:"editors"`
+}
+
+var ProtectRangeInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to protect",
+		},
+		"range": {
+			Type:        "string",
+			Description: "Cell range in A1 notation or a named range to protect. Examples: 'A1:F1', '1:1'. Leave empty to protect the whole sheet",
+		},
+		"description": {
+			Type:        "string",
+			Description: "Why the range is protected. Example: 'Header row'",
+		},
+		"warning_only": {
+			Type:        "boolean",
+			Description: "Only show a warning when someone edits the range instead of blocking the edit. Cannot be combined with editors",
+		},
+		"editors": {
+			Type:        "array",
+			Description: "Email addresses of the users or groups who may edit the range. You always remain an editor. Default: only you",
+			Items:       &jsonschema.Schema{Type: "string"},
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name"},
+}
+
+type UpdateProtectedRangeRequest struct {
+	SpreadsheetName  string   `json:"spreadsheet_name"`
+	ProtectedRangeID int64    `json:"protected_range_id"`
+	SheetName        string   `json:"sheet_name"`
+	Range            string   `json:"range"`
+	Description      *string  `json:"description"`
+	WarningOnly      *bool    `json:"warning_only"`
+	Editors          []string `json:"editors"`
+}
+
+var UpdateProtectedRangeInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"protected_range_id": {
+			Type:        "integer",
+			Description: "ID of the protected range, as shown by google_sheets_list_protected_ranges",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Sheet/tab of the new range. Defaults to the sheet the protected range is currently on",
+		},
+		"range": {
+			Type:        "string",
+			Description: "New cell range in A1 notation or a named range. Leave empty to keep the range",
+		},
+		"description": {
+			Type:        "string",
+			Description: "New description. Omit to keep the description",
+		},
+		"warning_only": {
+			Type:        "boolean",
+			Description: "Switch between warning only (true) and blocking edits (false). Omit to keep the mode",
+		},
+		"editors": {
+			Type:        "array",
+			Description: "New list of editor email addresses, replacing the current list. Omit to keep the editors",
+			Items:       &jsonschema.Schema{Type: "string"},
+		},
+	},
+	Required: []string{"spreadsheet_name", "protected_range_id"},
+}
+
+type UnprotectRangeRequest struct {
+	SpreadsheetName  string `json:"spreadsheet_name"`
+	ProtectedRangeID int64  `json:"protected_range_id"`
+}
+
+var UnprotectRangeInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"protected_range_id": {
+			Type:        "integer",
+			Description: "ID of the protected range to remove, as shown by google_sheets_list_protected_ranges. The cells themselves are not changed",
+		},
+	},
+	Required: []string{"spreadsheet_name", "protected_range_id"},
+}
+
+// 保護された範囲の一覧とシートIDからシートのプロパティへの対応を取得する
+// 名前付き範囲で指定された保護範囲は、名前付き範囲の GridRange を Range に設定して返す
+func (gs *GoogleSheets) getProtectedRanges(ctx context.Context, spreadsheetId string) ([]*sheets.ProtectedRange, map[int64]*sheets.SheetProperties, error) {
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).
+		Fields("namedRanges,sheets(properties(sheetId,title,gridProperties(rowCount,columnCount)),protectedRanges)").
+		Do()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get protected ranges: %w", err)
+	}
+
+	namedRanges := make(map[string]*sheets.GridRange, len(spreadsheet.NamedRanges))
+	for _, namedRange := range spreadsheet.NamedRanges {
+		namedRanges[namedRange.NamedRangeId] = namedRange.Range
+	}
+	sheetProps := make(map[int64]*sheets.SheetProperties, len(spreadsheet.Sheets))
+	var protectedRanges []*sheets.ProtectedRange
+	for _, sheet := range spreadsheet.Sheets {
+		sheetProps[sheet.Properties.SheetId] = sheet.Properties
+		for _, protectedRange := range sheet.ProtectedRanges {
+			if protectedRange.Range == nil {
+				protectedRange.Range = namedRanges[protectedRange.NamedRangeId]
+			}
+			if protectedRange.Range == nil {
+				protectedRange.Range = &sheets.GridRange{SheetId: sheet.Properties.SheetId}
+			}
+			protectedRanges = append(protectedRanges, protectedRange)
+		}
+	}
+	return protectedRanges, sheetProps, nil
+}
+
+// IDから保護された範囲を探す
+func findProtectedRange(protectedRanges []*sheets.ProtectedRange, id int64) (*sheets.ProtectedRange, error) {
+	for _, protectedRange := range protectedRanges {
+		if protectedRange.ProtectedRangeId == id {
+			return protectedRange, nil
+		}
+	}
+	return nil, fmt.Errorf("protected range not found: %d. Use google_sheets_list_protected_ranges to see available protected ranges", id)
+}
+
+// 保護された範囲を説明する文字列
+func describeProtectedRange(protectedRange *sheets.ProtectedRange, sheetProps map[int64]*sheets.SheetProperties) string {
+	target := a1RangeOnSheet(protectedRange.Range, sheetProps)
+	description := fmt.Sprintf("id %d: %s", protectedRange.ProtectedRangeId, target)
+	if target.withoutSheet().String() == "" {
+		description += " (whole sheet)"
+	}
+	if protectedRange.NamedRangeId != "" {
+		description += " (named range)"
+	}
+	if protectedRange.Description != "" {
+		description += " | " + protectedRange.Description
+	}
+	if protectedRange.WarningOnly {
+		description += " | warning only"
+	} else if editors := protectedRange.Editors; editors != nil {
+		var names []string
+		names = append(names, editors.Users...)
+		names = append(names, editors.Groups...)
+		if editors.DomainUsersCanEdit {
+			names = append(names, "everyone in the domain")
+		}
+		if len(names) > 0 {
+			description += " | editors: " + strings.Join(names, ", ")
+		}
+	}
+	if len(protectedRange.UnprotectedRanges) > 0 {
+		except := make([]string, len(protectedRange.UnprotectedRanges))
+		for i, gridRange := range protectedRange.UnprotectedRanges {
+			except[i] = a1RangeOnSheet(gridRange, sheetProps).withoutSheet().String()
+		}
+		description += " | except: " + strings.Join(except, ", ")
+	}
+	if !protectedRange.RequestingUserCanEdit {
+		description += " | you cannot edit"
+	}
+	return description
+}
+
+// GridRange の終端（0 は省略されたシートの端）
+func gridRangeEnd(end int64) int64 {
+	if end == 0 {
+		return math.MaxInt64
+	}
+	return end
+}
+
+// 2つの GridRange が重なるか判定する
+func gridRangesIntersect(a, b *sheets.GridRange) bool {
+	return a.SheetId == b.SheetId &&
+		a.StartRowIndex < gridRangeEnd(b.EndRowIndex) && b.StartRowIndex < gridRangeEnd(a.EndRowIndex) &&
+		a.StartColumnIndex < gridRangeEnd(b.EndColumnIndex) && b.StartColumnIndex < gridRangeEnd(a.EndColumnIndex)
+}
+
+// outer が inner を完全に含むか判定する
+func gridRangeContains(outer, inner *sheets.GridRange) bool {
+	return outer.SheetId == inner.SheetId &&
+		outer.StartRowIndex <= inner.StartRowIndex && gridRangeEnd(inner.EndRowIndex) <= gridRangeEnd(outer.EndRowIndex) &&
+		outer.StartColumnIndex <= inner.StartColumnIndex && gridRangeEnd(inner.EndColumnIndex) <= gridRangeEnd(outer.EndColumnIndex)
+}
+
+// 書き込む範囲と重なる保護範囲を探す（保護の例外範囲に収まる場合は除く）
+func intersectingProtectedRanges(target *sheets.GridRange, protectedRanges []*sheets.ProtectedRange) []*sheets.ProtectedRange {
+	var hits []*sheets.ProtectedRange
+	for _, protectedRange := range protectedRanges {
+		if !gridRangesIntersect(target, protectedRange.Range) {
+			continue
+		}
+		excepted := false
+		for _, unprotected := range protectedRange.UnprotectedRanges {
+			if gridRangeContains(unprotected, target) {
+				excepted = true
+				break
+			}
+		}
+		if !excepted {
+			hits = append(hits, protectedRange)
+		}
+	}
+	return hits
+}
+
+// 書き込む範囲が編集できる保護範囲と重なる場合に書き込みを止める（MCPGS_WARN_PROTECTED_RANGES が有効な場合のみ）
+// 編集できない保護範囲への書き込みは API がエラーにするため、ここでは編集できる範囲のみ確認する
+func (gs *GoogleSheets) checkProtectedRanges(ctx context.Context, spreadsheetId, sheetName string, ranges []string) error {
+	if !gs.cfg.WarnProtectedRanges {
+		return nil
+	}
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	protectedRanges, sheetProps, err := gs.getProtectedRanges(ctx, spreadsheetId)
+	if err != nil {
+		return err
+	}
+	var editable []*sheets.ProtectedRange
+	for _, protectedRange := range protectedRanges {
+		if protectedRange.RequestingUserCanEdit {
+			editable = append(editable, protectedRange)
+		}
+	}
+	if len(editable) == 0 {
+		return nil
+	}
+
+	var conflicts []string
+	for _, rangeStr := range ranges {
+		target := &sheets.GridRange{SheetId: sheetId}
+		if rangeStr != "" {
+			if target, err = gridRangeFromA1(sheetId, rangeStr); err != nil {
+				return err
+			}
+		}
+		for _, protectedRange := range intersectingProtectedRanges(target, editable) {
+			conflicts = append(conflicts, fmt.Sprintf("- %s overlaps protected range %s", describeRange(rangeStr), describeProtectedRange(protectedRange, sheetProps)))
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return fmt.Errorf("the write was stopped because the target overlaps protected ranges:\n%s\n"+
+		"Confirm with the user that these cells should be changed, then retry with ignore_protection set to true", strings.Join(conflicts, "\n"))
+}
+
+func (gs *GoogleSheets) ListProtectedRangesHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ListProtectedRangesRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	protectedRanges, sheetProps, err := gs.getProtectedRanges(ctx, spreadsheetId)
+	if err != nil {
+		return nil, err
+	}
+	// シートが指定された場合はそのシートの保護範囲のみ表示する
+	if request.SheetName != "" {
+		sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+		}
+		var filtered []*sheets.ProtectedRange
+		for _, protectedRange := range protectedRanges {
+			if protectedRange.Range.SheetId == sheetId {
+				filtered = append(filtered, protectedRange)
+			}
+		}
+		protectedRanges = filtered
+	}
+
+	var result strings.Builder
+	target := fmt.Sprintf("spreadsheet '%s'", request.SpreadsheetName)
+	if request.SheetName != "" {
+		target = fmt.Sprintf("sheet '%s' of spreadsheet '%s'", request.SheetName, request.SpreadsheetName)
+	}
+	if len(protectedRanges) == 0 {
+		result.WriteString(fmt.Sprintf("No protected ranges found in %s.", target))
+	} else {
+		result.WriteString(fmt.Sprintf("Protected ranges in %s (%d):\n\n", target, len(protectedRanges)))
+		for _, protectedRange := range protectedRanges {
+			result.WriteString("- " + describeProtectedRange(protectedRange, sheetProps) + "\n")
+		}
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: result.String(),
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) ProtectRangeHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ProtectRangeRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	if request.WarningOnly && len(request.Editors) > 0 {
+		return nil, fmt.Errorf("editors cannot be set on a warning-only protected range")
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, request.SheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+	gridRange := &sheets.GridRange{SheetId: sheetId, ForceSendFields: []string{"SheetId"}}
+	if rangeStr != "" {
+		if gridRange, err = gridRangeFromA1(sheetId, rangeStr); err != nil {
+			return nil, err
+		}
+	}
+
+	protectedRange := &sheets.ProtectedRange{
+		Range:       gridRange,
+		Description: request.Description,
+		WarningOnly: request.WarningOnly,
+	}
+	if len(request.Editors) > 0 {
+		protectedRange.Editors = &sheets.Editors{Users: request.Editors}
+	}
+
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+	resp, err := service.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{AddProtectedRange: &sheets.AddProtectedRangeRequest{ProtectedRange: protectedRange}}},
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to protect range: %w", err)
+	}
+	added := resp.Replies[0].AddProtectedRange.ProtectedRange
+
+	message := fmt.Sprintf("Successfully protected %s in sheet '%s' of spreadsheet '%s':\n- %s",
+		describeRange(request.Range), request.SheetName, request.SpreadsheetName,
+		describeProtectedRange(added, map[int64]*sheets.SheetProperties{sheetId: {SheetId: sheetId, Title: request.SheetName}}))
+	message += fmt.Sprintf("\n\nTo undo this change, you can remove the protection with google_sheets_unprotect_range (id %d).", added.ProtectedRangeId)
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) UpdateProtectedRangeHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[UpdateProtectedRangeRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	if request.Range == "" && request.SheetName == "" && request.Description == nil && request.WarningOnly == nil && request.Editors == nil {
+		return nil, fmt.Errorf("specify range, sheet_name, description, warning_only and/or editors to update")
+	}
+
+	protectedRanges, sheetProps, err := gs.getProtectedRanges(ctx, spreadsheetId)
+	if err != nil {
+		return nil, err
+	}
+	current, err := findProtectedRange(protectedRanges, request.ProtectedRangeID)
+	if err != nil {
+		return nil, err
+	}
+	previous := describeProtectedRange(current, sheetProps)
+
+	updated := &sheets.ProtectedRange{
+		ProtectedRangeId:  current.ProtectedRangeId,
+		Range:             current.Range,
+		Description:       current.Description,
+		WarningOnly:       current.WarningOnly,
+		Editors:           current.Editors,
+		UnprotectedRanges: current.UnprotectedRanges,
+	}
+	var fields []string
+	if request.Range != "" || request.SheetName != "" {
+		// シートまたは範囲の一方のみ指定された場合は、もう一方は現在の値を使う
+		sheetId := current.Range.SheetId
+		sheetName := request.SheetName
+		if sheetName != "" {
+			if sheetId, err = gs.getSheetIdWithContext(ctx, spreadsheetId, sheetName); err != nil {
+				return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+			}
+		} else if properties, ok := sheetProps[sheetId]; ok {
+			sheetName = properties.Title
+		}
+		if request.Range == "" {
+			// 範囲を変えない場合は現在の GridRange をそのまま使う
+			moved := *current.Range
+			updated.Range = &moved
+			updated.Range.SheetId = sheetId
+		} else {
+			// シート名付きの範囲は対象のシートを指している必要がある
+			rangeStr, err := gs.resolveRange(ctx, spreadsheetId, sheetName, request.Range)
+			if err != nil {
+				return nil, err
+			}
+			updated.Range = &sheets.GridRange{SheetId: sheetId}
+			if rangeStr != "" {
+				if updated.Range, err = gridRangeFromA1(sheetId, rangeStr); err != nil {
+					return nil, err
+				}
+			}
+		}
+		updated.Range.ForceSendFields = []string{"SheetId"}
+		fields = append(fields, "range")
+	}
+	if request.Description != nil {
+		updated.Description = *request.Description
+		updated.ForceSendFields = append(updated.ForceSendFields, "Description")
+		fields = append(fields, "description")
+	}
+	if request.WarningOnly != nil {
+		updated.WarningOnly = *request.WarningOnly
+		updated.ForceSendFields = append(updated.ForceSendFields, "WarningOnly")
+		fields = append(fields, "warningOnly")
+	}
+	if request.Editors != nil {
+		updated.Editors = &sheets.Editors{Users: request.Editors, ForceSendFields: []string{"Users"}}
+		fields = append(fields, "editors")
+	}
+	if updated.WarningOnly && len(request.Editors) > 0 {
+		return nil, fmt.Errorf("editors cannot be set on a warning-only protected range")
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		UpdateProtectedRange: &sheets.UpdateProtectedRangeRequest{
+			ProtectedRange: updated,
+			Fields:         strings.Join(fields, ","),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update protected range: %w", err)
+	}
+
+	// 説明のために結果の状態を反映する
+	updated.NamedRangeId = ""
+	updated.RequestingUserCanEdit = current.RequestingUserCanEdit
+	message := fmt.Sprintf("Successfully updated protected range in spreadsheet '%s':\n- %s", request.SpreadsheetName, describeProtectedRange(updated, sheetProps))
+	message += fmt.Sprintf("\n\nPrevious protected range: %s\nTo undo this change, you can use the previous protected range.", previous)
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) UnprotectRangeHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[UnprotectRangeRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	protectedRanges, sheetProps, err := gs.getProtectedRanges(ctx, spreadsheetId)
+	if err != nil {
+		return nil, err
+	}
+	protectedRange, err := findProtectedRange(protectedRanges, request.ProtectedRangeID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		DeleteProtectedRange: &sheets.DeleteProtectedRangeRequest{
+			ProtectedRangeId: protectedRange.ProtectedRangeId,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unprotect range: %w", err)
+	}
+
+	message := fmt.Sprintf("Successfully removed protected range %d from spreadsheet '%s'. The cells were not changed.",
+		request.ProtectedRangeID, request.SpreadsheetName)
+	message += fmt.Sprintf("\n\nRemoved protected range: %s\nTo undo this change, you can protect the range again.",
+		describeProtectedRange(protectedRange, sheetProps))
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
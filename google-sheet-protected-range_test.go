@@ -0,0 +1,216 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestGridRangesIntersect(t *testing.T) {
+	mustGrid := func(sheetId int64, rangeStr string) *sheets.GridRange {
+		if rangeStr == "" {
+			return &sheets.GridRange{SheetId: sheetId}
+		}
+		gridRange, err := gridRangeFromA1(sheetId, rangeStr)
+		if err != nil {
+			t.Fatalf("gridRangeFromA1(%q) failed: %v", rangeStr, err)
+		}
+		return gridRange
+	}
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"A1:D1", "B1", true},
+		{"A1:D1", "A2:D10", false},
+		{"1:1", "C1:C5", true},
+		{"1:1", "A2:Z", false},
+		{"B:B", "A1:C3", true},
+		{"B:B", "C:E", false},
+		{"", "ZZ1000", true},
+		{"A2:C", "B100", true},
+		{"A2:C", "D100", false},
+	}
+	for _, tt := range tests {
+		a, b := mustGrid(1, tt.a), mustGrid(1, tt.b)
+		if got := gridRangesIntersect(a, b); got != tt.want {
+			t.Errorf("gridRangesIntersect(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := gridRangesIntersect(b, a); got != tt.want {
+			t.Errorf("gridRangesIntersect(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+		}
+	}
+	// 別のシートの範囲は重ならない
+	if gridRangesIntersect(mustGrid(1, "A1"), mustGrid(2, "A1")) {
+		t.Error("ranges on different sheets should not intersect")
+	}
+}
+
+func TestIntersectingProtectedRanges(t *testing.T) {
+	header := &sheets.ProtectedRange{ProtectedRangeId: 1, Range: &sheets.GridRange{SheetId: 0, StartRowIndex: 0, EndRowIndex: 1}}
+	sheet := &sheets.ProtectedRange{
+		ProtectedRangeId: 2,
+		Range:            &sheets.GridRange{SheetId: 3},
+		// C 列の 2 行目以降は保護しない
+		UnprotectedRanges: []*sheets.GridRange{{SheetId: 3, StartRowIndex: 1, StartColumnIndex: 2, EndColumnIndex: 3}},
+	}
+	protectedRanges := []*sheets.ProtectedRange{header, sheet}
+
+	tests := []struct {
+		sheetId  int64
+		rangeStr string
+		want     []int64
+	}{
+		{0, "A1:B2", []int64{1}},
+		{0, "A2:B10", nil},
+		{3, "A1", []int64{2}},
+		{3, "C2:C50", nil},
+		{3, "C1:C50", []int64{2}},
+		{3, "B2:C3", []int64{2}},
+	}
+	for _, tt := range tests {
+		target, err := gridRangeFromA1(tt.sheetId, tt.rangeStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hits := intersectingProtectedRanges(target, protectedRanges)
+		if len(hits) != len(tt.want) {
+			t.Errorf("sheet %d %q: got %d hits, want %v", tt.sheetId, tt.rangeStr, len(hits), tt.want)
+			continue
+		}
+		for i, hit := range hits {
+			if hit.ProtectedRangeId != tt.want[i] {
+				t.Errorf("sheet %d %q: hit %d = %d, want %d", tt.sheetId, tt.rangeStr, i, hit.ProtectedRangeId, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestDescribeProtectedRange(t *testing.T) {
+	sheetProps := map[int64]*sheets.SheetProperties{
+		0: {SheetId: 0, Title: "Sheet1", GridProperties: &sheets.GridProperties{RowCount: 1000, ColumnCount: 26}},
+		5: {SheetId: 5, Title: "Q1 2024", GridProperties: &sheets.GridProperties{RowCount: 200, ColumnCount: 10}},
+	}
+	tests := []struct {
+		protectedRange *sheets.ProtectedRange
+		want           string
+	}{
+		{
+			&sheets.ProtectedRange{
+				ProtectedRangeId:      10,
+				Range:                 &sheets.GridRange{SheetId: 0, StartRowIndex: 0, EndRowIndex: 1, StartColumnIndex: 0, EndColumnIndex: 4},
+				Description:           "Header row",
+				Editors:               &sheets.Editors{Users: []string{"owner@example.com", "lead@example.com"}},
+				RequestingUserCanEdit: true,
+			},
+			"id 10: Sheet1!A1:D1 | Header row | editors: owner@example.com, lead@example.com",
+		},
+		{
+			&sheets.ProtectedRange{
+				ProtectedRangeId:      11,
+				Range:                 &sheets.GridRange{SheetId: 5},
+				WarningOnly:           true,
+				UnprotectedRanges:     []*sheets.GridRange{{SheetId: 5, StartColumnIndex: 2, EndColumnIndex: 3}},
+				RequestingUserCanEdit: true,
+			},
+			"id 11: 'Q1 2024' (whole sheet) | warning only | except: C:C",
+		},
+		{
+			&sheets.ProtectedRange{
+				ProtectedRangeId: 12,
+				Range:            &sheets.GridRange{SheetId: 0, StartColumnIndex: 1, EndColumnIndex: 2},
+				NamedRangeId:     "abc",
+			},
+			"id 12: Sheet1!B:B (named range) | you cannot edit",
+		},
+		{
+			&sheets.ProtectedRange{
+				ProtectedRangeId:      13,
+				Range:                 &sheets.GridRange{SheetId: 5, StartRowIndex: 4},
+				UnprotectedRanges:     []*sheets.GridRange{{SheetId: 5, StartColumnIndex: 8}},
+				RequestingUserCanEdit: true,
+			},
+			"id 13: 'Q1 2024'!5:200 | except: I:J",
+		},
+	}
+	for _, tt := range tests {
+		if got := describeProtectedRange(tt.protectedRange, sheetProps); got != tt.want {
+			t.Errorf("describeProtectedRange = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestProtectRangeHandlerSheetPrefix(t *testing.T) {
+	gs, ctx, requests := newRangeTestGoogleSheets(t, newRangeTestSpreadsheet())
+	_, err := gs.ProtectRangeHandler(ctx, nil, &mcp.CallToolParamsFor[ProtectRangeRequest]{Arguments: ProtectRangeRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Range:           "Data!A1:B2",
+	}})
+	if err == nil || !strings.Contains(err.Error(), "refers to sheet 'Data', not 'Sheet1'") {
+		t.Fatalf("error = %v, want a sheet mismatch error", err)
+	}
+
+	_, err = gs.ProtectRangeHandler(ctx, nil, &mcp.CallToolParamsFor[ProtectRangeRequest]{Arguments: ProtectRangeRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Data",
+		Range:           "Data!A3:B4",
+	}})
+	if err != nil {
+		t.Fatalf("ProtectRangeHandler failed: %v", err)
+	}
+	if len(*requests) != 1 || (*requests)[0].AddProtectedRange == nil {
+		t.Fatalf("unexpected requests: %+v", *requests)
+	}
+	if got := (*requests)[0].AddProtectedRange.ProtectedRange.Range; got.SheetId != 4 || got.StartRowIndex != 2 {
+		t.Errorf("range = %+v, want A3:B4 on sheet 4", got)
+	}
+}
+
+func TestUpdateProtectedRangeHandlerSheetPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		sheetName  string
+		rangeStr   string
+		wantSheet  int64
+		wantErrMsg string
+	}{
+		// sheet_name を省略した場合は現在のシートに対して確認する
+		{name: "prefix matches current sheet", rangeStr: "Sheet1!A1:A3", wantSheet: 0},
+		{name: "prefix differs from current sheet", rangeStr: "Data!A1:A3", wantErrMsg: "refers to sheet 'Data', not 'Sheet1'"},
+		{name: "prefix matches new sheet", sheetName: "Data", rangeStr: "Data!A1:A3", wantSheet: 4},
+		{name: "named range on new sheet", sheetName: "Data", rangeStr: "Prices", wantSheet: 4},
+		{name: "named range on another sheet", rangeStr: "Prices", wantErrMsg: "is on sheet 'Data', not 'Sheet1'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs, ctx, requests := newRangeTestGoogleSheets(t, newRangeTestSpreadsheet())
+			_, err := gs.UpdateProtectedRangeHandler(ctx, nil, &mcp.CallToolParamsFor[UpdateProtectedRangeRequest]{Arguments: UpdateProtectedRangeRequest{
+				SpreadsheetName:  "Budget",
+				ProtectedRangeID: 9,
+				SheetName:        tt.sheetName,
+				Range:            tt.rangeStr,
+			}})
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErrMsg)
+				}
+				if len(*requests) != 0 {
+					t.Errorf("sent requests for a range on another sheet: %+v", *requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateProtectedRangeHandler failed: %v", err)
+			}
+			if len(*requests) != 1 || (*requests)[0].UpdateProtectedRange == nil {
+				t.Fatalf("unexpected requests: %+v", *requests)
+			}
+			if got := (*requests)[0].UpdateProtectedRange.ProtectedRange.Range; got.SheetId != tt.wantSheet {
+				t.Errorf("range = %+v, want sheet %d", got, tt.wantSheet)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// クエリ結果として表示するデフォルトの最大行数
+const defaultQueryMaxRows = 500
+
+type QueryRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	Query           string `json:"query"`
+	MaxRows         int64  `json:"max_rows"`
+}
+
+var QueryInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to query when the query has no FROM clause",
+		},
+		"query": {
+			Type: "string",
+			Description: "SQL-like query evaluated against sheets whose first row is a header row. " +
+				"Syntax: SELECT * | expr [AS alias], ... [FROM sheet [alias] [[INNER|LEFT] JOIN sheet [alias] ON a.key = b.key]] " +
+				"[WHERE cond] [GROUP BY expr, ...] [HAVING cond] [ORDER BY expr [ASC|DESC], ...] [LIMIT n] [OFFSET n]. " +
+				"sheet is a sheet name or numeric sheet ID. Columns are referred to by header name (quote names with spaces using `...` or \"...\") or by column letter (A, B, ...). " +
+				"Strings use single quotes. Operators: = != <> < <= > >= AND OR NOT, LIKE ('%', '_'), CONTAINS, STARTS WITH, ENDS WITH, IN (...), IS [NOT] NULL, + - * /. " +
+				"Functions: COUNT(*), COUNT/SUM/AVG/MIN/MAX([DISTINCT] expr), LOWER, UPPER. Text matching is case-insensitive. " +
+				"Example: SELECT Region, SUM(Revenue) AS Total FROM Sales GROUP BY Region ORDER BY Total DESC",
+		},
+		"max_rows": {
+			Type:        "integer",
+			Description: "Maximum number of result rows to return. Default: 500",
+			Default:     json.RawMessage(`500`),
+		},
+	},
+	Required: []string{"spreadsheet_name", "query"},
+}
+
+func (gs *GoogleSheets) QueryHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[QueryRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+
+	// クエリを解析
+	query, err := parseSheetQuery(request.Query)
+	if err != nil {
+		return nil, err
+	}
+	if query.from == nil && request.SheetName == "" {
+		return nil, fmt.Errorf("no sheet to query. Specify sheet_name or a FROM clause in the query")
+	}
+
+	maxRows := request.MaxRows
+	if maxRows <= 0 {
+		maxRows = defaultQueryMaxRows
+	}
+
+	// スプレッドシート名からスプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+	// FROM 句がない場合は sheet_name のシートを対象にする
+	if query.from == nil {
+		query.from = &tableRef{name: request.SheetName}
+	}
+
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).Fields("sheets.properties(sheetId,title)").Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+
+	// クエリが参照するシートのデータを取得（日付は書式付き文字列、それ以外は書式なしの値）
+	// テーブル名はシート名またはシートIDで、同じシートは一度だけ読み込む
+	tables := make(map[string]*queryTable)
+	loaded := make(map[string]*queryTable)
+	for _, name := range query.tableNames() {
+		if _, ok := tables[name]; ok {
+			continue
+		}
+		sheet := findSheet(spreadsheet.Sheets, name)
+		if sheet == nil {
+			return nil, fmt.Errorf("sheet not found: '%s'. Please check the sheet name. Use google_sheets_list_sheets to see available sheets in this spreadsheet", name)
+		}
+		title := sheet.Properties.Title
+		if table, ok := loaded[title]; ok {
+			tables[name] = table
+			continue
+		}
+		resp, err := service.Spreadsheets.Values.Get(spreadsheetId, quoteSheetName(title)).
+			ValueRenderOption("UNFORMATTED_VALUE").
+			DateTimeRenderOption("FORMATTED_STRING").
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get data of sheet '%s': %w", title, err)
+		}
+		loaded[title] = newQueryTable(title, resp.Values)
+		tables[name] = loaded[title]
+	}
+
+	// クエリを評価
+	result, err := query.execute(tables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	total := len(result.rows)
+	truncated := int64(total) > maxRows
+	if truncated {
+		result.rows = result.rows[:maxRows]
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Query result from spreadsheet '%s' (%d rows):\n\n", request.SpreadsheetName, total))
+	text.WriteString(formatQueryResult(result))
+	if truncated {
+		text.WriteString(fmt.Sprintf("\nShowing the first %d of %d rows. Add LIMIT/OFFSET or increase max_rows to see more.\n", maxRows, total))
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: text.String(),
+			},
+		},
+	}, nil
+}
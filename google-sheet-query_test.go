@@ -0,0 +1,142 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestQueryHandlerWithSheetID(t *testing.T) {
+	var requestedRanges []string
+	gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1"}}})
+		case "/v4/spreadsheets/ss1":
+			writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1"}},
+				{Properties: &sheets.SheetProperties{SheetId: 777, Title: "Sales"}},
+			}})
+		case "/v4/spreadsheets/ss1/values/Sales":
+			requestedRanges = append(requestedRanges, "Sales")
+			writeTestJSON(t, w, &sheets.ValueRange{Values: [][]interface{}{
+				{"Region", "Revenue"},
+				{"East", 120},
+				{"West", 80},
+			}})
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+
+	result, err := gs.QueryHandler(ctx, nil, &mcp.CallToolParamsFor[QueryRequest]{Arguments: QueryRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "777",
+		Query:           "SELECT Region, Revenue WHERE Revenue > 100",
+	}})
+	if err != nil {
+		t.Fatalf("QueryHandler failed: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "| East | 120 |") || strings.Contains(text, "West") {
+		t.Errorf("unexpected result:\n%s", text)
+	}
+	if len(requestedRanges) != 1 {
+		t.Errorf("sheet 'Sales' was read %d times, want 1", len(requestedRanges))
+	}
+}
+
+func TestQueryHandlerWithSheetIDInQuery(t *testing.T) {
+	requestedRanges := make(map[string]int)
+	gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1"}}})
+		case "/v4/spreadsheets/ss1":
+			writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Customers"}},
+				{Properties: &sheets.SheetProperties{SheetId: 777, Title: "Sales"}},
+			}})
+		case "/v4/spreadsheets/ss1/values/Sales":
+			requestedRanges["Sales"]++
+			writeTestJSON(t, w, &sheets.ValueRange{Values: [][]interface{}{
+				{"Customer ID", "Revenue"},
+				{1, 120},
+				{2, 80},
+			}})
+		case "/v4/spreadsheets/ss1/values/Customers":
+			requestedRanges["Customers"]++
+			writeTestJSON(t, w, &sheets.ValueRange{Values: [][]interface{}{
+				{"ID", "Name"},
+				{1, "Acme"},
+				{2, "Bolt"},
+			}})
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "numeric ids in FROM and JOIN",
+			query: "SELECT c.Name, s.Revenue FROM 777 s JOIN 0 c ON s.`Customer ID` = c.ID ORDER BY c.Name",
+			want:  []string{"| Acme | 120 |", "| Bolt | 80 |"},
+		},
+		{
+			name:  "quoted id mixed with a sheet name",
+			query: "SELECT Name FROM `777` JOIN Customers ON `Customer ID` = ID WHERE Revenue > 100",
+			want:  []string{"| Acme |"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clear(requestedRanges)
+			result, err := gs.QueryHandler(ctx, nil, &mcp.CallToolParamsFor[QueryRequest]{Arguments: QueryRequest{
+				SpreadsheetName: "Budget",
+				Query:           tt.query,
+			}})
+			if err != nil {
+				t.Fatalf("QueryHandler failed: %v", err)
+			}
+			text := result.Content[0].(*mcp.TextContent).Text
+			for _, line := range tt.want {
+				if !strings.Contains(text, line) {
+					t.Errorf("result is missing %q:\n%s", line, text)
+				}
+			}
+			if requestedRanges["Sales"] != 1 || requestedRanges["Customers"] != 1 {
+				t.Errorf("sheets were read %v times, want each once", requestedRanges)
+			}
+		})
+	}
+
+	// 同じシートを名前と ID で参照しても一度だけ読み込む
+	clear(requestedRanges)
+	if _, err := gs.QueryHandler(ctx, nil, &mcp.CallToolParamsFor[QueryRequest]{Arguments: QueryRequest{
+		SpreadsheetName: "Budget",
+		Query:           "SELECT a.Revenue FROM Sales a JOIN 777 b ON a.`Customer ID` = b.`Customer ID`",
+	}}); err != nil {
+		t.Fatalf("QueryHandler failed: %v", err)
+	}
+	if requestedRanges["Sales"] != 1 {
+		t.Errorf("sheet 'Sales' was read %d times, want 1", requestedRanges["Sales"])
+	}
+
+	_, err := gs.QueryHandler(ctx, nil, &mcp.CallToolParamsFor[QueryRequest]{Arguments: QueryRequest{
+		SpreadsheetName: "Budget",
+		Query:           "SELECT * FROM 999",
+	}})
+	if err == nil || !strings.Contains(err.Error(), "sheet not found: '999'") {
+		t.Errorf("error = %v, want sheet not found", err)
+	}
+}
@@ -0,0 +1,604 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+// レコードとして返すデフォルトの最大件数
+const defaultRecordsLimit = 100
+
+// レコード（1行目をヘッダー行とする表の1行）の取得リクエスト
+type GetRecordsRequest struct {
+	SpreadsheetName string                 `json:"spreadsheet_name"`
+	SheetName       string                 `json:"sheet_name"`
+	Filter          map[string]interface{} `json:"filter"`
+	Limit           int64                  `json:"limit"`
+	Offset          int64                  `json:"offset"`
+}
+
+var GetRecordsInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab whose first row is the header row",
+		},
+		"filter": {
+			Type:        "object",
+			Description: "Only return records whose columns equal the given values. Keys are header names. Example: {\"Status\": \"Open\", \"Owner\": \"Alice\"}",
+		},
+		"limit": {
+			Type:        "integer",
+			Description: "Maximum number of records to return. Default: 100",
+			Default:     json.RawMessage(`100`),
+		},
+		"offset": {
+			Type:        "integer",
+			Description: "Number of matching records to skip",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name"},
+}
+
+// レコードの追加・更新リクエスト
+type UpsertRecordsRequest struct {
+	SpreadsheetName  string                   `json:"spreadsheet_name"`
+	SheetName        string                   `json:"sheet_name"`
+	KeyColumn        string                   `json:"key_column"`
+	Records          []map[string]interface{} `json:"records"`
+	AddColumns       bool                     `json:"add_columns"`
+	IgnoreProtection bool                     `json:"ignore_protection"`
+}
+
+var UpsertRecordsInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab whose first row is the header row",
+		},
+		"key_column": {
+			Type:        "string",
+			Description: "Header name of the column that identifies a record. Records whose key matches an existing row update that row; others are appended at the end",
+		},
+		"records": {
+			Type:        "array",
+			Description: "Records to write as objects keyed by header name. Only the given columns are written. Example: [{\"ID\": 42, \"Status\": \"Done\"}]",
+			Items: &jsonschema.Schema{
+				Type: "object",
+			},
+		},
+		"add_columns": {
+			Type:        "boolean",
+			Description: "Add header columns for keys that do not exist yet instead of failing",
+		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Write even if the target overlaps a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "key_column", "records"},
+}
+
+// レコードの削除リクエスト
+type DeleteRecordsRequest struct {
+	SpreadsheetName  string                 `json:"spreadsheet_name"`
+	SheetName        string                 `json:"sheet_name"`
+	Filter           map[string]interface{} `json:"filter"`
+	IgnoreProtection bool                   `json:"ignore_protection"`
+}
+
+var DeleteRecordsInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab whose first row is the header row",
+		},
+		"filter": {
+			Type:        "object",
+			Description: "Delete the rows of all records whose columns equal the given values. Keys are header names. Example: {\"ID\": 42}",
+		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Delete even if the matching rows overlap a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "filter"},
+}
+
+// シートをヘッダー行付きの表として読み込む（データ行 i はシートの i+2 行目）
+func (gs *GoogleSheets) loadRecordTable(ctx context.Context, service *sheets.Service, spreadsheetId, sheetName string) (*queryTable, error) {
+	resp, err := service.Spreadsheets.Values.Get(spreadsheetId, quoteSheetName(sheetName)).
+		ValueRenderOption("UNFORMATTED_VALUE").
+		DateTimeRenderOption("FORMATTED_STRING").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data of sheet '%s': %w", sheetName, err)
+	}
+	if len(resp.Values) == 0 {
+		return nil, fmt.Errorf("sheet '%s' has no header row. The first row must contain the column names", sheetName)
+	}
+	return newQueryTable(sheetName, resp.Values), nil
+}
+
+// ヘッダー名から列番号（0-based）を取得する（完全一致を優先し、なければ大文字・小文字を区別せずに探す）
+func recordColumnIndex(columns []string, name string) (int, bool) {
+	for i, column := range columns {
+		if column == name {
+			return i, true
+		}
+	}
+	for i, column := range columns {
+		if strings.EqualFold(column, name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// レコードのフィルター（ヘッダー名と値の組）
+type recordFilter struct {
+	columns []int
+	values  []interface{}
+}
+
+func newRecordFilter(table *queryTable, filter map[string]interface{}) (*recordFilter, error) {
+	f := &recordFilter{}
+	// エラーメッセージを安定させるためキーを並べる
+	names := make([]string, 0, len(filter))
+	for name := range filter {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		index, ok := recordColumnIndex(table.columns, name)
+		if !ok {
+			return nil, fmt.Errorf("column not found: '%s'. Available columns: %s", name, strings.Join(table.columns, ", "))
+		}
+		f.columns = append(f.columns, index)
+		f.values = append(f.values, filter[name])
+	}
+	return f, nil
+}
+
+// 行がフィルターに一致するか（空の値は空のセルに一致する）
+func (f *recordFilter) match(row []interface{}) bool {
+	for i, index := range f.columns {
+		if !recordValueEqual(row[index], f.values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// セルの値とレコードの値が等しいか（数値は表記によらず比較する）
+func recordValueEqual(cell, value interface{}) bool {
+	if isNullValue(cell) || isNullValue(value) {
+		return isNullValue(cell) && isNullValue(value)
+	}
+	return compareQueryValues(cell, value) == 0
+}
+
+// 空の行かどうか
+func isEmptyRecord(row []interface{}) bool {
+	for _, value := range row {
+		if !isNullValue(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// レコードをヘッダーの順序を保った JSON オブジェクトに変換する
+func formatRecord(columns []string, rowNumber int64, row []interface{}) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("{\"_row\": %d", rowNumber))
+	for i, column := range columns {
+		key, _ := json.Marshal(column)
+		var value interface{}
+		if !isNullValue(row[i]) {
+			value = row[i]
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			encoded, _ = json.Marshal(formatQueryValue(value))
+		}
+		builder.WriteString(fmt.Sprintf(", %s: %s", key, encoded))
+	}
+	builder.WriteString("}")
+	return builder.String()
+}
+
+func (gs *GoogleSheets) GetRecordsHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[GetRecordsRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultRecordsLimit
+	}
+
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	table, err := gs.loadRecordTable(ctx, service, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := newRecordFilter(table, request.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// フィルターに一致するレコードを集める
+	var records []string
+	matched := int64(0)
+	for i, row := range table.rows {
+		if isEmptyRecord(row) || !filter.match(row) {
+			continue
+		}
+		matched++
+		if matched <= request.Offset || int64(len(records)) >= limit {
+			continue
+		}
+		records = append(records, formatRecord(table.columns, int64(i+2), row))
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Records in sheet '%s' of spreadsheet '%s' (%d matching, showing %d):\n",
+		request.SheetName, request.SpreadsheetName, matched, len(records)))
+	result.WriteString(fmt.Sprintf("Columns: %s\n\n", strings.Join(table.columns, ", ")))
+	result.WriteString("[\n")
+	for i, record := range records {
+		result.WriteString("  " + record)
+		if i < len(records)-1 {
+			result.WriteString(",")
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString("]\n")
+	if next := request.Offset + int64(len(records)); next < matched {
+		result.WriteString(fmt.Sprintf("\nMore records are available. Use offset %d to continue.\n", next))
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: result.String(),
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) UpsertRecordsHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[UpsertRecordsRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	if len(request.Records) == 0 {
+		return nil, fmt.Errorf("records cannot be empty")
+	}
+
+	properties, err := gs.getSheetPropertiesWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet properties: %w", err)
+	}
+
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	table, err := gs.loadRecordTable(ctx, service, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, err
+	}
+	keyIndex, ok := recordColumnIndex(table.columns, request.KeyColumn)
+	if !ok {
+		return nil, fmt.Errorf("key column not found: '%s'. Available columns: %s", request.KeyColumn, strings.Join(table.columns, ", "))
+	}
+
+	// レコードを列番号と値の組に変換する（add_columns の場合は存在しない列をヘッダー行の末尾に追加する）
+	var newColumns []string
+	records := make([]map[int]interface{}, len(request.Records))
+	for i, record := range request.Records {
+		names := make([]string, 0, len(record))
+		for name := range record {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		records[i] = make(map[int]interface{}, len(record))
+		for _, name := range names {
+			column, ok := recordColumnIndex(table.columns, name)
+			if !ok {
+				if !request.AddColumns {
+					return nil, fmt.Errorf("column not found: '%s'. Available columns: %s. Set add_columns to true to add it", name, strings.Join(table.columns, ", "))
+				}
+				column = len(table.columns)
+				table.columns = append(table.columns, name)
+				newColumns = append(newColumns, name)
+			}
+			records[i][column] = record[name]
+		}
+		if isNullValue(records[i][keyIndex]) {
+			return nil, fmt.Errorf("every record must have a value for the key column '%s'", request.KeyColumn)
+		}
+	}
+
+	var (
+		data       []*sheets.ValueRange
+		targets    []string
+		previous   strings.Builder
+		updated    int
+		appended   [][]interface{}
+		keyRows    = make(map[string]int)
+		appendedAt = make(map[string]int)
+	)
+	// キーから行を引く索引（同じキーの行が複数ある場合は更新できない）
+	for i, row := range table.rows {
+		if isNullValue(row[keyIndex]) {
+			continue
+		}
+		key := joinKey(row[keyIndex])
+		if _, ok := keyRows[key]; ok {
+			keyRows[key] = -1
+			continue
+		}
+		keyRows[key] = i
+	}
+	lastRow := int64(1)
+	for i, row := range table.rows {
+		if !isEmptyRecord(row) {
+			lastRow = int64(i + 2)
+		}
+	}
+
+	if len(newColumns) > 0 {
+		startCol := int64(len(table.columns)-len(newColumns)) + 1
+		header := make([]interface{}, len(newColumns))
+		for i, name := range newColumns {
+			header[i] = name
+		}
+		data = append(data, &sheets.ValueRange{
+			Range:  sheetRange(request.SheetName, a1Cell(startCol, 1).String()),
+			Values: [][]interface{}{header},
+		})
+		targets = append(targets, a1Range{startCol: startCol, startRow: 1, endCol: int64(len(table.columns)), endRow: 1}.String())
+	}
+
+	for _, record := range records {
+		keyValue := record[keyIndex]
+		key := joinKey(keyValue)
+
+		index, exists := keyRows[key]
+		if exists && index < 0 {
+			return nil, fmt.Errorf("key '%s' matches more than one row in column '%s'. Make the key unique before upserting", formatQueryValue(keyValue), request.KeyColumn)
+		}
+
+		if !exists {
+			// 同じリクエスト内で追加済みのキーは追加行を更新する
+			row, ok := appendedAt[key]
+			if !ok {
+				row = len(appended)
+				appendedAt[key] = row
+				appended = append(appended, make([]interface{}, len(table.columns)))
+			}
+			for column, value := range record {
+				appended[row][column] = value
+			}
+			continue
+		}
+
+		// 既存の行は指定された列のセルだけを更新する
+		rowNumber := int64(index + 2)
+		previous.WriteString(formatTableData(a1Cell(1, rowNumber), [][]interface{}{table.rows[index]}))
+		columns := make([]int, 0, len(record))
+		for column := range record {
+			columns = append(columns, column)
+		}
+		sort.Ints(columns)
+		for _, column := range columns {
+			cell := a1Cell(int64(column+1), rowNumber).String()
+			data = append(data, &sheets.ValueRange{
+				Range:  sheetRange(request.SheetName, cell),
+				Values: [][]interface{}{{record[column]}},
+			})
+			targets = append(targets, cell)
+		}
+		updated++
+	}
+
+	// 追加する行はデータの最終行の次から書き込む
+	if len(appended) > 0 {
+		data = append(data, &sheets.ValueRange{
+			Range:  sheetRange(request.SheetName, a1Cell(1, lastRow+1).String()),
+			Values: appended,
+		})
+		targets = append(targets, a1Range{startCol: 1, startRow: lastRow + 1, endCol: int64(len(table.columns)), endRow: lastRow + int64(len(appended))}.String())
+	}
+
+	// 保護された範囲への書き込みを確認
+	if !request.IgnoreProtection {
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, request.SheetName, targets); err != nil {
+			return nil, err
+		}
+	}
+	if err := gs.ensureGridSize(ctx, spreadsheetId, properties, lastRow+int64(len(appended)), int64(len(table.columns))); err != nil {
+		return nil, err
+	}
+
+	_, err = service.Spreadsheets.Values.BatchUpdate(spreadsheetId, &sheets.BatchUpdateValuesRequest{
+		ValueInputOption: "USER_ENTERED",
+		Data:             data,
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert records: %w", err)
+	}
+
+	message := fmt.Sprintf("Successfully upserted records in sheet '%s' of spreadsheet '%s': %d updated, %d appended",
+		request.SheetName, request.SpreadsheetName, updated, len(appended))
+	if len(appended) > 0 {
+		message += fmt.Sprintf(" (rows %d-%d)", lastRow+1, lastRow+int64(len(appended)))
+	}
+	if len(newColumns) > 0 {
+		message += fmt.Sprintf("\nAdded columns: %s", strings.Join(newColumns, ", "))
+	}
+	if updated > 0 {
+		message += "\n\nPrevious data of the updated rows has been saved. To undo this change, you can use the previous data.\n\nPrevious data details:\n\n" + previous.String()
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) DeleteRecordsHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[DeleteRecordsRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	// 全行の削除を防ぐためフィルターを必須とする
+	if len(request.Filter) == 0 {
+		return nil, fmt.Errorf("filter cannot be empty")
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	table, err := gs.loadRecordTable(ctx, service, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := newRecordFilter(table, request.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		matched  []int
+		previous strings.Builder
+	)
+	for i, row := range table.rows {
+		if isEmptyRecord(row) || !filter.match(row) {
+			continue
+		}
+		matched = append(matched, i)
+		previous.WriteString(formatTableData(a1Cell(1, int64(i+2)), [][]interface{}{row}))
+	}
+	if len(matched) == 0 {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("No records matched the filter in sheet '%s' of spreadsheet '%s'. Nothing was deleted.", request.SheetName, request.SpreadsheetName),
+				},
+			},
+		}, nil
+	}
+
+	// 連続する行をまとめ、行番号がずれないよう下から順に削除する
+	var (
+		requests []*sheets.Request
+		targets  []string
+	)
+	for end := len(matched) - 1; end >= 0; {
+		start := end
+		for start > 0 && matched[start-1] == matched[start]-1 {
+			start--
+		}
+		requests = append(requests, &sheets.Request{
+			DeleteDimension: &sheets.DeleteDimensionRequest{
+				Range: &sheets.DimensionRange{
+					SheetId:    sheetId,
+					Dimension:  "ROWS",
+					StartIndex: int64(matched[start] + 1),
+					EndIndex:   int64(matched[end] + 2),
+				},
+			},
+		})
+		targets = append(targets, a1Range{startRow: int64(matched[start] + 2), endRow: int64(matched[end] + 2)}.String())
+		end = start - 1
+	}
+
+	// 保護された範囲にかかる行の削除を確認
+	if !request.IgnoreProtection {
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, request.SheetName, targets); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{Requests: requests}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete records: %w", err)
+	}
+
+	message := fmt.Sprintf("Successfully deleted %d records from sheet '%s' of spreadsheet '%s'",
+		len(matched), request.SheetName, request.SpreadsheetName)
+	message += "\n\nDeleted data has been saved. Row numbers refer to the positions before deletion. To undo this change, you can use the saved data."
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message + "\n\nDeleted data details:\n\n" + previous.String(),
+			},
+		},
+	}, nil
+}
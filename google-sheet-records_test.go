@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestRecordFilter(t *testing.T) {
+	table := newQueryTable("Tasks", [][]interface{}{
+		{"ID", "Status", "Owner"},
+		{float64(1), "Open", "Alice"},
+		{float64(2), "Done", ""},
+		{"3", "open", "Bob"},
+	})
+
+	tests := []struct {
+		filter map[string]interface{}
+		want   []bool
+	}{
+		{map[string]interface{}{"Status": "Open"}, []bool{true, false, false}},
+		{map[string]interface{}{"id": float64(3)}, []bool{false, false, true}},
+		{map[string]interface{}{"ID": "2", "Owner": ""}, []bool{false, true, false}},
+		{map[string]interface{}{"Owner": nil}, []bool{false, true, false}},
+	}
+	for _, tt := range tests {
+		filter, err := newRecordFilter(table, tt.filter)
+		if err != nil {
+			t.Fatalf("newRecordFilter(%v) error: %v", tt.filter, err)
+		}
+		for i, row := range table.rows {
+			if got := filter.match(row); got != tt.want[i] {
+				t.Errorf("filter %v on row %d = %v, want %v", tt.filter, i+2, got, tt.want[i])
+			}
+		}
+	}
+
+	if _, err := newRecordFilter(table, map[string]interface{}{"Priority": "High"}); err == nil {
+		t.Errorf("newRecordFilter with unknown column: expected error")
+	}
+}
+
+func TestFormatRecord(t *testing.T) {
+	got := formatRecord([]string{"ID", "Name \"nick\"", "Done"}, 5, []interface{}{float64(7), "", true})
+	want := `{"_row": 5, "ID": 7, "Name \"nick\"": null, "Done": true}`
+	if got != want {
+		t.Errorf("formatRecord = %s, want %s", got, want)
+	}
+}
+
+// レコード操作用の Google API の偽物（書き込まれた値と BatchUpdate のリクエストを記録する）
+func newRecordsTestGoogleSheets(t *testing.T, values [][]interface{}) (*GoogleSheets, context.Context, *[]*sheets.ValueRange, *[]*sheets.Request) {
+	t.Helper()
+	var (
+		written  []*sheets.ValueRange
+		requests []*sheets.Request
+	)
+	gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1"}}})
+		case "/v4/spreadsheets/ss1":
+			writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{{Properties: &sheets.SheetProperties{
+				SheetId:        0,
+				Title:          "Sheet1",
+				GridProperties: &sheets.GridProperties{RowCount: 100, ColumnCount: 26},
+			}}}})
+		case "/v4/spreadsheets/ss1/values/Sheet1":
+			writeTestJSON(t, w, &sheets.ValueRange{Values: values})
+		case "/v4/spreadsheets/ss1/values:batchUpdate":
+			var batch sheets.BatchUpdateValuesRequest
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				t.Fatal(err)
+			}
+			written = append(written, batch.Data...)
+			writeTestJSON(t, w, &sheets.BatchUpdateValuesResponse{})
+		case "/v4/spreadsheets/ss1:batchUpdate":
+			var batch sheets.BatchUpdateSpreadsheetRequest
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				t.Fatal(err)
+			}
+			requests = append(requests, batch.Requests...)
+			writeTestJSON(t, w, &sheets.BatchUpdateSpreadsheetResponse{})
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+	return gs, ctx, &written, &requests
+}
+
+// 書き込まれた範囲と値を "範囲=値" の形式で並べる
+func describeWrittenValues(written []*sheets.ValueRange) []string {
+	var got []string
+	for _, valueRange := range written {
+		got = append(got, fmt.Sprintf("%s=%v", valueRange.Range, valueRange.Values))
+	}
+	return got
+}
+
+func TestUpsertRecordsHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      [][]interface{}
+		records     []map[string]interface{}
+		addColumns  bool
+		want        []string
+		wantMessage string
+		wantErrMsg  string
+	}{
+		{
+			name:    "update and append",
+			values:  [][]interface{}{{"ID", "Name"}, {1, "Apple"}, {2, "Banana"}},
+			records: []map[string]interface{}{{"ID": 2, "Name": "Blueberry"}, {"ID": 3, "Name": "Cherry"}},
+			want: []string{
+				"Sheet1!A3=[[2]]",
+				"Sheet1!B3=[[Blueberry]]",
+				"Sheet1!A4=[[3 Cherry]]",
+			},
+			wantMessage: "| **3** | 2 | Banana |",
+		},
+		// 同じキーの行が複数ある場合は、そのキーを更新できない
+		{
+			name:       "duplicate key",
+			values:     [][]interface{}{{"ID", "Name"}, {1, "Apple"}, {1, "Apricot"}, {2, "Banana"}},
+			records:    []map[string]interface{}{{"ID": 1, "Name": "Avocado"}},
+			wantErrMsg: "key '1' matches more than one row",
+		},
+		{
+			name:    "duplicate key elsewhere",
+			values:  [][]interface{}{{"ID", "Name"}, {1, "Apple"}, {1, "Apricot"}, {2, "Banana"}},
+			records: []map[string]interface{}{{"ID": 2, "Name": "Blueberry"}},
+			want:    []string{"Sheet1!A4=[[2]]", "Sheet1!B4=[[Blueberry]]"},
+		},
+		// 同じリクエスト内で同じキーのレコードは一つの追加行にまとめる
+		{
+			name:    "same new key twice",
+			values:  [][]interface{}{{"ID", "Name", "Status"}, {1, "Apple", "ok"}},
+			records: []map[string]interface{}{{"ID": 5, "Name": "Fig"}, {"ID": 6, "Name": "Grape"}, {"ID": 5, "Status": "new"}},
+			want:    []string{"Sheet1!A3=[[5 Fig new] [6 Grape <nil>]]"},
+		},
+		// 末尾の空行の後ではなく、データの最終行の次に追加する
+		{
+			name:        "trailing blank rows",
+			values:      [][]interface{}{{"ID", "Name"}, {1, "Apple"}, {"", ""}, {2, "Banana"}, {"", ""}, {""}},
+			records:     []map[string]interface{}{{"ID": 3, "Name": "Cherry"}},
+			want:        []string{"Sheet1!A5=[[3 Cherry]]"},
+			wantMessage: "(rows 5-5)",
+		},
+		{
+			name:       "add columns",
+			values:     [][]interface{}{{"ID", "Name"}, {1, "Apple"}},
+			records:    []map[string]interface{}{{"ID": 1, "Zone": "z"}, {"ID": 2, "Note": "n"}},
+			addColumns: true,
+			want: []string{
+				"Sheet1!C1=[[Zone Note]]",
+				"Sheet1!A2=[[1]]",
+				"Sheet1!C2=[[z]]",
+				"Sheet1!A3=[[2 <nil> <nil> n]]",
+			},
+			wantMessage: "Added columns: Zone, Note",
+		},
+		{
+			name:       "unknown column",
+			values:     [][]interface{}{{"ID", "Name"}, {1, "Apple"}},
+			records:    []map[string]interface{}{{"ID": 1, "Zone": "z"}},
+			wantErrMsg: "column not found: 'Zone'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs, ctx, written, _ := newRecordsTestGoogleSheets(t, tt.values)
+			result, err := gs.UpsertRecordsHandler(ctx, nil, &mcp.CallToolParamsFor[UpsertRecordsRequest]{Arguments: UpsertRecordsRequest{
+				SpreadsheetName: "Budget",
+				SheetName:       "Sheet1",
+				KeyColumn:       "ID",
+				Records:         tt.records,
+				AddColumns:      tt.addColumns,
+			}})
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErrMsg)
+				}
+				if len(*written) != 0 {
+					t.Errorf("values were written despite the error: %v", describeWrittenValues(*written))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpsertRecordsHandler failed: %v", err)
+			}
+			if got := describeWrittenValues(*written); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("written values = %v, want %v", got, tt.want)
+			}
+			if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, tt.wantMessage) {
+				t.Errorf("result does not contain %q:\n%s", tt.wantMessage, text)
+			}
+		})
+	}
+}
+
+func TestDeleteRecordsHandler(t *testing.T) {
+	values := [][]interface{}{
+		{"ID", "Status"},
+		{1, "keep"},
+		{2, "done"},
+		{3, "done"},
+		{4, "keep"},
+		{5, "done"},
+		{6, "done"},
+		{7, "done"},
+		{"", ""},
+	}
+	gs, ctx, _, requests := newRecordsTestGoogleSheets(t, values)
+	result, err := gs.DeleteRecordsHandler(ctx, nil, &mcp.CallToolParamsFor[DeleteRecordsRequest]{Arguments: DeleteRecordsRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Filter:          map[string]interface{}{"Status": "done"},
+	}})
+	if err != nil {
+		t.Fatalf("DeleteRecordsHandler failed: %v", err)
+	}
+
+	// 連続する行をまとめ、下の行から削除する（行 6-8、行 3-4）
+	var got [][2]int64
+	for _, request := range *requests {
+		if request.DeleteDimension == nil {
+			t.Fatalf("unexpected request: %+v", request)
+		}
+		got = append(got, [2]int64{request.DeleteDimension.Range.StartIndex, request.DeleteDimension.Range.EndIndex})
+	}
+	if want := [][2]int64{{5, 8}, {2, 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("deleted row indexes = %v, want %v", got, want)
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, line := range []string{"Successfully deleted 5 records", "| **3** | 2 | done |", "| **8** | 7 | done |"} {
+		if !strings.Contains(text, line) {
+			t.Errorf("result is missing %q:\n%s", line, text)
+		}
+	}
+
+	// 一致する行がない場合は何も削除しない
+	*requests = nil
+	result, err = gs.DeleteRecordsHandler(ctx, nil, &mcp.CallToolParamsFor[DeleteRecordsRequest]{Arguments: DeleteRecordsRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Filter:          map[string]interface{}{"Status": "missing"},
+	}})
+	if err != nil {
+		t.Fatalf("DeleteRecordsHandler failed: %v", err)
+	}
+	if len(*requests) != 0 || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, "Nothing was deleted") {
+		t.Errorf("rows were deleted without a match: %+v", *requests)
+	}
+}
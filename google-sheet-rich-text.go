@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf16"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// リッチテキストの一部分（書式やリンクはこの部分の文字列だけに適用される）
+type RichTextRun struct {
+	Text          string `json:"text"`
+	Bold          bool   `json:"bold"`
+	Italic        bool   `json:"italic"`
+	Strikethrough bool   `json:"strikethrough"`
+	Underline     bool   `json:"underline"`
+	Color         string `json:"color"`
+	URL           string `json:"url"`
+}
+
+// 書き込むデータのセルにオブジェクトで指定された値
+// {"text": "Docs", "url": "https://..."} はセル全体のリンク、{"runs": [...]} は部分ごとに書式を設定したテキストになる
+type richCellValue struct {
+	Text string        `json:"text"`
+	URL  string        `json:"url"`
+	Runs []RichTextRun `json:"runs"`
+}
+
+// セル単位で書き込むリッチテキスト（行・列は 1-based）
+type richCellUpdate struct {
+	row  int64
+	col  int64
+	cell *sheets.CellData
+}
+
+// 書き込むデータのうちオブジェクトで指定されたセルを取り出す
+// 戻り値の plain はオブジェクトをテキストに置き換えたデータで、通常の値の書き込みと入力規則の確認に使う
+func splitRichCells(origin a1Range, data [][]interface{}) ([][]interface{}, []richCellUpdate, error) {
+	startCol, startRow := origin.start()
+	plain := make([][]interface{}, len(data))
+	var rich []richCellUpdate
+	for i, row := range data {
+		plain[i] = make([]interface{}, len(row))
+		for j, value := range row {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				plain[i][j] = value
+				continue
+			}
+			cellRow, cellCol := startRow+int64(i), startCol+int64(j)
+			cell, text, err := buildRichCellData(object)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid value for cell %s: %w", a1Cell(cellCol, cellRow), err)
+			}
+			plain[i][j] = text
+			rich = append(rich, richCellUpdate{row: cellRow, col: cellCol, cell: cell})
+		}
+	}
+	return plain, rich, nil
+}
+
+// オブジェクトで指定されたセルの値を CellData に変換する（セルのテキストも返す）
+func buildRichCellData(object map[string]interface{}) (*sheets.CellData, string, error) {
+	encoded, err := json.Marshal(object)
+	if err != nil {
+		return nil, "", err
+	}
+	var value richCellValue
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, "", fmt.Errorf("a cell object accepts only text, url and runs: %w", err)
+	}
+
+	runs := value.Runs
+	if len(runs) == 0 {
+		if value.URL == "" {
+			return nil, "", fmt.Errorf("a cell object needs url or runs. Write plain text as a string")
+		}
+		// テキストが省略された場合は URL をそのまま表示する
+		text := value.Text
+		if text == "" {
+			text = value.URL
+		}
+		runs = []RichTextRun{{Text: text, URL: value.URL}}
+	} else if value.Text != "" || value.URL != "" {
+		return nil, "", fmt.Errorf("use either text and url, or runs, not both")
+	}
+
+	var text strings.Builder
+	var formatRuns []*sheets.TextFormatRun
+	var offset int64
+	for _, run := range runs {
+		if run.Text == "" {
+			continue
+		}
+		format, err := buildRunTextFormat(run)
+		if err != nil {
+			return nil, "", err
+		}
+		// 書式のない部分も空の書式の区切りを入れて、前の部分の書式を引き継がないようにする
+		formatRuns = append(formatRuns, &sheets.TextFormatRun{
+			StartIndex:      offset,
+			Format:          format,
+			ForceSendFields: []string{"StartIndex"},
+		})
+		text.WriteString(run.Text)
+		// 位置は UTF-16 のコード単位で数える
+		offset += int64(len(utf16.Encode([]rune(run.Text))))
+	}
+	if text.Len() == 0 {
+		return nil, "", fmt.Errorf("cell text is empty")
+	}
+
+	cellText := text.String()
+	return &sheets.CellData{
+		UserEnteredValue: &sheets.ExtendedValue{StringValue: &cellText},
+		TextFormatRuns:   formatRuns,
+	}, cellText, nil
+}
+
+// リッチテキストの一部分の書式を作成する
+func buildRunTextFormat(run RichTextRun) (*sheets.TextFormat, error) {
+	format := &sheets.TextFormat{
+		Bold:          run.Bold,
+		Italic:        run.Italic,
+		Strikethrough: run.Strikethrough,
+		Underline:     run.Underline,
+	}
+	if run.Color != "" {
+		color, err := parseHexColor(run.Color)
+		if err != nil {
+			return nil, err
+		}
+		format.ForegroundColorStyle = &sheets.ColorStyle{RgbColor: color}
+	}
+	if run.URL != "" {
+		parsed, err := url.Parse(run.URL)
+		if err != nil || parsed.Scheme == "" {
+			return nil, fmt.Errorf("invalid url: '%s'. Use an absolute URL such as 'https://example.com'", run.URL)
+		}
+		format.Link = &sheets.Link{Uri: run.URL}
+	}
+	return format, nil
+}
+
+// リッチテキストのセルを書き込むリクエストを作成する
+func richCellRequests(sheetId int64, cells []richCellUpdate) []*sheets.Request {
+	requests := make([]*sheets.Request, len(cells))
+	for i, c := range cells {
+		requests[i] = &sheets.Request{
+			UpdateCells: &sheets.UpdateCellsRequest{
+				Start: &sheets.GridCoordinate{
+					SheetId:         sheetId,
+					RowIndex:        c.row - 1,
+					ColumnIndex:     c.col - 1,
+					ForceSendFields: []string{"SheetId", "RowIndex", "ColumnIndex"},
+				},
+				Rows:   []*sheets.RowData{{Values: []*sheets.CellData{c.cell}}},
+				Fields: "userEnteredValue,textFormatRuns",
+			},
+		}
+	}
+	return requests
+}
+
+// セル内のリンク
+type cellLink struct {
+	row  int64
+	col  int64
+	text string
+	url  string
+}
+
+// セルに含まれるリンクを取り出す（セル全体のリンクと、テキストの一部分に設定されたリンク）
+func extractCellLinks(row, col int64, cell *sheets.CellData) []cellLink {
+	if cell == nil {
+		return nil
+	}
+	// HYPERLINK 関数やセル全体のリンク（リンクが複数ある場合は設定されない）
+	if cell.Hyperlink != "" {
+		return []cellLink{{row: row, col: col, text: cell.FormattedValue, url: cell.Hyperlink}}
+	}
+
+	var links []cellLink
+	text := utf16.Encode([]rune(cell.FormattedValue))
+	previousURL := ""
+	for i, run := range cell.TextFormatRuns {
+		runURL := ""
+		if run.Format != nil && run.Format.Link != nil {
+			runURL = run.Format.Link.Uri
+		}
+		if runURL == "" {
+			previousURL = ""
+			continue
+		}
+		start, end := min(run.StartIndex, int64(len(text))), int64(len(text))
+		if i+1 < len(cell.TextFormatRuns) {
+			end = min(cell.TextFormatRuns[i+1].StartIndex, end)
+		}
+		part := string(utf16.Decode(text[start:end]))
+		// 同じリンクが続く部分（リンクの一部だけ太字にした場合など）はまとめる
+		if runURL == previousURL {
+			links[len(links)-1].text += part
+			continue
+		}
+		links = append(links, cellLink{row: row, col: col, text: part, url: runURL})
+		previousURL = runURL
+	}
+	return links
+}
+
+// 範囲内のセルのリンクを取得する
+func (gs *GoogleSheets) getCellLinks(ctx context.Context, spreadsheetId, sheetName, rangeStr string) ([]cellLink, error) {
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).
+		Ranges(sheetRange(sheetName, rangeStr)).
+		IncludeGridData(true).
+		Fields("sheets(data(startRow,startColumn,rowData(values(formattedValue,hyperlink,textFormatRuns))))").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hyperlinks: %w", err)
+	}
+
+	var links []cellLink
+	for _, sheet := range spreadsheet.Sheets {
+		for _, data := range sheet.Data {
+			for i, row := range data.RowData {
+				for j, cell := range row.Values {
+					links = append(links, extractCellLinks(data.StartRow+int64(i)+1, data.StartColumn+int64(j)+1, cell)...)
+				}
+			}
+		}
+	}
+	return links, nil
+}
+
+// リンクの一覧を書き出す
+func writeCellLinks(builder *strings.Builder, links []cellLink) {
+	for _, link := range links {
+		builder.WriteString(fmt.Sprintf("- %s: %q → %s\n", a1Cell(link.col, link.row), link.text, link.url))
+	}
+}
+
+// リンクやリッチテキストのセルを書式付きで書き込む
+func (gs *GoogleSheets) writeRichCells(ctx context.Context, spreadsheetId, sheetName string, cells []richCellUpdate) error {
+	if len(cells) == 0 {
+		return nil
+	}
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	if err := gs.batchUpdateSheet(ctx, spreadsheetId, richCellRequests(sheetId, cells)...); err != nil {
+		return fmt.Errorf("failed to write links and rich text: %w", err)
+	}
+	return nil
+}
+
+// リッチテキストの書き込みに失敗した場合のエラー
+// 通常の値は書き込み済みのため、元に戻せるように変更前のデータを添える
+func partialWriteError(err error, previousDetails string) error {
+	return fmt.Errorf("%w\nThe other values were already written, so the cells are partially updated. To undo this change, you can use the previous data.\n\nPrevious data details:\n\n%s", err, previousDetails)
+}
+
+// 書き込み結果のメッセージに添えるリッチテキストのセル数
+func richCellsNote(cells []richCellUpdate) string {
+	if len(cells) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d cells written with links or rich text)", len(cells))
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestSplitRichCells(t *testing.T) {
+	origin, err := parseA1Range("B2:D3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := [][]interface{}{
+		{"plain", map[string]interface{}{"text": "Docs", "url": "https://example.com/docs"}, 42.0},
+		{"=HYPERLINK(\"https://example.com\", \"Home\")", map[string]interface{}{"runs": []interface{}{
+			map[string]interface{}{"text": "😀 Done", "bold": true, "color": "#188038"},
+			map[string]interface{}{"text": " see "},
+			map[string]interface{}{"text": "spec", "url": "https://example.com/spec"},
+		}}},
+	}
+	plain, rich, err := splitRichCells(origin, data)
+	if err != nil {
+		t.Fatalf("splitRichCells failed: %v", err)
+	}
+	if plain[0][0] != "plain" || plain[0][1] != "Docs" || plain[0][2] != 42.0 || plain[1][1] != "😀 Done see spec" {
+		t.Errorf("plain = %v", plain)
+	}
+	if len(rich) != 2 {
+		t.Fatalf("got %d rich cells, want 2", len(rich))
+	}
+
+	link := rich[0]
+	if link.row != 2 || link.col != 3 || *link.cell.UserEnteredValue.StringValue != "Docs" {
+		t.Errorf("link cell = %+v", link)
+	}
+	if runs := link.cell.TextFormatRuns; len(runs) != 1 || runs[0].Format.Link.Uri != "https://example.com/docs" {
+		t.Errorf("link runs = %+v", runs)
+	}
+
+	text := rich[1]
+	if text.row != 3 || text.col != 3 {
+		t.Errorf("rich text cell at (%d, %d), want (3, 3)", text.row, text.col)
+	}
+	runs := text.cell.TextFormatRuns
+	if len(runs) != 3 {
+		t.Fatalf("got %d runs, want 3", len(runs))
+	}
+	// 絵文字は UTF-16 で 2 単位になる
+	wantStarts := []int64{0, 7, 12}
+	for i, run := range runs {
+		if run.StartIndex != wantStarts[i] {
+			t.Errorf("run %d starts at %d, want %d", i, run.StartIndex, wantStarts[i])
+		}
+	}
+	if !runs[0].Format.Bold || formatHexColor(runs[0].Format.ForegroundColorStyle.RgbColor) != "#188038" {
+		t.Errorf("run 0 format = %+v", runs[0].Format)
+	}
+	if runs[1].Format.Bold || runs[1].Format.Link != nil {
+		t.Errorf("run 1 should have no format, got %+v", runs[1].Format)
+	}
+	if runs[2].Format.Link == nil || runs[2].Format.Link.Uri != "https://example.com/spec" {
+		t.Errorf("run 2 format = %+v", runs[2].Format)
+	}
+}
+
+func TestBuildRichCellDataErrors(t *testing.T) {
+	invalid := []map[string]interface{}{
+		{"text": "no link"},
+		{"text": "Docs", "link": "https://example.com"},
+		{"text": "Docs", "url": "example.com"},
+		{"url": "https://example.com", "runs": []interface{}{map[string]interface{}{"text": "a"}}},
+		{"runs": []interface{}{map[string]interface{}{"text": ""}}},
+		{"runs": []interface{}{map[string]interface{}{"text": "a", "color": "red"}}},
+	}
+	for _, object := range invalid {
+		if _, _, err := buildRichCellData(object); err == nil {
+			t.Errorf("buildRichCellData(%v) should fail", object)
+		}
+	}
+
+	// テキストを省略した場合は URL を表示する
+	cell, text, err := buildRichCellData(map[string]interface{}{"url": "https://example.com"})
+	if err != nil || text != "https://example.com" || *cell.UserEnteredValue.StringValue != text {
+		t.Errorf("buildRichCellData without text = %q, %v", text, err)
+	}
+}
+
+func TestExtractCellLinks(t *testing.T) {
+	link := func(uri string, bold bool) *sheets.TextFormat {
+		return &sheets.TextFormat{Bold: bold, Link: &sheets.Link{Uri: uri}}
+	}
+	tests := []struct {
+		name string
+		cell *sheets.CellData
+		want []cellLink
+	}{
+		{"nil", nil, nil},
+		{"plain", &sheets.CellData{FormattedValue: "text"}, nil},
+		{
+			"whole cell",
+			&sheets.CellData{FormattedValue: "Home", Hyperlink: "https://example.com"},
+			[]cellLink{{row: 2, col: 3, text: "Home", url: "https://example.com"}},
+		},
+		{
+			"runs",
+			&sheets.CellData{
+				FormattedValue: "😀 see spec and docs",
+				TextFormatRuns: []*sheets.TextFormatRun{
+					{StartIndex: 0, Format: &sheets.TextFormat{Bold: true}},
+					{StartIndex: 7, Format: link("https://example.com/spec", false)},
+					{StartIndex: 9, Format: link("https://example.com/spec", true)},
+					{StartIndex: 11, Format: &sheets.TextFormat{}},
+					{StartIndex: 16, Format: link("https://example.com/docs", false)},
+				},
+			},
+			[]cellLink{
+				{row: 2, col: 3, text: "spec", url: "https://example.com/spec"},
+				{row: 2, col: 3, text: "docs", url: "https://example.com/docs"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractCellLinks(2, 3, tt.cell)
+			if len(got) != len(tt.want) {
+				t.Fatalf("extractCellLinks = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("link %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateCellsHandlerRichCellFailure(t *testing.T) {
+	gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1"}}})
+		case r.URL.Path == "/v4/spreadsheets/ss1":
+			writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1"}},
+			}})
+		case r.URL.Path == "/v4/spreadsheets/ss1/values/Sheet1!A1:B1" && r.Method == http.MethodGet:
+			writeTestJSON(t, w, &sheets.ValueRange{Values: [][]interface{}{{"old", "link"}}})
+		case r.URL.Path == "/v4/spreadsheets/ss1/values/Sheet1!A1:B1" && r.Method == http.MethodPut:
+			writeTestJSON(t, w, &sheets.UpdateValuesResponse{UpdatedCells: 2})
+		case r.URL.Path == "/v4/spreadsheets/ss1:batchUpdate":
+			// リッチテキストの書き込みだけが失敗する
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": 400, "message": "Invalid requests[0].updateCells"}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	})
+
+	_, err := gs.UpdateCellsHandler(ctx, nil, &mcp.CallToolParamsFor[UpdateCellsRequest]{Arguments: UpdateCellsRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Range:           "A1:B1",
+		Data:            [][]interface{}{{"new", map[string]interface{}{"text": "Docs", "url": "https://example.com/docs"}}},
+	}})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	// 通常の値は書き込み済みのため、元に戻すための変更前のデータがエラーに含まれる
+	if !strings.Contains(err.Error(), "partially updated") || !strings.Contains(err.Error(), "| old | link |") {
+		t.Errorf("error does not include the previous data: %v", err)
+	}
+}
+
+func TestGetSheetDataHandlerLinkFailure(t *testing.T) {
+	var linkRanges []string
+	gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1"}}})
+		case "/v4/spreadsheets/ss1":
+			if r.URL.Query().Get("includeGridData") != "true" {
+				writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{
+					{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1", GridProperties: &sheets.GridProperties{RowCount: 1000, ColumnCount: 26}}},
+				}})
+				return
+			}
+			// リンクの取得だけが失敗する
+			linkRanges = append(linkRanges, r.URL.Query()["ranges"]...)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": 400, "message": "Response too large"}}`))
+		case "/v4/spreadsheets/ss1/values/Sheet1!A1:B2":
+			writeTestJSON(t, w, &sheets.ValueRange{Values: [][]interface{}{{"Name", "Site"}, {"Docs", "example.com"}}})
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+
+	result, err := gs.GetSheetDataHandler(ctx, nil, &mcp.CallToolParamsFor[GetSheetDataRequest]{Arguments: GetSheetDataRequest{
+		SpreadsheetName: "Budget",
+		SheetName:       "Sheet1",
+		Range:           "A1:B2",
+	}})
+	if err != nil {
+		t.Fatalf("GetSheetDataHandler failed: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "| Docs | example.com |") || !strings.Contains(text, "Hyperlinks could not be read") {
+		t.Errorf("unexpected result:\n%s", text)
+	}
+	// リンクは読み取った範囲だけから取得する
+	if len(linkRanges) != 1 || linkRanges[0] != "Sheet1!A1:B2" {
+		t.Errorf("links were read from %v, want [Sheet1!A1:B2]", linkRanges)
+	}
+}
@@ -0,0 +1,508 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+// 条件の種類（BooleanCondition.Type）
+var booleanConditionTypes = []any{
+	"NUMBER_GREATER", "NUMBER_GREATER_THAN_EQ", "NUMBER_LESS", "NUMBER_LESS_THAN_EQ",
+	"NUMBER_EQ", "NUMBER_NOT_EQ", "NUMBER_BETWEEN", "NUMBER_NOT_BETWEEN",
+	"TEXT_CONTAINS", "TEXT_NOT_CONTAINS", "TEXT_STARTS_WITH", "TEXT_ENDS_WITH", "TEXT_EQ", "TEXT_IS_EMAIL", "TEXT_IS_URL",
+	"DATE_EQ", "DATE_BEFORE", "DATE_AFTER", "DATE_ON_OR_BEFORE", "DATE_ON_OR_AFTER", "DATE_BETWEEN", "DATE_NOT_BETWEEN", "DATE_IS_VALID",
+	"ONE_OF_LIST", "ONE_OF_RANGE", "BLANK", "NOT_BLANK", "CUSTOM_FORMULA", "BOOLEAN",
+}
+
+// 並べ替えのキー
+type SortKey struct {
+	Column string `json:"column"`
+	Order  string `json:"order"`
+}
+
+var sortKeysSchema = &jsonschema.Schema{
+	Type:        "array",
+	Description: "Sort keys in priority order. Example: [{\"column\": \"Region\"}, {\"column\": \"C\", \"order\": \"desc\"}]",
+	Items: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"column": {
+				Type:        "string",
+				Description: "Column letter (e.g. 'C') or header name",
+			},
+			"order": {
+				Type:        "string",
+				Description: "Sort order. Default: 'asc'",
+				Enum:        []any{"asc", "desc"},
+			},
+		},
+		Required: []string{"column"},
+	},
+}
+
+type SortRangeRequest struct {
+	SpreadsheetName  string    `json:"spreadsheet_name"`
+	SheetName        string    `json:"sheet_name"`
+	Range            string    `json:"range"`
+	SortKeys         []SortKey `json:"sort_keys"`
+	HasHeaderRow     bool      `json:"has_header_row"`
+	IgnoreProtection bool      `json:"ignore_protection"`
+}
+
+var SortRangeInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to sort",
+		},
+		"range": {
+			Type:        "string",
+			Description: "Cell range in A1 notation to sort. Examples: 'A1:D100', 'A:D'. Leave empty to sort the whole sheet",
+		},
+		"sort_keys": sortKeysSchema,
+		"has_header_row": {
+			Type:        "boolean",
+			Description: "The first row of the range is a header row: it is kept in place, and its names can be used as sort key columns",
+		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Write even if the target overlaps a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "sort_keys"},
+}
+
+// フィルターの条件
+type FilterCriterion struct {
+	Column       string   `json:"column"`
+	HiddenValues []string `json:"hidden_values"`
+	Condition    string   `json:"condition"`
+	Values       []string `json:"values"`
+}
+
+type SetBasicFilterRequest struct {
+	SpreadsheetName string            `json:"spreadsheet_name"`
+	SheetName       string            `json:"sheet_name"`
+	Range           string            `json:"range"`
+	Criteria        []FilterCriterion `json:"criteria"`
+	SortKeys        []SortKey         `json:"sort_keys"`
+}
+
+var SetBasicFilterInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to filter",
+		},
+		"range": {
+			Type:        "string",
+			Description: "Cell range in A1 notation to filter, including the header row. Examples: 'A1:D100', 'A:D'. Leave empty to filter the whole sheet",
+		},
+		"criteria": {
+			Type:        "array",
+			Description: "Filter criteria per column. Example: [{\"column\": \"Status\", \"hidden_values\": [\"Done\"]}, {\"column\": \"D\", \"condition\": \"NUMBER_GREATER\", \"values\": [\"100\"]}]",
+			Items: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"column": {
+						Type:        "string",
+						Description: "Column letter (e.g. 'C') or header name",
+					},
+					"hidden_values": {
+						Type:        "array",
+						Description: "Values to hide",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+					"condition": {
+						Type:        "string",
+						Description: "Condition that shown rows must satisfy",
+						Enum:        booleanConditionTypes,
+					},
+					"values": {
+						Type:        "array",
+						Description: "Values for the condition. Example: [\"100\"] for NUMBER_GREATER, [\"=$D2>$E2\"] for CUSTOM_FORMULA",
+						Items:       &jsonschema.Schema{Type: "string"},
+					},
+				},
+				Required: []string{"column"},
+			},
+		},
+		"sort_keys": sortKeysSchema,
+	},
+	Required: []string{"spreadsheet_name", "sheet_name"},
+}
+
+type ClearBasicFilterRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+}
+
+var ClearBasicFilterInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab whose filter is removed",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name"},
+}
+
+// 操作対象の GridRange を取得する（範囲が空の場合はシート全体）
+func sheetGridRange(sheetId int64, rangeStr string) (*sheets.GridRange, error) {
+	if rangeStr == "" {
+		return &sheets.GridRange{SheetId: sheetId}, nil
+	}
+	return gridRangeFromA1(sheetId, rangeStr)
+}
+
+// 範囲の1行目（ヘッダー行）の値を取得する
+func (gs *GoogleSheets) getHeaderRow(ctx context.Context, spreadsheetId, sheetName string, gridRange *sheets.GridRange) ([]interface{}, error) {
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+	// 範囲の1行目のみ（列の終端が省略されている場合はシートの端まで）
+	header := a1RangeFromGridRange(gridRange)
+	header.sheet = sheetName
+	header.startCol = gridRange.StartColumnIndex + 1
+	header.startRow = gridRange.StartRowIndex + 1
+	header.endRow = header.startRow
+	resp, err := service.Spreadsheets.Values.Get(spreadsheetId, header.String()).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header row: %w", err)
+	}
+	if len(resp.Values) == 0 {
+		return nil, nil
+	}
+	return resp.Values[0], nil
+}
+
+// 列の指定（ヘッダー名または列文字）をシート上の列番号（0-based）に変換する
+// ヘッダー名を優先し、一致しない場合は列文字として解釈する
+func resolveColumnIndex(column string, header []interface{}, startColumnIndex int64) (int64, error) {
+	for i, name := range header {
+		if fmt.Sprintf("%v", name) == column {
+			return startColumnIndex + int64(i), nil
+		}
+	}
+	for i, name := range header {
+		if strings.EqualFold(fmt.Sprintf("%v", name), column) {
+			return startColumnIndex + int64(i), nil
+		}
+	}
+	// 列文字は最大3文字（ZZZ 列まで）
+	col, err := parseColumnLetters(column)
+	if err != nil || col == 0 {
+		if len(header) > 0 {
+			return 0, fmt.Errorf("column not found: '%s'. Specify a column letter or one of the header names", column)
+		}
+		return 0, fmt.Errorf("column not found: '%s'. Specify a column letter", column)
+	}
+	return col - 1, nil
+}
+
+// 並べ替えのキーを SortSpec に変換する
+func sortSpecsFromKeys(keys []SortKey, header []interface{}, startColumnIndex int64) ([]*sheets.SortSpec, error) {
+	var specs []*sheets.SortSpec
+	for _, key := range keys {
+		index, err := resolveColumnIndex(key.Column, header, startColumnIndex)
+		if err != nil {
+			return nil, err
+		}
+		order := "ASCENDING"
+		switch strings.ToLower(key.Order) {
+		case "", "asc":
+		case "desc":
+			order = "DESCENDING"
+		default:
+			return nil, fmt.Errorf("invalid sort order: '%s'. Use 'asc' or 'desc'", key.Order)
+		}
+		specs = append(specs, &sheets.SortSpec{
+			DimensionIndex:  index,
+			SortOrder:       order,
+			ForceSendFields: []string{"DimensionIndex"},
+		})
+	}
+	return specs, nil
+}
+
+// 並べ替えのキーを説明する文字列
+func describeSortKeys(keys []SortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		order := "asc"
+		if strings.EqualFold(key.Order, "desc") {
+			order = "desc"
+		}
+		parts[i] = fmt.Sprintf("%s %s", key.Column, order)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// 範囲の説明（空の場合はシート全体）
+func describeRange(rangeStr string) string {
+	if rangeStr == "" {
+		return "the whole sheet"
+	}
+	return "range " + rangeStr
+}
+
+func (gs *GoogleSheets) SortRangeHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[SortRangeRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	if len(request.SortKeys) == 0 {
+		return nil, fmt.Errorf("sort_keys cannot be empty")
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, request.SheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+	gridRange, err := sheetGridRange(sheetId, rangeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// ヘッダー行がある場合はヘッダー名を取得し、並べ替えの対象から除く
+	var header []interface{}
+	if request.HasHeaderRow {
+		header, err = gs.getHeaderRow(ctx, spreadsheetId, request.SheetName, gridRange)
+		if err != nil {
+			return nil, err
+		}
+		gridRange.StartRowIndex++
+	}
+
+	specs, err := sortSpecsFromKeys(request.SortKeys, header, gridRange.StartColumnIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// 保護された範囲への書き込みを確認
+	if !request.IgnoreProtection {
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, request.SheetName, []string{rangeStr}); err != nil {
+			return nil, err
+		}
+	}
+
+	// 並べ替えを実行
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				SortRange: &sheets.SortRangeRequest{
+					Range:     gridRange,
+					SortSpecs: specs,
+				},
+			},
+		},
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sort range: %w", err)
+	}
+
+	message := fmt.Sprintf("Successfully sorted %s of sheet '%s' in spreadsheet '%s' by %s",
+		describeRange(request.Range), request.SheetName, request.SpreadsheetName, describeSortKeys(request.SortKeys))
+	if request.HasHeaderRow {
+		message += " (header row kept in place)"
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) SetBasicFilterHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[SetBasicFilterRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, request.SheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+	gridRange, err := sheetGridRange(sheetId, rangeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// フィルターの範囲の1行目はヘッダー行
+	var header []interface{}
+	if len(request.Criteria) > 0 || len(request.SortKeys) > 0 {
+		header, err = gs.getHeaderRow(ctx, spreadsheetId, request.SheetName, gridRange)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	filter := &sheets.BasicFilter{Range: gridRange}
+	for _, criterion := range request.Criteria {
+		index, err := resolveColumnIndex(criterion.Column, header, gridRange.StartColumnIndex)
+		if err != nil {
+			return nil, err
+		}
+		criteria := &sheets.FilterCriteria{HiddenValues: criterion.HiddenValues}
+		if criterion.Condition != "" {
+			criteria.Condition = &sheets.BooleanCondition{Type: criterion.Condition}
+			for _, value := range criterion.Values {
+				criteria.Condition.Values = append(criteria.Condition.Values, &sheets.ConditionValue{UserEnteredValue: value})
+			}
+		} else if len(criterion.HiddenValues) == 0 {
+			return nil, fmt.Errorf("criteria for column '%s' must have hidden_values or a condition", criterion.Column)
+		}
+		filter.FilterSpecs = append(filter.FilterSpecs, &sheets.FilterSpec{
+			ColumnIndex:     index,
+			FilterCriteria:  criteria,
+			ForceSendFields: []string{"ColumnIndex"},
+		})
+	}
+	if filter.SortSpecs, err = sortSpecsFromKeys(request.SortKeys, header, gridRange.StartColumnIndex); err != nil {
+		return nil, err
+	}
+
+	// フィルターを設定（既存のフィルターは置き換えられる）
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				SetBasicFilter: &sheets.SetBasicFilterRequest{
+					Filter: filter,
+				},
+			},
+		},
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set basic filter: %w", err)
+	}
+
+	message := fmt.Sprintf("Successfully set a filter on %s of sheet '%s' in spreadsheet '%s'",
+		describeRange(request.Range), request.SheetName, request.SpreadsheetName)
+	for _, criterion := range request.Criteria {
+		if len(criterion.HiddenValues) > 0 {
+			message += fmt.Sprintf("\n- %s: hide %s", criterion.Column, strings.Join(criterion.HiddenValues, ", "))
+		}
+		if criterion.Condition != "" {
+			message += fmt.Sprintf("\n- %s: %s %s", criterion.Column, criterion.Condition, strings.Join(criterion.Values, ", "))
+		}
+	}
+	if len(request.SortKeys) > 0 {
+		message += fmt.Sprintf("\nSorted by %s", describeSortKeys(request.SortKeys))
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) ClearBasicFilterHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[ClearBasicFilterRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				ClearBasicFilter: &sheets.ClearBasicFilterRequest{
+					SheetId:         sheetId,
+					ForceSendFields: []string{"SheetId"},
+				},
+			},
+		},
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear basic filter: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Successfully cleared the filter of sheet '%s' in spreadsheet '%s'. All rows are shown again.",
+					request.SheetName, request.SpreadsheetName),
+			},
+		},
+	}, nil
+}
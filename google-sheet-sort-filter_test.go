@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestResolveColumnIndex(t *testing.T) {
+	header := []interface{}{"Name", "Region", "ID"}
+	tests := []struct {
+		column  string
+		header  []interface{}
+		start   int64
+		want    int64
+		wantErr bool
+	}{
+		{column: "Region", header: header, start: 1, want: 2},
+		{column: "region", header: header, start: 0, want: 1},
+		{column: "ID", header: header, start: 0, want: 2},
+		{column: "E", header: header, start: 0, want: 4},
+		{column: "aa", header: nil, start: 0, want: 26},
+		{column: "Revenue", header: header, wantErr: true},
+		{column: "A1", header: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := resolveColumnIndex(tt.column, tt.header, tt.start)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("resolveColumnIndex(%q) = %d, want error", tt.column, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("resolveColumnIndex(%q) = %d, %v, want %d", tt.column, got, err, tt.want)
+		}
+	}
+}
+
+func TestSortSpecsFromKeys(t *testing.T) {
+	specs, err := sortSpecsFromKeys([]SortKey{{Column: "Region"}, {Column: "C", Order: "DESC"}}, []interface{}{"Name", "Region"}, 0)
+	if err != nil {
+		t.Fatalf("sortSpecsFromKeys error: %v", err)
+	}
+	if len(specs) != 2 || specs[0].DimensionIndex != 1 || specs[0].SortOrder != "ASCENDING" ||
+		specs[1].DimensionIndex != 2 || specs[1].SortOrder != "DESCENDING" {
+		t.Errorf("unexpected sort specs: %+v, %+v", specs[0], specs[1])
+	}
+	if _, err := sortSpecsFromKeys([]SortKey{{Column: "A", Order: "up"}}, nil, 0); err == nil {
+		t.Errorf("sortSpecsFromKeys with invalid order: expected error")
+	}
+}
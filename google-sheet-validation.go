@@ -0,0 +1,684 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/sheets/v4"
+)
+
+// 数値・日付の比較演算子と BooleanCondition の種類の対応
+var (
+	numberConditionTypes = map[string]string{
+		"between":          "NUMBER_BETWEEN",
+		"not_between":      "NUMBER_NOT_BETWEEN",
+		"greater":          "NUMBER_GREATER",
+		"greater_or_equal": "NUMBER_GREATER_THAN_EQ",
+		"less":             "NUMBER_LESS",
+		"less_or_equal":    "NUMBER_LESS_THAN_EQ",
+		"equal":            "NUMBER_EQ",
+		"not_equal":        "NUMBER_NOT_EQ",
+	}
+	dateConditionTypes = map[string]string{
+		"between":          "DATE_BETWEEN",
+		"not_between":      "DATE_NOT_BETWEEN",
+		"greater":          "DATE_AFTER",
+		"greater_or_equal": "DATE_ON_OR_AFTER",
+		"less":             "DATE_BEFORE",
+		"less_or_equal":    "DATE_ON_OR_BEFORE",
+		"equal":            "DATE_EQ",
+	}
+)
+
+type SetDataValidationRequest struct {
+	SpreadsheetName string   `json:"spreadsheet_name"`
+	SheetName       string   `json:"sheet_name"`
+	Range           string   `json:"range"`
+	RuleType        string   `json:"rule_type"`
+	Values          []string `json:"values"`
+	SourceRange     string   `json:"source_range"`
+	Operator        string   `json:"operator"`
+	Mode            string   `json:"mode"`
+	HideDropdown    bool     `json:"hide_dropdown"`
+	InputMessage    string   `json:"input_message"`
+}
+
+var SetDataValidationInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to modify",
+		},
+		"range": {
+			Type:        "string",
+			Description: "Cell range in A1 notation to apply the rule to. Examples: 'C2:C100', 'D:D'",
+		},
+		"rule_type": {
+			Type: "string",
+			Description: "Kind of rule: 'list' (dropdown of values), 'range' (dropdown of the values in source_range), " +
+				"'number' / 'date' (bounds given by operator and values), 'checkbox', 'custom_formula' (values[0] is a formula that must be TRUE), " +
+				"or 'clear' to remove data validation from the range",
+			Enum: []any{"list", "range", "number", "date", "checkbox", "custom_formula", "clear"},
+		},
+		"values": {
+			Type: "array",
+			Description: "Rule values. 'list': the allowed values. 'number'/'date': one bound, or two for between/not_between (dates as YYYY-MM-DD). " +
+				"'checkbox': optionally the checked and unchecked values. 'custom_formula': the formula, e.g. '=LEN(A2)<=10'",
+			Items: &jsonschema.Schema{Type: "string"},
+		},
+		"source_range": {
+			Type:        "string",
+			Description: "For 'range': the range containing the allowed values, optionally with a sheet name. Example: 'Options!A2:A20'",
+		},
+		"operator": {
+			Type:        "string",
+			Description: "For 'number' and 'date': how values are compared. Default: 'between'",
+			Enum:        []any{"between", "not_between", "greater", "greater_or_equal", "less", "less_or_equal", "equal", "not_equal"},
+		},
+		"mode": {
+			Type:        "string",
+			Description: "'strict' rejects invalid input, 'warn' accepts it and shows a warning. Default: 'strict'",
+			Enum:        []any{"strict", "warn"},
+		},
+		"hide_dropdown": {
+			Type:        "boolean",
+			Description: "For 'list' and 'range': do not show a dropdown arrow in the cells",
+		},
+		"input_message": {
+			Type:        "string",
+			Description: "Help text shown when a cell in the range is selected",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name", "range", "rule_type"},
+}
+
+type GetDataValidationRequest struct {
+	SpreadsheetName string `json:"spreadsheet_name"`
+	SheetName       string `json:"sheet_name"`
+	Range           string `json:"range"`
+}
+
+var GetDataValidationInputSchema = &jsonschema.Schema{
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"spreadsheet_name": {
+			Type:        "string",
+			Description: "Name of the Google Spreadsheet file",
+		},
+		"sheet_name": {
+			Type:        "string",
+			Description: "Name or numeric ID of the sheet/tab to inspect",
+		},
+		"range": {
+			Type:        "string",
+			Description: "Cell range in A1 notation to inspect. Leave empty to inspect the whole sheet",
+		},
+	},
+	Required: []string{"spreadsheet_name", "sheet_name"},
+}
+
+// リクエストから入力規則を作成する（rule_type が 'clear' の場合は nil）
+func buildValidationRule(request SetDataValidationRequest) (*sheets.DataValidationRule, error) {
+	condition := &sheets.BooleanCondition{}
+	values := request.Values
+	switch request.RuleType {
+	case "clear":
+		return nil, nil
+	case "list":
+		if len(values) == 0 {
+			return nil, fmt.Errorf("values must contain at least one allowed value for a 'list' rule")
+		}
+		condition.Type = "ONE_OF_LIST"
+	case "range":
+		if request.SourceRange == "" {
+			return nil, fmt.Errorf("source_range must be specified for a 'range' rule")
+		}
+		condition.Type = "ONE_OF_RANGE"
+		values = []string{"=" + strings.TrimPrefix(request.SourceRange, "=")}
+	case "number", "date":
+		operator := request.Operator
+		if operator == "" {
+			operator = "between"
+		}
+		types := numberConditionTypes
+		if request.RuleType == "date" {
+			types = dateConditionTypes
+		}
+		conditionType, ok := types[operator]
+		if !ok {
+			return nil, fmt.Errorf("operator '%s' is not supported for a '%s' rule", operator, request.RuleType)
+		}
+		want := 1
+		if operator == "between" || operator == "not_between" {
+			want = 2
+		}
+		if len(values) != want {
+			return nil, fmt.Errorf("operator '%s' needs %d values, got %d", operator, want, len(values))
+		}
+		condition.Type = conditionType
+	case "checkbox":
+		if len(values) != 0 && len(values) != 2 {
+			return nil, fmt.Errorf("a 'checkbox' rule takes no values, or the checked and unchecked values")
+		}
+		condition.Type = "BOOLEAN"
+	case "custom_formula":
+		if len(values) != 1 {
+			return nil, fmt.Errorf("a 'custom_formula' rule needs exactly one formula in values")
+		}
+		condition.Type = "CUSTOM_FORMULA"
+	default:
+		return nil, fmt.Errorf("invalid rule type: '%s'", request.RuleType)
+	}
+	for _, value := range values {
+		condition.Values = append(condition.Values, &sheets.ConditionValue{UserEnteredValue: value})
+	}
+
+	rule := &sheets.DataValidationRule{
+		Condition:    condition,
+		InputMessage: request.InputMessage,
+	}
+	switch request.Mode {
+	case "", "strict":
+		rule.Strict = true
+	case "warn":
+	default:
+		return nil, fmt.Errorf("invalid mode: '%s'. Use 'strict' or 'warn'", request.Mode)
+	}
+	if request.RuleType == "list" || request.RuleType == "range" {
+		rule.ShowCustomUi = !request.HideDropdown
+	}
+	return rule, nil
+}
+
+// 入力規則を説明する文字列
+func describeValidationRule(rule *sheets.DataValidationRule) string {
+	if rule == nil || rule.Condition == nil {
+		return "none"
+	}
+	values := make([]string, len(rule.Condition.Values))
+	for i, value := range rule.Condition.Values {
+		values[i] = value.UserEnteredValue
+		if value.RelativeDate != "" {
+			values[i] = value.RelativeDate
+		}
+	}
+	description := rule.Condition.Type
+	if len(values) > 0 {
+		description += " [" + strings.Join(values, ", ") + "]"
+	}
+	var options []string
+	if rule.Strict {
+		options = append(options, "strict")
+	} else {
+		options = append(options, "warn")
+	}
+	if rule.ShowCustomUi {
+		options = append(options, "dropdown")
+	}
+	if rule.InputMessage != "" {
+		options = append(options, fmt.Sprintf("message: %q", rule.InputMessage))
+	}
+	return description + " (" + strings.Join(options, ", ") + ")"
+}
+
+// 入力規則が設定されたセル（行・列は 1-based）
+type cellValidation struct {
+	row  int64
+	col  int64
+	rule *sheets.DataValidationRule
+}
+
+// 範囲内のセルの入力規則を取得する（範囲が空の場合はシート全体）
+func (gs *GoogleSheets) getCellValidations(ctx context.Context, spreadsheetId, sheetName, rangeStr string) ([]cellValidation, error) {
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
+	}
+
+	target := sheetRange(sheetName, rangeStr)
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).
+		Ranges(target).
+		IncludeGridData(true).
+		Fields("sheets(data(startRow,startColumn,rowData(values(dataValidation))))").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data validation rules: %w", err)
+	}
+
+	var cells []cellValidation
+	for _, sheet := range spreadsheet.Sheets {
+		for _, data := range sheet.Data {
+			for i, row := range data.RowData {
+				for j, cell := range row.Values {
+					if cell == nil || cell.DataValidation == nil {
+						continue
+					}
+					cells = append(cells, cellValidation{
+						row:  data.StartRow + int64(i) + 1,
+						col:  data.StartColumn + int64(j) + 1,
+						rule: cell.DataValidation,
+					})
+				}
+			}
+		}
+	}
+	return cells, nil
+}
+
+// セルの集まりを A1 表記の範囲にまとめる（列ごとの連続した行をまとめ、同じ行範囲の隣接する列をさらにまとめる）
+func compressCellRanges(cells [][2]int64) []string {
+	sort.Slice(cells, func(a, b int) bool {
+		if cells[a][1] != cells[b][1] {
+			return cells[a][1] < cells[b][1]
+		}
+		return cells[a][0] < cells[b][0]
+	})
+
+	// 列ごとの連続した行
+	type run struct{ col, firstCol, startRow, endRow int64 }
+	var runs []run
+	for _, cell := range cells {
+		row, col := cell[0], cell[1]
+		if n := len(runs); n > 0 && runs[n-1].col == col && runs[n-1].endRow+1 == row {
+			runs[n-1].endRow = row
+			continue
+		}
+		runs = append(runs, run{col: col, firstCol: col, startRow: row, endRow: row})
+	}
+
+	// 同じ行範囲で隣接する列をまとめる
+	var merged []run
+	for _, r := range runs {
+		found := false
+		for i := range merged {
+			if merged[i].col+1 == r.col && merged[i].startRow == r.startRow && merged[i].endRow == r.endRow {
+				merged[i].col = r.col
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, r)
+		}
+	}
+
+	ranges := make([]string, len(merged))
+	for i, r := range merged {
+		ranges[i] = a1Range{startCol: r.firstCol, startRow: r.startRow, endCol: r.col, endRow: r.endRow}.String()
+	}
+	return ranges
+}
+
+func (gs *GoogleSheets) SetDataValidationHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[SetDataValidationRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	rule, err := buildValidationRule(request)
+	if err != nil {
+		return nil, err
+	}
+
+	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, request.SheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, request.SheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+	gridRange, err := gridRangeFromA1(sheetId, rangeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gs.batchUpdateSheet(ctx, spreadsheetId, &sheets.Request{
+		SetDataValidation: &sheets.SetDataValidationRequest{
+			Range: gridRange,
+			Rule:  rule,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set data validation: %w", err)
+	}
+
+	var message string
+	if rule == nil {
+		message = fmt.Sprintf("Successfully removed data validation from %s in sheet '%s' of spreadsheet '%s'",
+			request.Range, request.SheetName, request.SpreadsheetName)
+	} else {
+		message = fmt.Sprintf("Successfully set data validation on %s in sheet '%s' of spreadsheet '%s': %s",
+			request.Range, request.SheetName, request.SpreadsheetName, describeValidationRule(rule))
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: message,
+			},
+		},
+	}, nil
+}
+
+func (gs *GoogleSheets) GetDataValidationHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[GetDataValidationRequest]) (*mcp.CallToolResultFor[any], error) {
+	request := params.Arguments
+	// スプレッドシートIDを取得
+	spreadsheetId, err := gs.getSpreadsheetIdWithContext(ctx, request.SpreadsheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
+	}
+
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, request.SheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+	cells, err := gs.getCellValidations(ctx, spreadsheetId, request.SheetName, rangeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同じ規則のセルをまとめる
+	var descriptions []string
+	groups := make(map[string][][2]int64)
+	for _, cell := range cells {
+		description := describeValidationRule(cell.rule)
+		if _, ok := groups[description]; !ok {
+			descriptions = append(descriptions, description)
+		}
+		groups[description] = append(groups[description], [2]int64{cell.row, cell.col})
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Data validation rules in %s of sheet '%s' in spreadsheet '%s':\n\n",
+		describeRange(request.Range), request.SheetName, request.SpreadsheetName))
+	if len(descriptions) == 0 {
+		result.WriteString("No data validation rules found.\n")
+	}
+	for _, description := range descriptions {
+		result.WriteString(fmt.Sprintf("- %s: %s\n", strings.Join(compressCellRanges(groups[description]), ", "), description))
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: result.String(),
+			},
+		},
+	}, nil
+}
+
+// 入力規則に違反する書き込み
+type validationViolation struct {
+	cell   string
+	value  interface{}
+	rule   string
+	reason string
+}
+
+// 書き込む値が入力規則を満たすか評価する
+// 違反している場合は理由を返す。ローカルで評価できない規則（カスタム数式など）は checked が false になる
+// rangeValues は ONE_OF_RANGE の参照範囲の値を取得する関数
+func evaluateValidationRule(rule *sheets.DataValidationRule, value interface{}, rangeValues func(string) ([]string, error)) (reason string, checked bool, err error) {
+	if rule == nil || rule.Condition == nil || isNullValue(value) {
+		return "", true, nil
+	}
+	text := formatQueryValue(value)
+	// 数式の結果はローカルでは評価できない
+	if strings.HasPrefix(text, "=") {
+		return "", false, nil
+	}
+
+	var bounds []string
+	for _, conditionValue := range rule.Condition.Values {
+		// 相対日付や数式の条件値はローカルでは評価できない
+		if conditionValue.RelativeDate != "" || (strings.HasPrefix(conditionValue.UserEnteredValue, "=") && rule.Condition.Type != "ONE_OF_RANGE") {
+			return "", false, nil
+		}
+		bounds = append(bounds, conditionValue.UserEnteredValue)
+	}
+
+	conditionType := rule.Condition.Type
+	switch {
+	case conditionType == "ONE_OF_LIST" || conditionType == "ONE_OF_RANGE":
+		allowed := bounds
+		if conditionType == "ONE_OF_RANGE" {
+			if len(bounds) != 1 {
+				return "", false, nil
+			}
+			if allowed, err = rangeValues(strings.TrimPrefix(bounds[0], "=")); err != nil {
+				return "", false, err
+			}
+		}
+		for _, candidate := range allowed {
+			if strings.EqualFold(strings.TrimSpace(candidate), strings.TrimSpace(text)) {
+				return "", true, nil
+			}
+		}
+		if len(allowed) > 10 {
+			return fmt.Sprintf("not one of the %d allowed values", len(allowed)), true, nil
+		}
+		return fmt.Sprintf("not one of: %s", strings.Join(allowed, ", ")), true, nil
+
+	case conditionType == "BOOLEAN":
+		if len(bounds) == 2 {
+			if strings.EqualFold(text, bounds[0]) || strings.EqualFold(text, bounds[1]) {
+				return "", true, nil
+			}
+			return fmt.Sprintf("checkbox value must be '%s' or '%s'", bounds[0], bounds[1]), true, nil
+		}
+		if strings.EqualFold(text, "TRUE") || strings.EqualFold(text, "FALSE") {
+			return "", true, nil
+		}
+		return "checkbox value must be TRUE or FALSE", true, nil
+
+	case strings.HasPrefix(conditionType, "NUMBER_"):
+		n, ok := toQueryNumber(value)
+		if !ok {
+			return "not a number", true, nil
+		}
+		numbers := make([]float64, len(bounds))
+		for i, bound := range bounds {
+			if numbers[i], ok = toQueryNumber(bound); !ok {
+				return "", false, nil
+			}
+		}
+		compare := func(i int) int {
+			return compareQueryValues(n, numbers[i])
+		}
+		return evaluateComparison(strings.TrimPrefix(conditionType, "NUMBER_"), compare, len(numbers), text, bounds)
+
+	case strings.HasPrefix(conditionType, "DATE_"):
+		if conditionType == "DATE_IS_VALID" {
+			if _, ok := parseDate(text); !ok {
+				return "not a valid date", true, nil
+			}
+			return "", true, nil
+		}
+		date, ok := parseDate(text)
+		if !ok {
+			return "not a recognizable date (use YYYY-MM-DD)", true, nil
+		}
+		for _, bound := range bounds {
+			if _, ok := parseDate(bound); !ok {
+				return "", false, nil
+			}
+		}
+		compare := func(i int) int {
+			boundDate, _ := parseDate(bounds[i])
+			return date.Compare(boundDate)
+		}
+		// 日付の演算子を数値の演算子と同じ名前に揃える
+		operator := map[string]string{
+			"DATE_EQ": "EQ", "DATE_BEFORE": "LESS", "DATE_AFTER": "GREATER",
+			"DATE_ON_OR_BEFORE": "LESS_THAN_EQ", "DATE_ON_OR_AFTER": "GREATER_THAN_EQ",
+			"DATE_BETWEEN": "BETWEEN", "DATE_NOT_BETWEEN": "NOT_BETWEEN",
+		}[conditionType]
+		if operator == "" {
+			return "", false, nil
+		}
+		return evaluateComparison(operator, compare, len(bounds), text, bounds)
+	}
+	return "", false, nil
+}
+
+// 比較演算子による評価（compare(i) は値と i 番目の条件値の比較結果）
+func evaluateComparison(operator string, compare func(int) int, count int, text string, bounds []string) (string, bool, error) {
+	need := 1
+	if operator == "BETWEEN" || operator == "NOT_BETWEEN" {
+		need = 2
+	}
+	if count != need {
+		return "", false, nil
+	}
+	var ok bool
+	var expected string
+	switch operator {
+	case "EQ":
+		ok, expected = compare(0) == 0, "equal to "+bounds[0]
+	case "NOT_EQ":
+		ok, expected = compare(0) != 0, "not equal to "+bounds[0]
+	case "GREATER":
+		ok, expected = compare(0) > 0, "greater than "+bounds[0]
+	case "GREATER_THAN_EQ":
+		ok, expected = compare(0) >= 0, bounds[0]+" or greater"
+	case "LESS":
+		ok, expected = compare(0) < 0, "less than "+bounds[0]
+	case "LESS_THAN_EQ":
+		ok, expected = compare(0) <= 0, bounds[0]+" or less"
+	case "BETWEEN":
+		ok, expected = compare(0) >= 0 && compare(1) <= 0, fmt.Sprintf("between %s and %s", bounds[0], bounds[1])
+	case "NOT_BETWEEN":
+		ok, expected = compare(0) < 0 || compare(1) > 0, fmt.Sprintf("not between %s and %s", bounds[0], bounds[1])
+	default:
+		return "", false, nil
+	}
+	if ok {
+		return "", true, nil
+	}
+	return fmt.Sprintf("%s must be %s", text, expected), true, nil
+}
+
+// 書き込む前に、範囲の入力規則に違反する値がないか確認する
+// 戻り値はローカルで評価できなかったセルの数
+func (gs *GoogleSheets) checkDataValidation(ctx context.Context, spreadsheetId, sheetName, rangeStr string, data [][]interface{}) ([]validationViolation, int, error) {
+	target, err := parseA1Range(rangeStr)
+	if err != nil {
+		return nil, 0, err
+	}
+	startCol, startRow := target.start()
+
+	cells, err := gs.getCellValidations(ctx, spreadsheetId, sheetName, rangeStr)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(cells) == 0 {
+		return nil, 0, nil
+	}
+	rules := make(map[[2]int64]*sheets.DataValidationRule, len(cells))
+	for _, cell := range cells {
+		rules[[2]int64{cell.row, cell.col}] = cell.rule
+	}
+
+	// ONE_OF_RANGE の参照範囲の値（範囲ごとに1度だけ取得する）
+	cache := make(map[string][]string)
+	rangeValues := func(source string) ([]string, error) {
+		if values, ok := cache[source]; ok {
+			return values, nil
+		}
+		if !strings.Contains(source, "!") {
+			source = sheetRange(sheetName, source)
+		}
+		service, err := gs.auth.GetSheetsService(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sheets service: %w", err)
+		}
+		resp, err := service.Spreadsheets.Values.Get(spreadsheetId, source).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get values of validation range '%s': %w", source, err)
+		}
+		var values []string
+		for _, row := range resp.Values {
+			for _, value := range row {
+				if text := formatQueryValue(value); text != "" {
+					values = append(values, text)
+				}
+			}
+		}
+		cache[source] = values
+		return values, nil
+	}
+
+	var violations []validationViolation
+	unchecked := 0
+	for i, row := range data {
+		for j, value := range row {
+			r, c := startRow+int64(i), startCol+int64(j)
+			rule, ok := rules[[2]int64{r, c}]
+			if !ok {
+				continue
+			}
+			reason, checked, err := evaluateValidationRule(rule, value, rangeValues)
+			if err != nil {
+				return nil, 0, err
+			}
+			if !checked {
+				unchecked++
+				continue
+			}
+			if reason != "" {
+				violations = append(violations, validationViolation{
+					cell:   a1Cell(c, r).String(),
+					value:  value,
+					rule:   describeValidationRule(rule),
+					reason: reason,
+				})
+			}
+		}
+	}
+	return violations, unchecked, nil
+}
+
+// 入力規則の違反をエラーとして報告する
+func validationError(violations []validationViolation) error {
+	var report strings.Builder
+	report.WriteString(fmt.Sprintf("write was refused: %d cells violate data validation rules. No cells were changed.\n\n", len(violations)))
+	report.WriteString("| Cell | Value | Problem | Rule |\n")
+	report.WriteString("|---|---|---|---|\n")
+	for _, violation := range violations {
+		report.WriteString(fmt.Sprintf("| %s | %v | %s | %s |\n", violation.cell, violation.value, violation.reason, violation.rule))
+	}
+	report.WriteString("\nFix the values, or use google_sheets_get_data_validation to see the rules.")
+	return fmt.Errorf("%s", report.String())
+}
+
+// 入力規則を確認できなかったセルについての注意書き
+func uncheckedValidationNote(unchecked int) string {
+	if unchecked == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nNote: %d cells have validation rules that cannot be checked locally (custom formulas, formula values or relative dates) and were written without checking.", unchecked)
+}
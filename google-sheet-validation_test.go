@@ -0,0 +1,138 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestBuildValidationRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		request SetDataValidationRequest
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "list dropdown",
+			request: SetDataValidationRequest{RuleType: "list", Values: []string{"Open", "Done"}},
+			want:    "ONE_OF_LIST [Open, Done] (strict, dropdown)",
+		},
+		{
+			name:    "range dropdown in warn mode",
+			request: SetDataValidationRequest{RuleType: "range", SourceRange: "Options!A2:A20", Mode: "warn"},
+			want:    "ONE_OF_RANGE [=Options!A2:A20] (warn, dropdown)",
+		},
+		{
+			name:    "number between by default",
+			request: SetDataValidationRequest{RuleType: "number", Values: []string{"1", "10"}},
+			want:    "NUMBER_BETWEEN [1, 10] (strict)",
+		},
+		{
+			name:    "date on or after with message",
+			request: SetDataValidationRequest{RuleType: "date", Operator: "greater_or_equal", Values: []string{"2024-01-01"}, InputMessage: "From 2024"},
+			want:    "DATE_ON_OR_AFTER [2024-01-01] (strict, message: \"From 2024\")",
+		},
+		{
+			name:    "checkbox",
+			request: SetDataValidationRequest{RuleType: "checkbox"},
+			want:    "BOOLEAN (strict)",
+		},
+		{
+			name:    "custom formula",
+			request: SetDataValidationRequest{RuleType: "custom_formula", Values: []string{"=LEN(A2)<=10"}},
+			want:    "CUSTOM_FORMULA [=LEN(A2)<=10] (strict)",
+		},
+		{name: "clear", request: SetDataValidationRequest{RuleType: "clear"}, want: "none"},
+		{name: "empty list", request: SetDataValidationRequest{RuleType: "list"}, wantErr: true},
+		{name: "between needs two values", request: SetDataValidationRequest{RuleType: "number", Values: []string{"1"}}, wantErr: true},
+		{name: "date not equal", request: SetDataValidationRequest{RuleType: "date", Operator: "not_equal", Values: []string{"2024-01-01"}}, wantErr: true},
+		{name: "invalid mode", request: SetDataValidationRequest{RuleType: "checkbox", Mode: "loose"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := buildValidationRule(tt.request)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", describeValidationRule(rule))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := describeValidationRule(rule); got != tt.want {
+				t.Errorf("rule = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressCellRanges(t *testing.T) {
+	cells := [][2]int64{
+		{2, 3}, {3, 3}, {4, 3}, // C2:C4
+		{2, 4}, {3, 4}, {4, 4}, // D2:D4
+		{10, 1}, // A10
+		{6, 3},  // C6
+		{7, 3},  // C7
+	}
+	got := compressCellRanges(cells)
+	want := []string{"A10", "C2:D4", "C6:C7"}
+	if !slices.Equal(got, want) {
+		t.Errorf("compressCellRanges = %v, want %v", got, want)
+	}
+}
+
+func TestEvaluateValidationRule(t *testing.T) {
+	rule := func(conditionType string, strict bool, values ...string) *sheets.DataValidationRule {
+		condition := &sheets.BooleanCondition{Type: conditionType}
+		for _, value := range values {
+			condition.Values = append(condition.Values, &sheets.ConditionValue{UserEnteredValue: value})
+		}
+		return &sheets.DataValidationRule{Condition: condition, Strict: strict}
+	}
+	rangeValues := func(source string) ([]string, error) {
+		if source != "Options!A2:A4" {
+			t.Fatalf("unexpected source range: %s", source)
+		}
+		return []string{"Red", "Green", "Blue"}, nil
+	}
+
+	tests := []struct {
+		name        string
+		rule        *sheets.DataValidationRule
+		value       interface{}
+		wantOK      bool
+		wantChecked bool
+	}{
+		{"list member", rule("ONE_OF_LIST", true, "Open", "Done"), "done", true, true},
+		{"list non member", rule("ONE_OF_LIST", false, "Open", "Done"), "Closed", false, true},
+		{"empty value is allowed", rule("ONE_OF_LIST", true, "Open"), "", true, true},
+		{"range member", rule("ONE_OF_RANGE", true, "=Options!A2:A4"), "Green", true, true},
+		{"range non member", rule("ONE_OF_RANGE", true, "=Options!A2:A4"), "Pink", false, true},
+		{"number between", rule("NUMBER_BETWEEN", true, "1", "10"), float64(10), true, true},
+		{"number out of range", rule("NUMBER_BETWEEN", true, "1", "10"), "11", false, true},
+		{"number not a number", rule("NUMBER_GREATER", true, "0"), "abc", false, true},
+		{"number bound is a formula", rule("NUMBER_LESS", true, "=B1"), float64(3), true, false},
+		{"date after", rule("DATE_AFTER", true, "2024-01-01"), "2024-02-01", true, true},
+		{"date before bound", rule("DATE_ON_OR_AFTER", true, "2024-01-01"), "2023/12/31", false, true},
+		{"date unparseable", rule("DATE_BEFORE", true, "2024-01-01"), "next week", false, true},
+		{"checkbox boolean", rule("BOOLEAN", true), true, true, true},
+		{"checkbox text", rule("BOOLEAN", true), "yes", false, true},
+		{"checkbox custom values", rule("BOOLEAN", true, "Yes", "No"), "no", true, true},
+		{"custom formula is not checked", rule("CUSTOM_FORMULA", true, "=A1>0"), "x", true, false},
+		{"formula value is not checked", rule("ONE_OF_LIST", true, "Open"), "=B2", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, checked, err := evaluateValidationRule(tt.rule, tt.value, rangeValues)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if checked != tt.wantChecked || (reason == "") != tt.wantOK {
+				t.Errorf("evaluateValidationRule(%v) = %q, checked %v; want ok %v, checked %v", tt.value, reason, checked, tt.wantOK, tt.wantChecked)
+			}
+		})
+	}
+}
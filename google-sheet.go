@@ -2,8 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"path"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -40,7 +41,7 @@ var CopySheetInputSchema = &jsonschema.Schema{
 		},
 		"source_sheet_name": {
 			Type:        "string",
-			Description: "Name of the sheet/tab to copy from",
+			Description: "Name or numeric ID of the sheet/tab to copy from",
 		},
 		"destination_spreadsheet_name": {
 			Type:        "string",
@@ -69,7 +70,7 @@ var RenameSheetInputSchema = &jsonschema.Schema{
 		},
 		"sheet_name": {
 			Type:        "string",
-			Description: "Current name of the sheet/tab to rename",
+			Description: "Current name or numeric ID of the sheet/tab to rename",
 		},
 		"new_name": {
 			Type:        "string",
@@ -88,16 +89,21 @@ var ListSheetsInputSchema = &jsonschema.Schema{
 	Properties: map[string]*jsonschema.Schema{
 		"spreadsheet_name": {
 			Type:        "string",
-			Description: "Name of the Google Spreadsheet file (as shown in google_drive_list_files). Example: 'My Spreadsheet' or 'Archive/data.xlsx'",
+			Description: "Name of the Google Spreadsheet file (as shown in google_drive_list_files). Write a '/' that is part of a name as '\\/'. Example: 'My Spreadsheet', 'Archive/data.xlsx' or '2024\\/25 Budget'",
 		},
 	},
 	Required: []string{"spreadsheet_name"},
 }
 
+// 読み取り時のデフォルトの最大セル数
+const defaultReadMaxCells = 5000
+
 type GetSheetDataRequest struct {
 	SpreadsheetName string `json:"spreadsheet_name"`
 	SheetName       string `json:"sheet_name"`
 	Range           string `json:"range"`
+	MaxRows         int64  `json:"max_rows"`
+	MaxCells        int64  `json:"max_cells"`
 }
 
 var GetSheetDataInputSchema = &jsonschema.Schema{
@@ -109,21 +115,31 @@ var GetSheetDataInputSchema = &jsonschema.Schema{
 		},
 		"sheet_name": {
 			Type:        "string",
-			Description: "Name of the sheet/tab within the spreadsheet. Example: 'Sheet1', 'Data', or '/wiki/api/v2/blogposts'",
+			Description: "Name or numeric ID of the sheet/tab within the spreadsheet. Example: 'Sheet1', 'Data', or '/wiki/api/v2/blogposts'",
 		},
 		"range": {
 			Type:        "string",
-			Description: "Optional cell range to read (A1 notation). Examples: 'A1:C10', 'B2:D5'. Leave empty to read all data.",
+			Description: "Optional cell range to read (A1 notation). Examples: 'A1:C10', 'B2:D5', 'A101:F', or a named range such as 'Tax_Rates'. Leave empty to read all data. To continue a truncated read, pass the 'Next range' from the previous response.",
+		},
+		"max_rows": {
+			Type:        "integer",
+			Description: "Maximum number of rows to return. If the range has more rows, the response includes the next range to read.",
+		},
+		"max_cells": {
+			Type:        "integer",
+			Description: "Maximum number of cells (rows x width of the widest returned row) to return. Default: 5000",
+			Default:     json.RawMessage(`5000`),
 		},
 	},
 	Required: []string{"spreadsheet_name", "sheet_name"},
 }
 
 type AddRowsRequest struct {
-	SpreadsheetName string `json:"spreadsheet_name"`
-	SheetName       string `json:"sheet_name"`
-	Count           int64  `json:"count"`
-	StartRow        int64  `json:"start_row"`
+	SpreadsheetName   string `json:"spreadsheet_name"`
+	SheetName         string `json:"sheet_name"`
+	Count             int64  `json:"count"`
+	StartRow          int64  `json:"start_row"`
+	InheritFromBefore bool   `json:"inherit_from_before"`
 }
 
 var AddRowsInputSchema = &jsonschema.Schema{
@@ -135,7 +151,7 @@ var AddRowsInputSchema = &jsonschema.Schema{
 		},
 		"sheet_name": {
 			Type:        "string",
-			Description: "Name of the sheet/tab to modify",
+			Description: "Name or numeric ID of the sheet/tab to modify",
 		},
 		"count": {
 			Type:        "integer",
@@ -145,15 +161,20 @@ var AddRowsInputSchema = &jsonschema.Schema{
 			Type:        "integer",
 			Description: "Row position to start inserting (1-based). If not specified, rows will be added at the end.",
 		},
+		"inherit_from_before": {
+			Type:        "boolean",
+			Description: "Copy formatting and data validation from the row above the new rows instead of the row after them",
+		},
 	},
 	Required: []string{"spreadsheet_name", "sheet_name", "count"},
 }
 
 type AddColumnsRequest struct {
-	SpreadsheetName string `json:"spreadsheet_name"`
-	SheetName       string `json:"sheet_name"`
-	Count           int64  `json:"count"`
-	StartColumn     int64  `json:"start_column"`
+	SpreadsheetName   string `json:"spreadsheet_name"`
+	SheetName         string `json:"sheet_name"`
+	Count             int64  `json:"count"`
+	StartColumn       int64  `json:"start_column"`
+	InheritFromBefore bool   `json:"inherit_from_before"`
 }
 
 var AddColumnsInputSchema = &jsonschema.Schema{
@@ -165,7 +186,7 @@ var AddColumnsInputSchema = &jsonschema.Schema{
 		},
 		"sheet_name": {
 			Type:        "string",
-			Description: "Name of the sheet/tab to modify",
+			Description: "Name or numeric ID of the sheet/tab to modify",
 		},
 		"count": {
 			Type:        "integer",
@@ -175,16 +196,21 @@ var AddColumnsInputSchema = &jsonschema.Schema{
 			Type:        "integer",
 			Description: "Column position to start inserting (1-based). If not specified, columns will be added at the end.",
 		},
+		"inherit_from_before": {
+			Type:        "boolean",
+			Description: "Copy formatting and data validation from the column to the left of the new columns instead of the column to the right",
+		},
 	},
 	Required: []string{"spreadsheet_name", "sheet_name", "count"},
 }
 
 // 行削除リクエスト
 type DeleteRowsRequest struct {
-	SpreadsheetName string `json:"spreadsheet_name"`
-	SheetName       string `json:"sheet_name"`
-	Count           int64  `json:"count"`
-	StartRow        int64  `json:"start_row"`
+	SpreadsheetName  string `json:"spreadsheet_name"`
+	SheetName        string `json:"sheet_name"`
+	Count            int64  `json:"count"`
+	StartRow         int64  `json:"start_row"`
+	IgnoreProtection bool   `json:"ignore_protection"`
 }
 
 var DeleteRowsInputSchema = &jsonschema.Schema{
@@ -196,7 +222,7 @@ var DeleteRowsInputSchema = &jsonschema.Schema{
 		},
 		"sheet_name": {
 			Type:        "string",
-			Description: "Name of the sheet/tab to modify",
+			Description: "Name or numeric ID of the sheet/tab to modify",
 		},
 		"count": {
 			Type:        "integer",
@@ -206,16 +232,21 @@ var DeleteRowsInputSchema = &jsonschema.Schema{
 			Type:        "integer",
 			Description: "First row to delete (1-based). Example: 5 to delete row 5 onwards",
 		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Delete even if the rows overlap a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
 	},
 	Required: []string{"spreadsheet_name", "sheet_name", "count", "start_row"},
 }
 
 // 列削除リクエスト
 type DeleteColumnsRequest struct {
-	SpreadsheetName string `json:"spreadsheet_name"`
-	SheetName       string `json:"sheet_name"`
-	Count           int64  `json:"count"`
-	StartColumn     int64  `json:"start_column"`
+	SpreadsheetName  string `json:"spreadsheet_name"`
+	SheetName        string `json:"sheet_name"`
+	Count            int64  `json:"count"`
+	StartColumn      int64  `json:"start_column"`
+	IgnoreProtection bool   `json:"ignore_protection"`
 }
 
 var DeleteColumnsInputSchema = &jsonschema.Schema{
@@ -227,7 +258,7 @@ var DeleteColumnsInputSchema = &jsonschema.Schema{
 		},
 		"sheet_name": {
 			Type:        "string",
-			Description: "Name of the sheet/tab to modify",
+			Description: "Name or numeric ID of the sheet/tab to modify",
 		},
 		"count": {
 			Type:        "integer",
@@ -237,16 +268,22 @@ var DeleteColumnsInputSchema = &jsonschema.Schema{
 			Type:        "integer",
 			Description: "First column to delete (1-based). Example: 3 to delete column C onwards",
 		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Delete even if the columns overlap a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
 	},
 	Required: []string{"spreadsheet_name", "sheet_name", "count", "start_column"},
 }
 
 // セル編集リクエスト
 type UpdateCellsRequest struct {
-	SpreadsheetName string          `json:"spreadsheet_name"`
-	SheetName       string          `json:"sheet_name"`
-	Range           string          `json:"range"`
-	Data            [][]interface{} `json:"data"`
+	SpreadsheetName  string          `json:"spreadsheet_name"`
+	SheetName        string          `json:"sheet_name"`
+	Range            string          `json:"range"`
+	Data             [][]interface{} `json:"data"`
+	Validate         bool            `json:"validate"`
+	IgnoreProtection bool            `json:"ignore_protection"`
 }
 
 var UpdateCellsInputSchema = &jsonschema.Schema{
@@ -258,28 +295,39 @@ var UpdateCellsInputSchema = &jsonschema.Schema{
 		},
 		"sheet_name": {
 			Type:        "string",
-			Description: "Name of the sheet/tab to modify",
+			Description: "Name or numeric ID of the sheet/tab to modify",
 		},
 		"range": {
 			Type:        "string",
-			Description: "Cell range in A1 notation. Examples: 'A1:C3', 'B2:D5'",
+			Description: "Cell range in A1 notation, or a named range. Examples: 'A1:C3', 'B2:D5', 'Tax_Rates'",
 		},
 		"data": {
-			Type:        "array",
-			Description: "2D array of values to write. Example: [[\"Header1\", \"Header2\"], [\"Value1\", \"Value2\"]]",
+			Type: "array",
+			Description: "2D array of values to write. Example: [[\"Header1\", \"Header2\"], [\"Value1\", \"Value2\"]]. " +
+				"A cell can also be an object: {\"text\": \"Docs\", \"url\": \"https://example.com\"} writes a link, and {\"runs\": [{\"text\": \"Done\", \"bold\": true, \"color\": \"#188038\"}, {\"text\": \" on 5/1\"}]} writes rich text (each run accepts bold, italic, strikethrough, underline, color and url). HYPERLINK formulas such as =HYPERLINK(\"https://example.com\", \"Docs\") also work.",
 			Items: &jsonschema.Schema{
 				Type: "array",
 			},
 		},
+		"validate": {
+			Type:        "boolean",
+			Description: "Check the values against the data validation rules of the target cells first (dropdown lists, number and date bounds, checkboxes), and refuse the whole write with a per-cell report if any value violates them",
+		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Write even if the target overlaps a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
 	},
 	Required: []string{"spreadsheet_name", "sheet_name", "range", "data"},
 }
 
 // 複数範囲のセル編集リクエスト
 type BatchUpdateCellsRequest struct {
-	SpreadsheetName string                     `json:"spreadsheet_name"`
-	SheetName       string                     `json:"sheet_name"`
-	Ranges          map[string][][]interface{} `json:"ranges"`
+	SpreadsheetName  string                     `json:"spreadsheet_name"`
+	SheetName        string                     `json:"sheet_name"`
+	Ranges           map[string][][]interface{} `json:"ranges"`
+	Validate         bool                       `json:"validate"`
+	IgnoreProtection bool                       `json:"ignore_protection"`
 }
 
 var BatchUpdateCellsInputSchema = &jsonschema.Schema{
@@ -291,11 +339,12 @@ var BatchUpdateCellsInputSchema = &jsonschema.Schema{
 		},
 		"sheet_name": {
 			Type:        "string",
-			Description: "Name of the sheet/tab to modify",
+			Description: "Name or numeric ID of the sheet/tab to modify",
 		},
 		"ranges": {
-			Type:        "object",
-			Description: "Map of cell ranges to 2D arrays of values. Example: {\"A1:B2\": [[\"Name\", \"Age\"], [\"John\", 25]], \"D1:E1\": [[\"Status\", \"Active\"]]}",
+			Type: "object",
+			Description: "Map of cell ranges (A1 notation or named ranges) to 2D arrays of values. Example: {\"A1:B2\": [[\"Name\", \"Age\"], [\"John\", 25]], \"D1:E1\": [[\"Status\", \"Active\"]]}. " +
+				"A cell can also be an object: {\"text\": \"Docs\", \"url\": \"https://example.com\"} writes a link, and {\"runs\": [{\"text\": \"Done\", \"bold\": true, \"color\": \"#188038\"}, {\"text\": \" on 5/1\"}]} writes rich text (each run accepts bold, italic, strikethrough, underline, color and url). HYPERLINK formulas such as =HYPERLINK(\"https://example.com\", \"Docs\") also work.",
 			AdditionalProperties: &jsonschema.Schema{
 				Type: "array",
 				Items: &jsonschema.Schema{
@@ -303,6 +352,14 @@ var BatchUpdateCellsInputSchema = &jsonschema.Schema{
 				},
 			},
 		},
+		"validate": {
+			Type:        "boolean",
+			Description: "Check the values against the data validation rules of the target cells first (dropdown lists, number and date bounds, checkboxes), and refuse the whole write with a per-cell report if any value violates them",
+		},
+		"ignore_protection": {
+			Type:        "boolean",
+			Description: "Write even if the target overlaps a protected range. Only needed when MCPGS_WARN_PROTECTED_RANGES is enabled and a previous attempt was stopped; confirm with the user first",
+		},
 	},
 	Required: []string{"spreadsheet_name", "sheet_name", "ranges"},
 }
@@ -314,35 +371,33 @@ func (gs *GoogleSheets) getSpreadsheetId(spreadsheetName string) (string, error)
 
 // コンテキスト付きでスプレッドシート名からスプレッドシートIDを取得する
 func (gs *GoogleSheets) getSpreadsheetIdWithContext(ctx context.Context, spreadsheetName string) (string, error) {
-	// パスの正規化と検証
-	filePath := path.Clean(spreadsheetName)
-	if strings.HasPrefix(filePath, "..") || strings.HasPrefix(filePath, "/") {
-		return "", fmt.Errorf("invalid path: directory traversal is not allowed")
+	// パスの分割と検証
+	parts, err := splitDrivePath(spreadsheetName)
+	if err != nil {
+		return "", err
 	}
 
 	// ルートフォルダから開始
 	parentID := gs.cfg.FolderID
 
 	// パスが空の場合はエラー
-	if filePath == "." || filePath == "" {
+	if len(parts) == 0 {
 		return "", fmt.Errorf("spreadsheet name cannot be empty")
 	}
 
-	// パスを分割
-	parts := strings.Split(filePath, "/")
-
 	// 各パスの部分を順番に検索
 	for i, part := range parts {
 		isLast := i == len(parts)-1
 
 		// 最後の部分（ファイル名）の場合はスプレッドシートタイプを指定
-		var query string
+		query := newDriveQuery().inParents(parentID).nameEquals(part)
 		if isLast {
-			query = fmt.Sprintf("'%s' in parents and name = '%s' and mimeType = 'application/vnd.google-apps.spreadsheet' and trashed = false", parentID, part)
+			query.mimeType(spreadsheetMimeType)
 		} else {
 			// フォルダの場合
-			query = fmt.Sprintf("'%s' in parents and name = '%s' and mimeType = 'application/vnd.google-apps.folder' and trashed = false", parentID, part)
+			query.mimeType(folderMimeType)
 		}
+		query.notTrashed()
 
 		service, err := gs.auth.GetDriveService(ctx)
 		if err != nil {
@@ -350,7 +405,7 @@ func (gs *GoogleSheets) getSpreadsheetIdWithContext(ctx context.Context, spreads
 		}
 
 		fileList, err := service.Files.List().
-			Q(query).
+			Q(query.String()).
 			SupportsAllDrives(true).
 			IncludeItemsFromAllDrives(true).
 			Fields("files(id, name, mimeType)").
@@ -363,7 +418,7 @@ func (gs *GoogleSheets) getSpreadsheetIdWithContext(ctx context.Context, spreads
 			if isLast {
 				return "", fmt.Errorf("spreadsheet not found: '%s'. Please check the spreadsheet name. Use google_drive_list_files to find available spreadsheets", spreadsheetName)
 			} else {
-				return "", fmt.Errorf("folder not found: '%s'. Please check the folder path. Use google_drive_list_files to browse available folders", strings.Join(parts[:i+1], "/"))
+				return "", fmt.Errorf("folder not found: '%s'. Please check the folder path. Use google_drive_list_files to browse available folders", formatDrivePath(parts[:i+1]))
 			}
 		}
 
@@ -381,25 +436,71 @@ func (gs *GoogleSheets) getSheetId(spreadsheetId string, sheetName string) (int6
 
 // コンテキスト付きでシートIDを取得する
 func (gs *GoogleSheets) getSheetIdWithContext(ctx context.Context, spreadsheetId string, sheetName string) (int64, error) {
+	properties, err := gs.getSheetPropertiesWithContext(ctx, spreadsheetId, sheetName)
+	if err != nil {
+		return 0, err
+	}
+	return properties.SheetId, nil
+}
+
+// シートのプロパティ（シートID・行数・列数など）を取得する
+func (gs *GoogleSheets) getSheetPropertiesWithContext(ctx context.Context, spreadsheetId string, sheetName string) (*sheets.SheetProperties, error) {
 	// スプレッドシートの情報を取得
 	service, err := gs.auth.GetSheetsService(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get sheets service: %w", err)
+		return nil, fmt.Errorf("failed to get sheets service: %w", err)
 	}
 
-	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).Do()
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).Fields("sheets.properties").Do()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get spreadsheet: %w", err)
+		return nil, fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+
+	// シート名またはシートIDからシートを検索
+	if sheet := findSheet(spreadsheet.Sheets, sheetName); sheet != nil {
+		return sheet.Properties, nil
 	}
 
-	// シート名からシートIDを検索
-	for _, sheet := range spreadsheet.Sheets {
+	return nil, fmt.Errorf("sheet not found: '%s'. Please check the sheet name. Use google_sheets_list_sheets to see available sheets in this spreadsheet", sheetName)
+}
+
+// シート名またはシートID（数字のみの指定）からシートを探す
+// 数字のみの名前のシートもあるため、シート名の一致を優先する
+func findSheet(sheetList []*sheets.Sheet, sheetName string) *sheets.Sheet {
+	for _, sheet := range sheetList {
 		if sheet.Properties.Title == sheetName {
-			return sheet.Properties.SheetId, nil
+			return sheet
+		}
+	}
+	if sheetId, err := strconv.ParseInt(sheetName, 10, 64); err == nil {
+		for _, sheet := range sheetList {
+			if sheet.Properties.SheetId == sheetId {
+				return sheet
+			}
 		}
 	}
+	return nil
+}
+
+// シートIDで指定されたシートをシート名に変換する
+// 数字のみの指定でなければそのまま返す。該当するシートがない場合もそのまま返す
+func (gs *GoogleSheets) resolveSheetName(ctx context.Context, spreadsheetId string, sheetName string) (string, error) {
+	if _, err := strconv.ParseInt(sheetName, 10, 64); err != nil {
+		return sheetName, nil
+	}
 
-	return 0, fmt.Errorf("sheet not found: '%s'. Please check the sheet name. Use google_sheets_list_sheets to see available sheets in this spreadsheet", sheetName)
+	service, err := gs.auth.GetSheetsService(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get sheets service: %w", err)
+	}
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetId).Fields("sheets.properties(sheetId,title)").Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+	if sheet := findSheet(spreadsheet.Sheets, sheetName); sheet != nil {
+		return sheet.Properties.Title, nil
+	}
+	return sheetName, nil
 }
 
 func (gs *GoogleSheets) CopySheetHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[CopySheetRequest]) (*mcp.CallToolResultFor[any], error) {
@@ -489,6 +590,11 @@ func (gs *GoogleSheets) RenameSheetHandler(ctx context.Context, cc *mcp.ServerSe
 		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
 	}
 
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
 	sheetName := request.SheetName
 
 	// 新しい名前が空でないことを確認
@@ -587,6 +693,11 @@ func (gs *GoogleSheets) AddRowsHandler(ctx context.Context, cc *mcp.ServerSessio
 		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
 	}
 
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
 	sheetName := request.SheetName
 
 	// 追加する行数が正の値であることを確認
@@ -594,33 +705,60 @@ func (gs *GoogleSheets) AddRowsHandler(ctx context.Context, cc *mcp.ServerSessio
 		return nil, fmt.Errorf("count must be a positive number")
 	}
 
-	if request.StartRow <= 0 {
-		return nil, fmt.Errorf("start_row must be a positive number")
+	if request.StartRow < 0 {
+		return nil, fmt.Errorf("start_row must not be negative")
+	}
+	if request.StartRow == 1 && request.InheritFromBefore {
+		return nil, fmt.Errorf("inherit_from_before cannot be used when inserting before the first row")
 	}
 
-	// シートIDを取得
-	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, sheetName)
+	// シートのプロパティを取得
+	properties, err := gs.getSheetPropertiesWithContext(ctx, spreadsheetId, sheetName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+		return nil, fmt.Errorf("failed to get sheet properties: %w", err)
 	}
 
 	// リクエストを作成
-	var batchRequest *sheets.BatchUpdateSpreadsheetRequest
-
-	batchRequest = &sheets.BatchUpdateSpreadsheetRequest{
-		Requests: []*sheets.Request{
-			{
-				InsertDimension: &sheets.InsertDimensionRequest{
-					Range: &sheets.DimensionRange{
-						SheetId:    sheetId,
-						Dimension:  "ROWS",
-						StartIndex: request.StartRow - 1,
-						EndIndex:   request.StartRow + request.Count - 1,
-					},
-					InheritFromBefore: false,
+	var dimensionRequest *sheets.Request
+	switch {
+	case request.StartRow > 0:
+		dimensionRequest = &sheets.Request{
+			InsertDimension: &sheets.InsertDimensionRequest{
+				Range: &sheets.DimensionRange{
+					SheetId:    properties.SheetId,
+					Dimension:  "ROWS",
+					StartIndex: request.StartRow - 1,
+					EndIndex:   request.StartRow + request.Count - 1,
 				},
+				InheritFromBefore: request.InheritFromBefore,
 			},
-		},
+		}
+	case request.InheritFromBefore && properties.GridProperties != nil:
+		// 末尾に追加して直前の行の書式を引き継ぐ場合は、末尾の位置に挿入する
+		end := properties.GridProperties.RowCount
+		dimensionRequest = &sheets.Request{
+			InsertDimension: &sheets.InsertDimensionRequest{
+				Range: &sheets.DimensionRange{
+					SheetId:    properties.SheetId,
+					Dimension:  "ROWS",
+					StartIndex: end,
+					EndIndex:   end + request.Count,
+				},
+				InheritFromBefore: true,
+			},
+		}
+	default:
+		// 末尾に追加
+		dimensionRequest = &sheets.Request{
+			AppendDimension: &sheets.AppendDimensionRequest{
+				SheetId:   properties.SheetId,
+				Dimension: "ROWS",
+				Length:    request.Count,
+			},
+		}
+	}
+	batchRequest := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{dimensionRequest},
 	}
 
 	// 行を追加
@@ -642,6 +780,10 @@ func (gs *GoogleSheets) AddRowsHandler(ctx context.Context, cc *mcp.ServerSessio
 	} else {
 		message = fmt.Sprintf("Successfully added %d rows at the end of sheet '%s' of spreadsheet '%s'",
 			request.Count, request.SheetName, request.SpreadsheetName)
+		if properties.GridProperties != nil {
+			first := properties.GridProperties.RowCount + 1
+			message += fmt.Sprintf(" (new rows %d-%d)", first, first+request.Count-1)
+		}
 	}
 
 	return &mcp.CallToolResultFor[any]{
@@ -661,6 +803,11 @@ func (gs *GoogleSheets) AddColumnsHandler(ctx context.Context, cc *mcp.ServerSes
 		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
 	}
 
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
 	sheetName := request.SheetName
 
 	// 追加する列数が正の値であることを確認
@@ -668,33 +815,60 @@ func (gs *GoogleSheets) AddColumnsHandler(ctx context.Context, cc *mcp.ServerSes
 		return nil, fmt.Errorf("count must be a positive number")
 	}
 
-	if request.StartColumn <= 0 {
-		return nil, fmt.Errorf("start_column must be a positive number")
+	if request.StartColumn < 0 {
+		return nil, fmt.Errorf("start_column must not be negative")
+	}
+	if request.StartColumn == 1 && request.InheritFromBefore {
+		return nil, fmt.Errorf("inherit_from_before cannot be used when inserting before the first column")
 	}
 
-	// シートIDを取得
-	sheetId, err := gs.getSheetIdWithContext(ctx, spreadsheetId, sheetName)
+	// シートのプロパティを取得
+	properties, err := gs.getSheetPropertiesWithContext(ctx, spreadsheetId, sheetName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
+		return nil, fmt.Errorf("failed to get sheet properties: %w", err)
 	}
 
 	// リクエストを作成
-	var batchRequest *sheets.BatchUpdateSpreadsheetRequest
-
-	batchRequest = &sheets.BatchUpdateSpreadsheetRequest{
-		Requests: []*sheets.Request{
-			{
-				InsertDimension: &sheets.InsertDimensionRequest{
-					Range: &sheets.DimensionRange{
-						SheetId:    sheetId,
-						Dimension:  "COLUMNS",
-						StartIndex: request.StartColumn - 1,
-						EndIndex:   request.StartColumn + request.Count - 1,
-					},
-					InheritFromBefore: false,
+	var dimensionRequest *sheets.Request
+	switch {
+	case request.StartColumn > 0:
+		dimensionRequest = &sheets.Request{
+			InsertDimension: &sheets.InsertDimensionRequest{
+				Range: &sheets.DimensionRange{
+					SheetId:    properties.SheetId,
+					Dimension:  "COLUMNS",
+					StartIndex: request.StartColumn - 1,
+					EndIndex:   request.StartColumn + request.Count - 1,
 				},
+				InheritFromBefore: request.InheritFromBefore,
 			},
-		},
+		}
+	case request.InheritFromBefore && properties.GridProperties != nil:
+		// 末尾に追加して直前の列の書式を引き継ぐ場合は、末尾の位置に挿入する
+		end := properties.GridProperties.ColumnCount
+		dimensionRequest = &sheets.Request{
+			InsertDimension: &sheets.InsertDimensionRequest{
+				Range: &sheets.DimensionRange{
+					SheetId:    properties.SheetId,
+					Dimension:  "COLUMNS",
+					StartIndex: end,
+					EndIndex:   end + request.Count,
+				},
+				InheritFromBefore: true,
+			},
+		}
+	default:
+		// 末尾に追加
+		dimensionRequest = &sheets.Request{
+			AppendDimension: &sheets.AppendDimensionRequest{
+				SheetId:   properties.SheetId,
+				Dimension: "COLUMNS",
+				Length:    request.Count,
+			},
+		}
+	}
+	batchRequest := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{dimensionRequest},
 	}
 
 	// 列を追加
@@ -716,6 +890,10 @@ func (gs *GoogleSheets) AddColumnsHandler(ctx context.Context, cc *mcp.ServerSes
 	} else {
 		message = fmt.Sprintf("Successfully added %d columns at the end of sheet '%s' of spreadsheet '%s'",
 			request.Count, request.SheetName, request.SpreadsheetName)
+		if properties.GridProperties != nil {
+			first := properties.GridProperties.ColumnCount + 1
+			message += fmt.Sprintf(" (new columns %s-%s)", columnIndexToLetter(first), columnIndexToLetter(first+request.Count-1))
+		}
 	}
 
 	return &mcp.CallToolResultFor[any]{
@@ -727,8 +905,9 @@ func (gs *GoogleSheets) AddColumnsHandler(ctx context.Context, cc *mcp.ServerSes
 	}, nil
 }
 
-// 2次元配列のデータを表形式の文字列に変換する関数
-func formatTableData(startColumn, startRow int64, values [][]interface{}) string {
+// 2次元配列のデータを表形式の文字列に変換する関数（origin は値の左上のセルを含む範囲）
+func formatTableData(origin a1Range, values [][]interface{}) string {
+	startColumn, startRow := origin.start()
 
 	maxWidth := 0
 	for _, row := range values {
@@ -783,6 +962,11 @@ func (gs *GoogleSheets) UpdateCellsHandler(ctx context.Context, cc *mcp.ServerSe
 		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
 	}
 
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
 	sheetName := request.SheetName
 
 	// 範囲が指定されていることを確認
@@ -795,8 +979,47 @@ func (gs *GoogleSheets) UpdateCellsHandler(ctx context.Context, cc *mcp.ServerSe
 		return nil, fmt.Errorf("data cannot be empty")
 	}
 
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, sheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+	if rangeStr == "" {
+		return nil, fmt.Errorf("named range '%s' covers the whole sheet. Please specify a cell range", request.Range)
+	}
+	target, err := parseA1Range(rangeStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse range: %w", err)
+	}
+
+	// リンクやリッチテキストのセルは値を書き込んだ後に書式付きで上書きする
+	data, richCells, err := splitRichCells(target, request.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	// 書き込む値が入力規則を満たすか確認
+	var validationNote string
+	if request.Validate {
+		violations, unchecked, err := gs.checkDataValidation(ctx, spreadsheetId, sheetName, rangeStr, data)
+		if err != nil {
+			return nil, err
+		}
+		if len(violations) > 0 {
+			return nil, validationError(violations)
+		}
+		validationNote = uncheckedValidationNote(unchecked)
+	}
+
+	// 保護された範囲への書き込みを確認
+	if !request.IgnoreProtection {
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, sheetName, []string{rangeStr}); err != nil {
+			return nil, err
+		}
+	}
+
 	// 範囲を完全な形式に変換（シート名を含む）
-	fullRange := fmt.Sprintf("%s!%s", sheetName, request.Range)
+	fullRange := sheetRange(sheetName, rangeStr)
 
 	// 変更前のデータを取得
 	service, err := gs.auth.GetSheetsService(ctx)
@@ -821,7 +1044,7 @@ func (gs *GoogleSheets) UpdateCellsHandler(ctx context.Context, cc *mcp.ServerSe
 	// 値を更新するリクエストを作成
 	valueRange := &sheets.ValueRange{
 		Range:  fullRange,
-		Values: request.Data,
+		Values: data,
 	}
 
 	// 値を更新
@@ -835,21 +1058,22 @@ func (gs *GoogleSheets) UpdateCellsHandler(ctx context.Context, cc *mcp.ServerSe
 	if err != nil {
 		return nil, fmt.Errorf("failed to update cells: %w", err)
 	}
+	if err := gs.writeRichCells(ctx, spreadsheetId, sheetName, richCells); err != nil {
+		return nil, partialWriteError(err, formatTableData(target, prevData.Values))
+	}
 
 	// 成功メッセージを作成
 	message := fmt.Sprintf("Successfully updated %d cells in range '%s' of sheet '%s' in spreadsheet '%s'",
 		updateResponse.UpdatedCells, request.Range, request.SheetName, request.SpreadsheetName)
+	message += richCellsNote(richCells)
 
 	// 変更前のデータの情報をメッセージに含める
 	message += fmt.Sprintf("\n\nPrevious data for range '%s' has been saved (%d rows x %d columns). To undo this change, you can use the previous data.",
 		request.Range, prevRowCount, prevColCount)
+	message += validationNote
 
 	// 変更前のデータを表示用に整形
-	col, row, err := startIndexFromRange(request.Range)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse range: %w", err)
-	}
-	prevDataStr := "\n\nPrevious data details:\n\n" + formatTableData(col, row, prevData.Values)
+	prevDataStr := "\n\nPrevious data details:\n\n" + formatTableData(target, prevData.Values)
 
 	// レスポンスを作成（変更前のデータを含める）
 	return &mcp.CallToolResultFor[any]{
@@ -861,45 +1085,6 @@ func (gs *GoogleSheets) UpdateCellsHandler(ctx context.Context, cc *mcp.ServerSe
 	}, nil
 }
 
-func startIndexFromRange(rangeStr string) (int64, int64, error) {
-	// 範囲が指定されていない場合はエラー
-	if rangeStr == "" {
-		return 0, 0, fmt.Errorf("range must be specified")
-	}
-
-	start := ""
-	startEnd := strings.Split(rangeStr, ":")
-	if len(startEnd) >= 1 {
-		start = startEnd[0]
-	} else {
-		return 0, 0, fmt.Errorf("invalid range: %s", rangeStr)
-	}
-
-	// ABC123 のような形式を分割
-	var colStr, rowStr string
-	i := 0
-	for _, c := range start {
-		if c >= 'A' && c <= 'Z' {
-			colStr += string(c)
-			i++
-		} else {
-			break
-		}
-	}
-	rowStr = start[i:]
-
-	var col, row int64
-	for _, c := range colStr {
-		col = col*26 + int64(c-'A'+1)
-	}
-	row, err := strconv.ParseInt(rowStr, 10, 64)
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid range: %s", rangeStr)
-	}
-
-	return col, row, nil
-}
-
 // 複数範囲のセル一括編集ハンドラー
 func (gs *GoogleSheets) BatchUpdateCellsHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[BatchUpdateCellsRequest]) (*mcp.CallToolResultFor[any], error) {
 	request := params.Arguments
@@ -909,6 +1094,11 @@ func (gs *GoogleSheets) BatchUpdateCellsHandler(ctx context.Context, cc *mcp.Ser
 		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
 	}
 
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
 	sheetName := request.SheetName
 
 	// 範囲が指定されていることを確認
@@ -916,19 +1106,87 @@ func (gs *GoogleSheets) BatchUpdateCellsHandler(ctx context.Context, cc *mcp.Ser
 		return nil, fmt.Errorf("ranges cannot be empty")
 	}
 
-	// 変更前のデータを保存するマップ
-	previousData := make(map[string][][]interface{})
+	// 結果の順序を一定にするため、範囲は指定された文字列の順に処理する
+	rangeKeys := make([]string, 0, len(request.Ranges))
+	for rangeStr := range request.Ranges {
+		rangeKeys = append(rangeKeys, rangeStr)
+	}
+	sort.Strings(rangeKeys)
+
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	resolvedRanges := make(map[string]string, len(request.Ranges))
+	for _, rangeStr := range rangeKeys {
+		resolved, err := gs.resolveRange(ctx, spreadsheetId, sheetName, rangeStr)
+		if err != nil {
+			return nil, err
+		}
+		if resolved == "" {
+			return nil, fmt.Errorf("named range '%s' covers the whole sheet. Please specify a cell range", rangeStr)
+		}
+		resolvedRanges[rangeStr] = resolved
+	}
+
+	// リンクやリッチテキストのセルは値を書き込んだ後に書式付きで上書きする
+	rangeData := make(map[string][][]interface{}, len(request.Ranges))
+	var richCells []richCellUpdate
+	for _, rangeStr := range rangeKeys {
+		values := request.Ranges[rangeStr]
+		target, err := parseA1Range(resolvedRanges[rangeStr])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse range: %w", err)
+		}
+		plain, rich, err := splitRichCells(target, values)
+		if err != nil {
+			return nil, err
+		}
+		rangeData[rangeStr] = plain
+		richCells = append(richCells, rich...)
+	}
+
+	// 書き込む値が入力規則を満たすか確認（1つでも違反があれば何も書き込まない）
+	var validationNote string
+	if request.Validate {
+		var violations []validationViolation
+		unchecked := 0
+		for _, rangeStr := range rangeKeys {
+			rangeViolations, rangeUnchecked, err := gs.checkDataValidation(ctx, spreadsheetId, sheetName, resolvedRanges[rangeStr], rangeData[rangeStr])
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, rangeViolations...)
+			unchecked += rangeUnchecked
+		}
+		if len(violations) > 0 {
+			return nil, validationError(violations)
+		}
+		validationNote = uncheckedValidationNote(unchecked)
+	}
+
+	// 保護された範囲への書き込みを確認
+	if !request.IgnoreProtection {
+		targets := make([]string, len(rangeKeys))
+		for i, rangeStr := range rangeKeys {
+			targets[i] = resolvedRanges[rangeStr]
+		}
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, sheetName, targets); err != nil {
+			return nil, err
+		}
+	}
+
+	// 変更前のデータ（表示用に整形したもの）
+	var prevDataStr strings.Builder
 
 	// バッチ更新用のデータを作成
 	var data []*sheets.ValueRange
-	for rangeStr, values := range request.Ranges {
+	for _, rangeStr := range rangeKeys {
+		values := rangeData[rangeStr]
 		// データが空でないことを確認
 		if len(values) == 0 {
 			return nil, fmt.Errorf("data for range '%s' cannot be empty", rangeStr)
 		}
 
 		// 範囲を完全な形式に変換（シート名を含む）
-		fullRange := fmt.Sprintf("%s!%s", sheetName, rangeStr)
+		fullRange := sheetRange(sheetName, resolvedRanges[rangeStr])
 
 		// 変更前のデータを取得
 		service, err := gs.auth.GetSheetsService(ctx)
@@ -940,7 +1198,11 @@ func (gs *GoogleSheets) BatchUpdateCellsHandler(ctx context.Context, cc *mcp.Ser
 		if err != nil {
 			return nil, fmt.Errorf("failed to get previous data for range '%s': %w", rangeStr, err)
 		}
-		previousData[rangeStr] = prevData.Values
+		target, err := parseA1Range(resolvedRanges[rangeStr])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse range: %w", err)
+		}
+		prevDataStr.WriteString(formatTableData(target, prevData.Values))
 
 		// ValueRangeを作成
 		valueRange := &sheets.ValueRange{
@@ -968,35 +1230,29 @@ func (gs *GoogleSheets) BatchUpdateCellsHandler(ctx context.Context, cc *mcp.Ser
 	if err != nil {
 		return nil, fmt.Errorf("failed to batch update cells: %w", err)
 	}
+	if err := gs.writeRichCells(ctx, spreadsheetId, sheetName, richCells); err != nil {
+		return nil, partialWriteError(err, prevDataStr.String())
+	}
 
 	// 成功メッセージを作成
 	message := fmt.Sprintf("Successfully updated %d cells across %d ranges in sheet '%s' in spreadsheet '%s'",
 		batchUpdateResponse.TotalUpdatedCells, batchUpdateResponse.TotalUpdatedSheets,
 		request.SheetName, request.SpreadsheetName)
+	message += richCellsNote(richCells)
 
 	// 変更前のデータの情報をメッセージに含める
 	message += "\n\nPrevious data for the following ranges has been saved:"
-	for rangeStr := range previousData {
+	for _, rangeStr := range rangeKeys {
 		message += fmt.Sprintf("\n- %s", rangeStr)
 	}
 	message += "\n\nTo undo these changes, you can use the previous data."
-
-	// 変更前のデータを表示用に整形
-	var prevDataStr strings.Builder
-	prevDataStr.WriteString("\n\nPrevious data details:\n\n")
-	for rangeStr, values := range previousData {
-		col, row, err := startIndexFromRange(rangeStr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse range: %w", err)
-		}
-		prevDataStr.WriteString(formatTableData(col, row, values))
-	}
+	message += validationNote
 
 	// レスポンスを作成（変更前のデータを含める）
 	return &mcp.CallToolResultFor[any]{
 		Content: []mcp.Content{
 			&mcp.TextContent{
-				Text: message + prevDataStr.String(),
+				Text: message + "\n\nPrevious data details:\n\n" + prevDataStr.String(),
 			},
 		},
 	}, nil
@@ -1010,13 +1266,75 @@ func (gs *GoogleSheets) GetSheetDataHandler(ctx context.Context, cc *mcp.ServerS
 		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
 	}
 
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
 	sheetName := request.SheetName
 
-	// 範囲が指定されていない場合はシート全体を取得
-	range_ := sheetName
-	if request.Range != "" {
-		range_ = fmt.Sprintf("%s!%s", sheetName, request.Range)
+	// シートの行数・列数を取得
+	properties, err := gs.getSheetPropertiesWithContext(ctx, spreadsheetId, sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet properties: %w", err)
+	}
+	var gridRows, gridCols int64
+	if properties.GridProperties != nil {
+		gridRows = properties.GridProperties.RowCount
+		gridCols = properties.GridProperties.ColumnCount
+	}
+
+	// 名前付き範囲が指定された場合は A1 表記に解決する
+	rangeStr, err := gs.resolveRange(ctx, spreadsheetId, sheetName, request.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	// 読み取る範囲を決定（範囲が指定されていない場合はシート全体）
+	var (
+		startCol int64 = 1
+		startRow int64 = 1
+		endCol         = gridCols
+		endRow         = gridRows
+	)
+	if rangeStr != "" {
+		target, err := parseA1Range(rangeStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse range: %w", err)
+		}
+		startCol, startRow, endCol, endRow = target.startCol, target.startRow, target.endCol, target.endRow
+		// 省略された端はシートの端とみなす
+		if startCol == 0 {
+			startCol = 1
+		}
+		if startRow == 0 {
+			startRow = 1
+		}
+		if endCol == 0 {
+			endCol = gridCols
+		}
+		if endRow == 0 {
+			endRow = gridRows
+		}
+	}
+	if endCol < startCol {
+		endCol = startCol
+	}
+	if endRow < startRow {
+		endRow = startRow
+	}
+
+	// 読み取る行数を決める（値のある行は1セル以上を使うため、セル数の上限より多くの行は読まない）
+	rowLimit := endRow - startRow + 1
+	if request.MaxRows > 0 && request.MaxRows < rowLimit {
+		rowLimit = request.MaxRows
+	}
+	maxCells := request.MaxCells
+	if maxCells <= 0 {
+		maxCells = defaultReadMaxCells
 	}
+	readRows := min(rowLimit, maxCells)
+	readRange := a1Range{startCol: startCol, startRow: startRow, endCol: endCol, endRow: startRow + readRows - 1}.String()
 
 	// シートデータを取得
 	service, err := gs.auth.GetSheetsService(ctx)
@@ -1024,11 +1342,32 @@ func (gs *GoogleSheets) GetSheetDataHandler(ctx context.Context, cc *mcp.ServerS
 		return nil, fmt.Errorf("failed to get sheets service: %w", err)
 	}
 
-	resp, err := service.Spreadsheets.Values.Get(spreadsheetId, range_).Do()
+	resp, err := service.Spreadsheets.Values.Get(spreadsheetId, sheetRange(sheetName, readRange)).Do()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sheet data: %w", err)
 	}
 
+	// セル数の上限は返ってきたデータのうち最も長い行の幅で数える
+	width := int64(1)
+	for _, row := range resp.Values {
+		width = max(width, int64(len(row)))
+	}
+	returnedRows := int64(len(resp.Values))
+	blockEnd := startRow + readRows - 1
+	if rowsByCells := max(1, maxCells/width); rowsByCells < returnedRows {
+		returnedRows = rowsByCells
+		resp.Values = resp.Values[:returnedRows]
+		blockEnd = startRow + returnedRows - 1
+		readRange = a1Range{startCol: startCol, startRow: startRow, endCol: endCol, endRow: blockEnd}.String()
+	}
+
+	// 返した行で読み取った範囲が埋まっている場合のみ、続きの範囲を示す
+	// 末尾の空行は返されないため、行数が足りない場合はデータの終わりまで読んでいる（何も返らない場合は続きにデータがあるかわからない）
+	nextRange := ""
+	if (returnedRows == blockEnd-startRow+1 || returnedRows == 0) && blockEnd < endRow {
+		nextRange = a1Range{startCol: startCol, startRow: blockEnd + 1, endCol: endCol, endRow: endRow}.String()
+	}
+
 	// 結果を整形
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("Data from sheet '%s' in spreadsheet '%s'",
@@ -1037,10 +1376,15 @@ func (gs *GoogleSheets) GetSheetDataHandler(ctx context.Context, cc *mcp.ServerS
 		result.WriteString(fmt.Sprintf(" (range: %s)", request.Range))
 	}
 	result.WriteString(":\n\n")
+	sizeInfo := fmt.Sprintf("Sheet size: %d rows x %d columns\n", gridRows, gridCols)
 
 	// データがない場合
 	if len(resp.Values) == 0 {
-		result.WriteString("No data found.")
+		result.WriteString(fmt.Sprintf("No data found in %s.\n", readRange))
+		result.WriteString("\n" + sizeInfo)
+		if nextRange != "" {
+			result.WriteString(fmt.Sprintf("Next range: %s (the remaining rows may also be empty)\n", nextRange))
+		}
 		return &mcp.CallToolResultFor[any]{
 			Content: []mcp.Content{
 				&mcp.TextContent{
@@ -1051,17 +1395,16 @@ func (gs *GoogleSheets) GetSheetDataHandler(ctx context.Context, cc *mcp.ServerS
 	}
 
 	// 各行のデータを表示
-	var (
-		startCol int64 = 1
-		startRow int64 = 1
-	)
-	if request.Range != "" {
-		startCol, startRow, err = startIndexFromRange(request.Range)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse range: %w", err)
-		}
+	result.WriteString(formatTableData(a1Cell(startCol, startRow), resp.Values))
+
+	// 値だけでは失われるリンクを表示（取得に失敗しても値は返す）
+	links, err := gs.getCellLinks(ctx, spreadsheetId, sheetName, readRange)
+	if err != nil {
+		result.WriteString(fmt.Sprintf("\nHyperlinks could not be read: %v\n", err))
+	} else if len(links) > 0 {
+		result.WriteString("\nHyperlinks:\n")
+		writeCellLinks(&result, links)
 	}
-	result.WriteString(formatTableData(startCol, startRow, resp.Values))
 
 	// 行と列の数を表示
 	rowCount := len(resp.Values)
@@ -1071,7 +1414,11 @@ func (gs *GoogleSheets) GetSheetDataHandler(ctx context.Context, cc *mcp.ServerS
 			colCount = count
 		}
 	}
-	result.WriteString(fmt.Sprintf("\nTotal: %d rows x %d columns\n", rowCount, colCount))
+	result.WriteString(fmt.Sprintf("\nTotal: %d rows x %d columns (read range: %s)\n", rowCount, colCount, readRange))
+	result.WriteString(sizeInfo)
+	if nextRange != "" {
+		result.WriteString(fmt.Sprintf("Truncated to %d rows. Next range: %s (pass it as range to continue reading)\n", returnedRows, nextRange))
+	}
 
 	// 成功レスポンスを返す
 	return &mcp.CallToolResultFor[any]{
@@ -1092,6 +1439,11 @@ func (gs *GoogleSheets) DeleteRowsHandler(ctx context.Context, cc *mcp.ServerSes
 		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
 	}
 
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
 	sheetName := request.SheetName
 
 	// 削除する行数が正の値であることを確認
@@ -1110,6 +1462,14 @@ func (gs *GoogleSheets) DeleteRowsHandler(ctx context.Context, cc *mcp.ServerSes
 		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
 	}
 
+	// 保護された範囲にかかる行の削除を確認
+	if !request.IgnoreProtection {
+		target := a1Range{startRow: request.StartRow, endRow: request.StartRow + request.Count - 1}
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, sheetName, []string{target.String()}); err != nil {
+			return nil, err
+		}
+	}
+
 	// 削除前のデータを取得（削除範囲のデータを保存）
 	service, err := gs.auth.GetSheetsService(ctx)
 	if err != nil {
@@ -1117,9 +1477,11 @@ func (gs *GoogleSheets) DeleteRowsHandler(ctx context.Context, cc *mcp.ServerSes
 	}
 
 	// 削除する範囲を指定（A1表記に変換）
-	startRowA1 := request.StartRow
-	endRowA1 := startRowA1 + request.Count - 1
-	rangeToDelete := fmt.Sprintf("%s!%d:%d", sheetName, startRowA1, endRowA1)
+	rangeToDelete := a1Range{
+		sheet:    sheetName,
+		startRow: request.StartRow,
+		endRow:   request.StartRow + request.Count - 1,
+	}.String()
 
 	// 削除前のデータを取得
 	prevData, err := service.Spreadsheets.Values.Get(spreadsheetId, rangeToDelete).Do()
@@ -1170,7 +1532,7 @@ func (gs *GoogleSheets) DeleteRowsHandler(ctx context.Context, cc *mcp.ServerSes
 
 	// 削除前のデータを表示用に整形
 
-	prevDataStr := "\n\nDeleted data details:\n\n" + formatTableData(1, request.StartRow, prevData.Values)
+	prevDataStr := "\n\nDeleted data details:\n\n" + formatTableData(a1Cell(1, request.StartRow), prevData.Values)
 
 	return &mcp.CallToolResultFor[any]{
 		Content: []mcp.Content{
@@ -1190,6 +1552,11 @@ func (gs *GoogleSheets) DeleteColumnsHandler(ctx context.Context, cc *mcp.Server
 		return nil, fmt.Errorf("failed to get spreadsheet ID: %w", err)
 	}
 
+	// シートIDで指定された場合はシート名に変換する
+	if request.SheetName, err = gs.resolveSheetName(ctx, spreadsheetId, request.SheetName); err != nil {
+		return nil, err
+	}
+
 	sheetName := request.SheetName
 
 	// 削除する列数が正の値であることを確認
@@ -1208,6 +1575,14 @@ func (gs *GoogleSheets) DeleteColumnsHandler(ctx context.Context, cc *mcp.Server
 		return nil, fmt.Errorf("failed to get sheet ID: %w", err)
 	}
 
+	// 保護された範囲にかかる列の削除を確認
+	if !request.IgnoreProtection {
+		target := a1Range{startCol: request.StartColumn, endCol: request.StartColumn + request.Count - 1}
+		if err := gs.checkProtectedRanges(ctx, spreadsheetId, sheetName, []string{target.String()}); err != nil {
+			return nil, err
+		}
+	}
+
 	// 削除前のデータを取得（削除範囲のデータを保存）
 	service, err := gs.auth.GetSheetsService(ctx)
 	if err != nil {
@@ -1215,9 +1590,11 @@ func (gs *GoogleSheets) DeleteColumnsHandler(ctx context.Context, cc *mcp.Server
 	}
 
 	// 削除する範囲を指定（A1表記に変換）
-	startColA1 := columnIndexToLetter(request.StartColumn)
-	endColA1 := columnIndexToLetter(request.StartColumn + request.Count - 1)
-	rangeToDelete := fmt.Sprintf("%s!%s:%s", sheetName, startColA1, endColA1)
+	rangeToDelete := a1Range{
+		sheet:    sheetName,
+		startCol: request.StartColumn,
+		endCol:   request.StartColumn + request.Count - 1,
+	}.String()
 
 	// 削除前のデータを取得
 	prevData, err := service.Spreadsheets.Values.Get(spreadsheetId, rangeToDelete).Do()
@@ -1269,7 +1646,7 @@ func (gs *GoogleSheets) DeleteColumnsHandler(ctx context.Context, cc *mcp.Server
 		prevRowCount, prevColCount)
 
 	// 削除前のデータを表示用に整形
-	prevDataStr := "\n\nDeleted data details:\n\n" + formatTableData(request.StartColumn, 1, prevData.Values)
+	prevDataStr := "\n\nDeleted data details:\n\n" + formatTableData(a1Cell(request.StartColumn, 1), prevData.Values)
 
 	return &mcp.CallToolResultFor[any]{
 		Content: []mcp.Content{
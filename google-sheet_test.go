@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Google API へのリクエストを handler で処理する GoogleSheets を作成する
+// 認証済みのトークンを設定し、HTTP クライアントはコンテキスト経由で差し替える
+func newTestGoogleSheets(t *testing.T, handler http.HandlerFunc) (*GoogleSheets, context.Context) {
+	t.Helper()
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		recorder := httptest.NewRecorder()
+		handler(recorder, r)
+		return recorder.Result(), nil
+	})}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client)
+	cfg := &Config{FolderID: "root"}
+	auth := &GoogleAuth{
+		cfg:    cfg,
+		config: &oauth2.Config{},
+		token:  &oauth2.Token{AccessToken: "test", Expiry: time.Now().Add(time.Hour)},
+	}
+	return &GoogleSheets{cfg: cfg, auth: auth}, ctx
+}
+
+// JSON のレスポンスを書き込む
+func writeTestJSON(t *testing.T, w http.ResponseWriter, value any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		t.Errorf("failed to encode response: %v", err)
+	}
+}
+
+// GetSheetDataHandler 用の Google API の偽物（data は読み取る範囲ごとの値）
+// 読み取られた範囲とリンクを取得した範囲を記録する
+func newSheetDataTestGoogleSheets(t *testing.T, grid *sheets.GridProperties, data map[string][][]interface{}) (*GoogleSheets, context.Context, *[]string, *[]string) {
+	t.Helper()
+	var readRanges, linkRanges []string
+	gs, ctx := newTestGoogleSheets(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/drive/v3/files":
+			writeTestJSON(t, w, &drive.FileList{Files: []*drive.File{{Id: "ss1"}}})
+		case r.URL.Path == "/v4/spreadsheets/ss1" && r.URL.Query().Get("includeGridData") == "true":
+			linkRanges = append(linkRanges, r.URL.Query()["ranges"]...)
+			writeTestJSON(t, w, &sheets.Spreadsheet{})
+		case r.URL.Path == "/v4/spreadsheets/ss1":
+			writeTestJSON(t, w, &sheets.Spreadsheet{Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{SheetId: 0, Title: "Sheet1", GridProperties: grid}},
+			}})
+		case strings.HasPrefix(r.URL.Path, "/v4/spreadsheets/ss1/values/Sheet1!"):
+			readRange := strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/ss1/values/Sheet1!")
+			readRanges = append(readRanges, readRange)
+			writeTestJSON(t, w, &sheets.ValueRange{Values: data[readRange]})
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+	return gs, ctx, &readRanges, &linkRanges
+}
+
+// 1行目から rows 行 cols 列の値
+func testRows(rows, cols int) [][]interface{} {
+	values := make([][]interface{}, rows)
+	for i := range values {
+		values[i] = make([]interface{}, cols)
+		for j := range values[i] {
+			values[i][j] = fmt.Sprintf("r%dc%d", i+1, j+1)
+		}
+	}
+	return values
+}
+
+func TestGetSheetDataHandler(t *testing.T) {
+	grid := &sheets.GridProperties{RowCount: 1000, ColumnCount: 26}
+	tests := []struct {
+		name      string
+		request   GetSheetDataRequest
+		data      map[string][][]interface{}
+		wantRead  string
+		wantLinks []string
+		want      []string
+		notWant   []string
+	}{
+		{
+			// データの少ない既定の大きさのシートは1回で読み終わる
+			name:      "small data on a default sheet",
+			request:   GetSheetDataRequest{},
+			data:      map[string][][]interface{}{"A1:Z1000": testRows(10, 3)},
+			wantRead:  "A1:Z1000",
+			wantLinks: []string{"Sheet1!A1:Z1000"},
+			want:      []string{"| **10** | r10c1 | r10c2 | r10c3 |", "Total: 10 rows x 3 columns"},
+			notWant:   []string{"Truncated", "Next range"},
+		},
+		{
+			name:     "empty range",
+			request:  GetSheetDataRequest{Range: "A1:C10"},
+			wantRead: "A1:C10",
+			want:     []string{"No data found in A1:C10."},
+			notWant:  []string{"Next range"},
+		},
+		{
+			name:      "explicit range limited by max_rows",
+			request:   GetSheetDataRequest{Range: "A1:B20", MaxRows: 5},
+			data:      map[string][][]interface{}{"A1:B5": testRows(5, 2)},
+			wantRead:  "A1:B5",
+			wantLinks: []string{"Sheet1!A1:B5"},
+			want:      []string{"| **5** | r5c1 | r5c2 |", "Truncated to 5 rows. Next range: A6:B20"},
+		},
+		{
+			// max_rows に満たない行数しか返らない場合は続きを示さない
+			name:      "explicit range ending before max_rows",
+			request:   GetSheetDataRequest{Range: "A1:B20", MaxRows: 5},
+			data:      map[string][][]interface{}{"A1:B5": testRows(3, 2)},
+			wantRead:  "A1:B5",
+			wantLinks: []string{"Sheet1!A1:B5"},
+			want:      []string{"Total: 3 rows x 2 columns"},
+			notWant:   []string{"Next range"},
+		},
+		{
+			// セル数の上限は返ってきた行の幅（2列）で数える
+			name:      "truncated by max_cells",
+			request:   GetSheetDataRequest{MaxCells: 10},
+			data:      map[string][][]interface{}{"A1:Z10": testRows(10, 2)},
+			wantRead:  "A1:Z10",
+			wantLinks: []string{"Sheet1!A1:Z5"},
+			want:      []string{"| **5** | r5c1 | r5c2 |", "Truncated to 5 rows. Next range: A6:Z1000"},
+			notWant:   []string{"r6c1"},
+		},
+	}
+	for _, tt := range tests {
+		tt.request.SpreadsheetName = "Budget"
+		tt.request.SheetName = "Sheet1"
+		gs, ctx, readRanges, linkRanges := newSheetDataTestGoogleSheets(t, grid, tt.data)
+		result, err := gs.GetSheetDataHandler(ctx, nil, &mcp.CallToolParamsFor[GetSheetDataRequest]{Arguments: tt.request})
+		if err != nil {
+			t.Errorf("%s: GetSheetDataHandler failed: %v", tt.name, err)
+			continue
+		}
+		if len(*readRanges) != 1 || (*readRanges)[0] != tt.wantRead {
+			t.Errorf("%s: read %v, want [%s]", tt.name, *readRanges, tt.wantRead)
+		}
+		if !reflect.DeepEqual(*linkRanges, tt.wantLinks) {
+			t.Errorf("%s: links were read from %v, want %v", tt.name, *linkRanges, tt.wantLinks)
+		}
+		text := result.Content[0].(*mcp.TextContent).Text
+		for _, want := range tt.want {
+			if !strings.Contains(text, want) {
+				t.Errorf("%s: result does not contain %q:\n%s", tt.name, want, text)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(text, notWant) {
+				t.Errorf("%s: result contains %q:\n%s", tt.name, notWant, text)
+			}
+		}
+	}
+}
+
+func TestAddRowsAndColumnsHandler(t *testing.T) {
+	tests := []struct {
+		name              string
+		columns           bool
+		start             int64
+		inheritFromBefore bool
+		wantAppend        bool
+		wantStartIndex    int64
+		wantEndIndex      int64
+		wantInherit       bool
+		wantMessage       string
+		wantErrMsg        string
+	}{
+		// 末尾への追加は AppendDimension（書式を引き継ぐ場合は末尾の位置への InsertDimension）
+		{name: "rows at the end", wantAppend: true, wantMessage: "(new rows 11-13)"},
+		{name: "rows at the end inheriting format", inheritFromBefore: true, wantStartIndex: 10, wantEndIndex: 13, wantInherit: true, wantMessage: "(new rows 11-13)"},
+		{name: "rows before the first row", start: 1, wantStartIndex: 0, wantEndIndex: 3, wantMessage: "at index 1"},
+		{name: "rows in the middle", start: 4, wantStartIndex: 3, wantEndIndex: 6, wantMessage: "at index 4"},
+		{name: "rows in the middle inheriting format", start: 4, inheritFromBefore: true, wantStartIndex: 3, wantEndIndex: 6, wantInherit: true},
+		{name: "rows before the first row inheriting format", start: 1, inheritFromBefore: true, wantErrMsg: "cannot be used when inserting before the first row"},
+		{name: "columns at the end", columns: true, wantAppend: true, wantMessage: "at the end"},
+		{name: "columns at the end inheriting format", columns: true, inheritFromBefore: true, wantStartIndex: 5, wantEndIndex: 8, wantInherit: true},
+		{name: "columns before the first column", columns: true, start: 1, wantStartIndex: 0, wantEndIndex: 3},
+		{name: "columns in the middle inheriting format", columns: true, start: 2, inheritFromBefore: true, wantStartIndex: 1, wantEndIndex: 4, wantInherit: true},
+		{name: "columns before the first column inheriting format", columns: true, start: 1, inheritFromBefore: true, wantErrMsg: "cannot be used when inserting before the first column"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet := &sheets.Sheet{Properties: &sheets.SheetProperties{
+				SheetId:        2,
+				Title:          "Sheet1",
+				GridProperties: &sheets.GridProperties{RowCount: 10, ColumnCount: 5},
+			}}
+			gs, ctx, requests := newLayoutTestGoogleSheets(t, sheet, nil)
+
+			var (
+				result *mcp.CallToolResultFor[any]
+				err    error
+			)
+			wantDimension := "ROWS"
+			if tt.columns {
+				wantDimension = "COLUMNS"
+				result, err = gs.AddColumnsHandler(ctx, nil, &mcp.CallToolParamsFor[AddColumnsRequest]{Arguments: AddColumnsRequest{
+					SpreadsheetName:   "Budget",
+					SheetName:         "Sheet1",
+					Count:             3,
+					StartColumn:       tt.start,
+					InheritFromBefore: tt.inheritFromBefore,
+				}})
+			} else {
+				result, err = gs.AddRowsHandler(ctx, nil, &mcp.CallToolParamsFor[AddRowsRequest]{Arguments: AddRowsRequest{
+					SpreadsheetName:   "Budget",
+					SheetName:         "Sheet1",
+					Count:             3,
+					StartRow:          tt.start,
+					InheritFromBefore: tt.inheritFromBefore,
+				}})
+			}
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErrMsg)
+				}
+				if len(*requests) != 0 {
+					t.Errorf("sent requests for an invalid position: %+v", *requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("handler failed: %v", err)
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("sent %d requests, want 1", len(*requests))
+			}
+
+			request := (*requests)[0]
+			if tt.wantAppend {
+				if request.AppendDimension == nil {
+					t.Fatalf("expected AppendDimension, got %+v", request)
+				}
+				if got := request.AppendDimension; got.SheetId != 2 || got.Dimension != wantDimension || got.Length != 3 {
+					t.Errorf("AppendDimension = %+v", got)
+				}
+			} else {
+				if request.InsertDimension == nil {
+					t.Fatalf("expected InsertDimension, got %+v", request)
+				}
+				insert := request.InsertDimension
+				if got := insert.Range; got.SheetId != 2 || got.Dimension != wantDimension || got.StartIndex != tt.wantStartIndex || got.EndIndex != tt.wantEndIndex {
+					t.Errorf("InsertDimension range = %+v, want %d-%d", got, tt.wantStartIndex, tt.wantEndIndex)
+				}
+				if insert.InheritFromBefore != tt.wantInherit {
+					t.Errorf("InheritFromBefore = %v, want %v", insert.InheritFromBefore, tt.wantInherit)
+				}
+			}
+			if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, tt.wantMessage) {
+				t.Errorf("result does not contain %q: %s", tt.wantMessage, text)
+			}
+		})
+	}
+}
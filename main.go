@@ -43,7 +43,7 @@ func main() {
 			Version: "v1.0.0",
 		},
 		&mcp.ServerOptions{
-			Instructions: "MCP server for Google Drive file management and Google Sheets operations. Workflow: 1) Use google_drive_list_files to browse and find spreadsheets, 2) Use google_sheets_list_sheets to see sheets in a spreadsheet, 3) Use google_sheets_read_data to view content, 4) Use other google_sheets_* tools to modify data.",
+			Instructions: "MCP server for Google Drive file management and Google Sheets operations. Workflow: 1) Use google_drive_list_files to browse and find spreadsheets, 2) Use google_sheets_list_sheets to see sheets in a spreadsheet (or google_sheets_describe for an overview of their contents), 3) Use google_sheets_read_data to view content, 4) Use other google_sheets_* tools to modify data. Paths are relative to the root folder and separated by '/'; write a '/' that is part of a file or folder name as '\\/' and a backslash as '\\\\'.",
 		},
 	)
 
@@ -53,11 +53,21 @@ func main() {
 		&mcp.Tool{
 			Name:        "google_drive_list_files",
 			Title:       "Google Drive: List Files and Folders",
-			Description: "Browse and list files and folders in Google Drive. Use this to explore directory structure and find spreadsheets before working with them.",
+			Description: "Browse and list files and folders in Google Drive. Use this to explore directory structure and find spreadsheets before working with them. Supports filtering by MIME type, name and modification date, sorting, recursive listing and tree output.",
 			InputSchema: ListFilesInputSchema,
 		},
 		drive.ListFilesHandler,
 	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_drive_search",
+			Title:       "Google Drive: Search Files",
+			Description: "Search files by name or full-text content within the root folder tree. Returns each hit's path relative to the root folder, which can be passed to other tools.",
+			InputSchema: SearchFilesInputSchema,
+		},
+		drive.SearchFilesHandler,
+	)
 	mcp.AddTool(
 		server,
 		&mcp.Tool{
@@ -78,17 +88,77 @@ func main() {
 		},
 		drive.RenameFileHandler,
 	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_drive_export_file",
+			Title:       "Google Drive: Export Spreadsheet",
+			Description: "Export a Google Spreadsheet as XLSX, ODS, PDF, CSV or TSV. A single sheet can be exported as CSV, TSV or PDF. The result is written to the export directory or returned as resource content.",
+			InputSchema: ExportFileInputSchema,
+		},
+		drive.ExportFileHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_drive_list_comments",
+			Title:       "Google Drive: List Comments",
+			Description: "List the comments on a spreadsheet with their IDs, authors, quoted text and replies. Only open comments are listed unless include_resolved is set.",
+			InputSchema: ListCommentsInputSchema,
+		},
+		drive.ListCommentsHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_drive_add_comment",
+			Title:       "Google Drive: Add Comment",
+			Description: "Add a comment to a spreadsheet. The comment applies to the whole file because the Drive API cannot anchor comments to cells, so mention the sheet and cells in the text.",
+			InputSchema: AddCommentInputSchema,
+		},
+		drive.AddCommentHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_drive_reply_comment",
+			Title:       "Google Drive: Reply to Comment",
+			Description: "Reply to a comment on a spreadsheet. Use google_drive_list_comments to find comment IDs.",
+			InputSchema: ReplyCommentInputSchema,
+		},
+		drive.ReplyCommentHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_drive_resolve_comment",
+			Title:       "Google Drive: Resolve Comment",
+			Description: "Mark a comment on a spreadsheet as resolved, optionally with a reply explaining what was changed.",
+			InputSchema: ResolveCommentInputSchema,
+		},
+		drive.ResolveCommentHandler,
+	)
 	// Register Google Sheets tools
 	mcp.AddTool(
 		server,
 		&mcp.Tool{
 			Name:        "google_sheets_list_sheets",
 			Title:       "Google Sheets: List Sheets in Spreadsheet",
-			Description: "List all sheets (tabs) within a specific Google Spreadsheet. Use this after finding the spreadsheet with google_drive_list_files.",
+			Description: "List all sheets (tabs) within a specific Google Spreadsheet. Use this after finding the spreadsheet with google_drive_list_files. Either the name or the ID of a sheet can be passed as sheet_name to other tools.",
 			InputSchema: ListSheetsInputSchema,
 		},
 		sheet.ListSheetsHandler,
 	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_describe",
+			Title:       "Google Sheets: Describe Sheets",
+			Description: "Get a cheap overview of a spreadsheet before reading data: per sheet grid size, used range, header row detection, and per-column type, null ratio, distinct count, min/max and sample values.",
+			InputSchema: DescribeInputSchema,
+		},
+		sheet.DescribeHandler,
+	)
 	mcp.AddTool(
 		server,
 		&mcp.Tool{
@@ -114,17 +184,57 @@ func main() {
 		&mcp.Tool{
 			Name:        "google_sheets_read_data",
 			Title:       "Google Sheets: Read Data from Sheet",
-			Description: "Read data from a specific sheet in a Google Spreadsheet. Specify spreadsheet name, sheet name, and optionally a cell range (e.g., A1:C10). This is how you 'open' and view spreadsheet content.",
+			Description: "Read data from a specific sheet in a Google Spreadsheet. Specify spreadsheet name, sheet name, and optionally a cell range (e.g., A1:C10). This is how you 'open' and view spreadsheet content. Large reads are truncated by max_rows/max_cells; the response then gives the next range to read. Hyperlinks in the cells are listed after the data.",
 			InputSchema: GetSheetDataInputSchema,
 		},
 		sheet.GetSheetDataHandler,
 	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_query",
+			Title:       "Google Sheets: Query Data",
+			Description: "Run a SQL-like query (SELECT ... FROM ... JOIN ... WHERE ... GROUP BY ... HAVING ... ORDER BY ... LIMIT) over sheets with a header row and get only the result table. Use this instead of reading whole sheets to answer questions like totals per group; two sheets can be joined on a key column.",
+			InputSchema: QueryInputSchema,
+		},
+		sheet.QueryHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_get_records",
+			Title:       "Google Sheets: Get Records",
+			Description: "Read rows of a sheet whose first row is a header row as JSON objects keyed by header name, optionally filtered by column=value. Each record includes its row number as _row. Unlike ranges, this keeps working when columns are inserted or reordered.",
+			InputSchema: GetRecordsInputSchema,
+		},
+		sheet.GetRecordsHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_upsert_records",
+			Title:       "Google Sheets: Upsert Records",
+			Description: "Write records (objects keyed by header name) to a sheet whose first row is a header row. Records matching an existing row on the key column update only the given cells of that row; the rest are appended after the last row. Previous data of updated rows is returned.",
+			InputSchema: UpsertRecordsInputSchema,
+		},
+		sheet.UpsertRecordsHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_delete_records",
+			Title:       "Google Sheets: Delete Records",
+			Description: "Delete the rows of all records matching a column=value filter in a sheet whose first row is a header row. Deleted data is returned so it can be restored.",
+			InputSchema: DeleteRecordsInputSchema,
+		},
+		sheet.DeleteRecordsHandler,
+	)
 	mcp.AddTool(
 		server,
 		&mcp.Tool{
 			Name:        "google_sheets_add_rows",
 			Title:       "Google Sheets: Insert Rows",
-			Description: "Insert new empty rows in a Google Sheet. Specify spreadsheet name, sheet name, number of rows to add, and starting row position.",
+			Description: "Insert new empty rows in a Google Sheet. Specify spreadsheet name, sheet name, number of rows to add, and starting row position (omit it to append at the end of the sheet).",
 			InputSchema: AddRowsInputSchema,
 		},
 		sheet.AddRowsHandler,
@@ -134,7 +244,7 @@ func main() {
 		&mcp.Tool{
 			Name:        "google_sheets_add_columns",
 			Title:       "Google Sheets: Insert Columns",
-			Description: "Insert new empty columns in a Google Sheet. Specify spreadsheet name, sheet name, number of columns to add, and starting column position.",
+			Description: "Insert new empty columns in a Google Sheet. Specify spreadsheet name, sheet name, number of columns to add, and starting column position (omit it to append at the end of the sheet).",
 			InputSchema: AddColumnsInputSchema,
 		},
 		sheet.AddColumnsHandler,
@@ -144,7 +254,7 @@ func main() {
 		&mcp.Tool{
 			Name:        "google_sheets_update_cells",
 			Title:       "Google Sheets: Update Cell Values",
-			Description: "Update cell values in a specific range of a Google Sheet. Provide spreadsheet name, sheet name, cell range (e.g., A1:C3), and 2D array of values.",
+			Description: "Update cell values in a specific range of a Google Sheet. Provide spreadsheet name, sheet name, cell range (e.g., A1:C3), and 2D array of values. Cells can be written as links or rich text by passing an object instead of a value. Set validate to check the values against existing data validation rules before writing.",
 			InputSchema: UpdateCellsInputSchema,
 		},
 		sheet.UpdateCellsHandler,
@@ -154,11 +264,221 @@ func main() {
 		&mcp.Tool{
 			Name:        "google_sheets_batch_update_cells",
 			Title:       "Google Sheets: Batch Update Multiple Ranges",
-			Description: "Update multiple cell ranges in a Google Sheet in a single operation. Provide spreadsheet name, sheet name, and a map of ranges to values.",
+			Description: "Update multiple cell ranges in a Google Sheet in a single operation. Provide spreadsheet name, sheet name, and a map of ranges to values. Cells can be written as links or rich text by passing an object instead of a value. Set validate to check the values against existing data validation rules before writing.",
 			InputSchema: BatchUpdateCellsInputSchema,
 		},
 		sheet.BatchUpdateCellsHandler,
 	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_clear_range",
+			Title:       "Google Sheets: Clear Range",
+			Description: "Clear the values of one or more ranges of any size, optionally also clearing formats and notes. Use this instead of writing empty strings. Previous data is returned so it can be restored.",
+			InputSchema: ClearRangeInputSchema,
+		},
+		sheet.ClearRangeHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_find_replace",
+			Title:       "Google Sheets: Find and Replace",
+			Description: "Find text (or a regular expression) in a range, a sheet or all sheets and replace it in one request. Supports match case, entire cell and formulas. Use find_only to list the matching cells and values without modifying anything. Previous values of the matching cells are returned.",
+			InputSchema: FindReplaceInputSchema,
+		},
+		sheet.FindReplaceHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_sort_range",
+			Title:       "Google Sheets: Sort Range",
+			Description: "Sort a range or the whole sheet in place by one or more columns (column letter or header name), keeping formulas and formatting intact. Set has_header_row to keep the header row in place.",
+			InputSchema: SortRangeInputSchema,
+		},
+		sheet.SortRangeHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_set_basic_filter",
+			Title:       "Google Sheets: Set Basic Filter",
+			Description: "Set the basic filter of a sheet on a range whose first row is a header row. Rows can be hidden by value or by condition per column (column letter or header name), and the filter can sort the range. Replaces any existing basic filter.",
+			InputSchema: SetBasicFilterInputSchema,
+		},
+		sheet.SetBasicFilterHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_clear_basic_filter",
+			Title:       "Google Sheets: Clear Basic Filter",
+			Description: "Remove the basic filter of a sheet and show all rows again.",
+			InputSchema: ClearBasicFilterInputSchema,
+		},
+		sheet.ClearBasicFilterHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_set_data_validation",
+			Title:       "Google Sheets: Set Data Validation",
+			Description: "Set or remove a data validation rule on a range: dropdowns from a list or a range, number or date bounds, checkboxes, or a custom formula, in strict (reject) or warn mode.",
+			InputSchema: SetDataValidationInputSchema,
+		},
+		sheet.SetDataValidationHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_get_data_validation",
+			Title:       "Google Sheets: Get Data Validation",
+			Description: "List the data validation rules of a sheet or range, grouped by rule with the cell ranges they apply to. Check this before writing to cells with dropdowns.",
+			InputSchema: GetDataValidationInputSchema,
+		},
+		sheet.GetDataValidationHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_set_note",
+			Title:       "Google Sheets: Set Note",
+			Description: "Set or remove the note on cells. Every cell in the range gets the same note; an empty note removes the notes. Previous notes are returned for undo.",
+			InputSchema: SetNoteInputSchema,
+		},
+		sheet.SetNoteHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_get_notes",
+			Title:       "Google Sheets: Get Notes",
+			Description: "List the cell notes in a sheet or range.",
+			InputSchema: GetNotesInputSchema,
+		},
+		sheet.GetNotesHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_list_conditional_formats",
+			Title:       "Google Sheets: List Conditional Formats",
+			Description: "List the conditional format rules of a sheet with their index, ranges, condition and format. Use the index to update or delete a rule.",
+			InputSchema: ListConditionalFormatsInputSchema,
+		},
+		sheet.ListConditionalFormatsHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_add_conditional_format",
+			Title:       "Google Sheets: Add Conditional Format",
+			Description: "Add a conditional format rule to ranges of a sheet: highlight cells matching a condition or custom formula, or color them with a gradient scale. Colors are hex codes such as '#F4CCCC'.",
+			InputSchema: AddConditionalFormatInputSchema,
+		},
+		sheet.AddConditionalFormatHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_update_conditional_format",
+			Title:       "Google Sheets: Update Conditional Format",
+			Description: "Replace the conditional format rule at an index and/or move it to a new index (priority). The previous rule is returned so the change can be undone.",
+			InputSchema: UpdateConditionalFormatInputSchema,
+		},
+		sheet.UpdateConditionalFormatHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_delete_conditional_format",
+			Title:       "Google Sheets: Delete Conditional Format",
+			Description: "Delete the conditional format rule at an index. The deleted rule is returned so it can be added again.",
+			InputSchema: DeleteConditionalFormatInputSchema,
+		},
+		sheet.DeleteConditionalFormatHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_list_named_ranges",
+			Title:       "Google Sheets: List Named Ranges",
+			Description: "List the named ranges of a spreadsheet with the sheet and cell range each one refers to. Named ranges can be passed as the range of other tools.",
+			InputSchema: ListNamedRangesInputSchema,
+		},
+		sheet.ListNamedRangesHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_create_named_range",
+			Title:       "Google Sheets: Create Named Range",
+			Description: "Give a name to a cell range of a sheet. The name can then be used in formulas and as the range of other tools.",
+			InputSchema: CreateNamedRangeInputSchema,
+		},
+		sheet.CreateNamedRangeHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_update_named_range",
+			Title:       "Google Sheets: Update Named Range",
+			Description: "Rename a named range and/or change the sheet or cell range it refers to. The previous definition is returned so the change can be undone.",
+			InputSchema: UpdateNamedRangeInputSchema,
+		},
+		sheet.UpdateNamedRangeHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_delete_named_range",
+			Title:       "Google Sheets: Delete Named Range",
+			Description: "Delete a named range. The cells it refers to are not changed.",
+			InputSchema: DeleteNamedRangeInputSchema,
+		},
+		sheet.DeleteNamedRangeHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_list_protected_ranges",
+			Title:       "Google Sheets: List Protected Ranges",
+			Description: "List the protected ranges and protected sheets with their IDs, descriptions, editors and warning-only mode.",
+			InputSchema: ListProtectedRangesInputSchema,
+		},
+		sheet.ListProtectedRangesHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_protect_range",
+			Title:       "Google Sheets: Protect Range",
+			Description: "Protect a range or a whole sheet. Either restrict editing to a list of editors, or only show a warning when someone edits it.",
+			InputSchema: ProtectRangeInputSchema,
+		},
+		sheet.ProtectRangeHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_update_protected_range",
+			Title:       "Google Sheets: Update Protected Range",
+			Description: "Change the range, description, editors or warning-only mode of a protected range. Returns the previous settings for undo.",
+			InputSchema: UpdateProtectedRangeInputSchema,
+		},
+		sheet.UpdateProtectedRangeHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_unprotect_range",
+			Title:       "Google Sheets: Unprotect Range",
+			Description: "Remove a protected range or sheet protection. The cells themselves are not changed.",
+			InputSchema: UnprotectRangeInputSchema,
+		},
+		sheet.UnprotectRangeHandler,
+	)
 	mcp.AddTool(
 		server,
 		&mcp.Tool{
@@ -179,6 +499,86 @@ func main() {
 		},
 		sheet.DeleteColumnsHandler,
 	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_move_dimension",
+			Title:       "Google Sheets: Move Rows or Columns",
+			Description: "Move a block of rows or columns to another position, keeping formatting and updating references in formulas. The response reports the positions before and after the move.",
+			InputSchema: MoveDimensionInputSchema,
+		},
+		sheet.MoveDimensionHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_merge_cells",
+			Title:       "Google Sheets: Merge Cells",
+			Description: "Merge a range into one cell, or merge each of its rows or columns. Only the top-left value of each merged area is kept.",
+			InputSchema: MergeCellsInputSchema,
+		},
+		sheet.MergeCellsHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_unmerge_cells",
+			Title:       "Google Sheets: Unmerge Cells",
+			Description: "Unmerge all merged cells inside a range.",
+			InputSchema: UnmergeCellsInputSchema,
+		},
+		sheet.UnmergeCellsHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_freeze",
+			Title:       "Google Sheets: Freeze Rows and Columns",
+			Description: "Set the number of frozen rows at the top and/or frozen columns at the left of a sheet. Use 0 to unfreeze.",
+			InputSchema: FreezeInputSchema,
+		},
+		sheet.FreezeHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_resize_dimension",
+			Title:       "Google Sheets: Resize Rows or Columns",
+			Description: "Set the height of rows or the width of columns in pixels, or auto-resize them to fit their contents when pixel_size is not specified.",
+			InputSchema: ResizeDimensionInputSchema,
+		},
+		sheet.ResizeDimensionHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_hide_dimension",
+			Title:       "Google Sheets: Hide Rows or Columns",
+			Description: "Hide rows or columns of a sheet. Their data is kept.",
+			InputSchema: DimensionVisibilityInputSchema,
+		},
+		sheet.HideDimensionHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_unhide_dimension",
+			Title:       "Google Sheets: Unhide Rows or Columns",
+			Description: "Show hidden rows or columns of a sheet again.",
+			InputSchema: DimensionVisibilityInputSchema,
+		},
+		sheet.UnhideDimensionHandler,
+	)
+	mcp.AddTool(
+		server,
+		&mcp.Tool{
+			Name:        "google_sheets_import",
+			Title:       "Google Sheets: Import CSV/TSV/XLSX",
+			Description: "Import a local CSV/TSV file from the import directory or inline CSV/TSV text into a sheet (replace, append, or at an anchor cell). XLSX and CSV files can also be uploaded and converted to a new Google Spreadsheet.",
+			InputSchema: ImportInputSchema,
+		},
+		sheet.ImportHandler,
+	)
 
 	if err := server.Run(ctx, mcp.NewStdioTransport()); err != nil {
 		logger.ErrorContext(ctx, "failed to run server", "error", err)
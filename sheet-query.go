@@ -0,0 +1,1705 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// シートのデータに対する SQL 風クエリの解析と評価
+//
+// 対応する構文:
+//
+//	SELECT * | expr [AS alias], ...
+//	[FROM sheet [alias] [[INNER | LEFT] JOIN sheet [alias] ON expr = expr]]
+//	[WHERE expr] [GROUP BY expr, ...] [HAVING expr]
+//	[ORDER BY expr [ASC | DESC], ...] [LIMIT n] [OFFSET n]
+//
+// 列はヘッダー行の名前（空白などを含む場合は `...` または "..." で囲む）か列文字（A, B, ...）で指定する。
+// 集計関数は COUNT / SUM / AVG / MIN / MAX、スカラー関数は LOWER / UPPER に対応する。
+
+type sqlTokenKind int
+
+const (
+	sqlTokenEOF sqlTokenKind = iota
+	sqlTokenIdent
+	sqlTokenQuotedIdent
+	sqlTokenNumber
+	sqlTokenString
+	sqlTokenSymbol
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+	pos  int
+}
+
+// クエリ文字列をトークンに分割する
+func tokenizeQuery(input string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'':
+			// 文字列リテラル（'' はエスケープされた '）
+			start := i
+			var text strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated string literal at position %d", start+1)
+				}
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						text.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				text.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenString, text: text.String(), pos: start})
+		case r == '`' || r == '"':
+			// 引用符で囲まれた識別子
+			start := i
+			quote := r
+			var text strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated quoted identifier at position %d", start+1)
+				}
+				if runes[i] == quote {
+					if i+1 < len(runes) && runes[i+1] == quote {
+						text.WriteRune(quote)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				text.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenQuotedIdent, text: text.String(), pos: start})
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			start := i
+			text := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "<=", ">=", "<>", "!=":
+					text = two
+				}
+			}
+			if !strings.Contains(",().*+-/=<>", text) && len(text) == 1 {
+				return nil, fmt.Errorf("unexpected character '%c' at position %d", r, start+1)
+			}
+			i += len([]rune(text))
+			tokens = append(tokens, sqlToken{kind: sqlTokenSymbol, text: text, pos: start})
+		}
+	}
+	tokens = append(tokens, sqlToken{kind: sqlTokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+// 予約語（識別子として使う場合は引用符で囲む）
+var sqlKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "JOIN": true, "INNER": true, "LEFT": true, "OUTER": true, "ON": true,
+	"WHERE": true, "GROUP": true, "BY": true, "HAVING": true, "ORDER": true, "ASC": true, "DESC": true,
+	"LIMIT": true, "OFFSET": true, "AND": true, "OR": true, "NOT": true, "LIKE": true, "CONTAINS": true,
+	"STARTS": true, "ENDS": true, "WITH": true, "IS": true, "NULL": true, "TRUE": true, "FALSE": true,
+	"AS": true, "IN": true, "DISTINCT": true,
+}
+
+// 式
+type queryExpr interface {
+	String() string
+}
+
+type literalExpr struct {
+	value interface{}
+}
+
+type columnExpr struct {
+	table string
+	name  string
+	index int // 評価対象のデータセットにおける列番号（bind で設定）
+}
+
+type unaryExpr struct {
+	op      string
+	operand queryExpr
+}
+
+type binaryExpr struct {
+	op          string
+	left, right queryExpr
+}
+
+type isNullExpr struct {
+	operand queryExpr
+	not     bool
+}
+
+type inExpr struct {
+	operand queryExpr
+	list    []queryExpr
+	not     bool
+}
+
+type funcExpr struct {
+	name     string
+	arg      queryExpr
+	star     bool
+	distinct bool
+}
+
+func (e *literalExpr) String() string {
+	switch v := e.value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return formatQueryValue(e.value)
+}
+
+func (e *columnExpr) String() string {
+	if e.table != "" {
+		return e.table + "." + e.name
+	}
+	return e.name
+}
+
+func (e *unaryExpr) String() string {
+	if e.op == "NOT" {
+		return "NOT " + e.operand.String()
+	}
+	return e.op + e.operand.String()
+}
+
+func (e *binaryExpr) String() string {
+	return e.left.String() + " " + e.op + " " + e.right.String()
+}
+
+func (e *isNullExpr) String() string {
+	if e.not {
+		return e.operand.String() + " IS NOT NULL"
+	}
+	return e.operand.String() + " IS NULL"
+}
+
+func (e *inExpr) String() string {
+	items := make([]string, len(e.list))
+	for i, item := range e.list {
+		items[i] = item.String()
+	}
+	op := " IN ("
+	if e.not {
+		op = " NOT IN ("
+	}
+	return e.operand.String() + op + strings.Join(items, ", ") + ")"
+}
+
+func (e *funcExpr) String() string {
+	if e.star {
+		return e.name + "(*)"
+	}
+	if e.distinct {
+		return e.name + "(DISTINCT " + e.arg.String() + ")"
+	}
+	return e.name + "(" + e.arg.String() + ")"
+}
+
+// 集計関数
+var aggregateFuncs = map[string]bool{"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true}
+
+// スカラー関数
+var scalarFuncs = map[string]bool{"LOWER": true, "UPPER": true}
+
+type selectItem struct {
+	expr  queryExpr
+	alias string
+}
+
+type orderItem struct {
+	expr queryExpr
+	desc bool
+}
+
+type tableRef struct {
+	name  string
+	alias string
+}
+
+// 参照名（別名があれば別名）
+func (t *tableRef) refName() string {
+	if t.alias != "" {
+		return t.alias
+	}
+	return t.name
+}
+
+type joinClause struct {
+	table    tableRef
+	left     bool
+	leftKey  queryExpr
+	rightKey queryExpr
+}
+
+// 解析済みのクエリ
+type sheetQuery struct {
+	star    bool
+	items   []selectItem
+	from    *tableRef
+	join    *joinClause
+	where   queryExpr
+	groupBy []queryExpr
+	having  queryExpr
+	orderBy []orderItem
+	limit   int // -1 は無制限
+	offset  int
+}
+
+// クエリが参照するシート名
+func (q *sheetQuery) tableNames() []string {
+	var names []string
+	if q.from != nil {
+		names = append(names, q.from.name)
+	}
+	if q.join != nil {
+		names = append(names, q.join.table.name)
+	}
+	return names
+}
+
+type queryParser struct {
+	tokens []sqlToken
+	pos    int
+}
+
+// クエリ文字列を解析する
+func parseSheetQuery(input string) (*sheetQuery, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	query, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+func (p *queryParser) peek() sqlToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() sqlToken {
+	token := p.tokens[p.pos]
+	if token.kind != sqlTokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+// 次のトークンが指定したキーワードかどうか
+func (p *queryParser) isKeyword(keywords ...string) bool {
+	token := p.peek()
+	if token.kind != sqlTokenIdent {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(token.text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// 次のトークンが指定したキーワードなら読み進める
+func (p *queryParser) acceptKeyword(keyword string) bool {
+	if p.isKeyword(keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return p.errorf("expected %s", keyword)
+	}
+	return nil
+}
+
+func (p *queryParser) isSymbol(symbol string) bool {
+	token := p.peek()
+	return token.kind == sqlTokenSymbol && token.text == symbol
+}
+
+func (p *queryParser) acceptSymbol(symbol string) bool {
+	if p.isSymbol(symbol) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.errorf("expected '%s'", symbol)
+	}
+	return nil
+}
+
+func (p *queryParser) errorf(format string, args ...any) error {
+	token := p.peek()
+	found := "end of query"
+	if token.kind != sqlTokenEOF {
+		found = "'" + token.text + "'"
+	}
+	return fmt.Errorf("query syntax error at position %d: %s, found %s", token.pos+1, fmt.Sprintf(format, args...), found)
+}
+
+// 識別子（予約語以外の単語、または引用符で囲まれた名前）を読む
+func (p *queryParser) parseIdent() (string, error) {
+	token := p.peek()
+	switch {
+	case token.kind == sqlTokenQuotedIdent:
+		p.pos++
+		return token.text, nil
+	case token.kind == sqlTokenIdent && !sqlKeywords[strings.ToUpper(token.text)]:
+		p.pos++
+		return token.text, nil
+	}
+	return "", p.errorf("expected a name")
+}
+
+func (p *queryParser) parseQuery() (*sheetQuery, error) {
+	query := &sheetQuery{limit: -1}
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+
+	// SELECT 句
+	if p.acceptSymbol("*") {
+		query.star = true
+	} else {
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			item := selectItem{expr: expr}
+			if p.acceptKeyword("AS") {
+				if item.alias, err = p.parseIdent(); err != nil {
+					return nil, err
+				}
+			}
+			query.items = append(query.items, item)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	// FROM 句と JOIN 句
+	if p.acceptKeyword("FROM") {
+		from, err := p.parseTableRef()
+		if err != nil {
+			return nil, err
+		}
+		query.from = from
+		if p.isKeyword("JOIN", "INNER", "LEFT") {
+			join := &joinClause{}
+			if p.acceptKeyword("LEFT") {
+				join.left = true
+				p.acceptKeyword("OUTER")
+			} else {
+				p.acceptKeyword("INNER")
+			}
+			if err := p.expectKeyword("JOIN"); err != nil {
+				return nil, err
+			}
+			table, err := p.parseTableRef()
+			if err != nil {
+				return nil, err
+			}
+			join.table = *table
+			if err := p.expectKeyword("ON"); err != nil {
+				return nil, err
+			}
+			if join.leftKey, err = p.parseAdditive(); err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol("="); err != nil {
+				return nil, err
+			}
+			if join.rightKey, err = p.parseAdditive(); err != nil {
+				return nil, err
+			}
+			query.join = join
+		}
+	}
+
+	var err error
+	if p.acceptKeyword("WHERE") {
+		if query.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("GROUP") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			query.groupBy = append(query.groupBy, expr)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if p.acceptKeyword("HAVING") {
+		if query.having, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("ORDER") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			item := orderItem{expr: expr}
+			if p.acceptKeyword("DESC") {
+				item.desc = true
+			} else {
+				p.acceptKeyword("ASC")
+			}
+			query.orderBy = append(query.orderBy, item)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if p.acceptKeyword("LIMIT") {
+		if query.limit, err = p.parseCount(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("OFFSET") {
+		if query.offset, err = p.parseCount(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek().kind != sqlTokenEOF {
+		return nil, p.errorf("unexpected token")
+	}
+	return query, nil
+}
+
+func (p *queryParser) parseTableRef() (*tableRef, error) {
+	// シートIDは数字のまま指定できる
+	if token := p.peek(); token.kind == sqlTokenNumber {
+		p.pos++
+		return p.parseTableAlias(&tableRef{name: token.text})
+	}
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	return p.parseTableAlias(&tableRef{name: name})
+}
+
+// テーブル名に続く別名を読む
+func (p *queryParser) parseTableAlias(table *tableRef) (*tableRef, error) {
+	var err error
+	if p.acceptKeyword("AS") {
+		if table.alias, err = p.parseIdent(); err != nil {
+			return nil, err
+		}
+	} else if token := p.peek(); token.kind == sqlTokenQuotedIdent || (token.kind == sqlTokenIdent && !sqlKeywords[strings.ToUpper(token.text)]) {
+		table.alias, _ = p.parseIdent()
+	}
+	return table, nil
+}
+
+// LIMIT / OFFSET の件数を読む
+func (p *queryParser) parseCount() (int, error) {
+	token := p.peek()
+	if token.kind != sqlTokenNumber {
+		return 0, p.errorf("expected a number")
+	}
+	n, err := strconv.Atoi(token.text)
+	if err != nil || n < 0 {
+		return 0, p.errorf("expected a non-negative integer")
+	}
+	p.pos++
+	return n, nil
+}
+
+func (p *queryParser) parseExpr() (queryExpr, error) {
+	return p.parseOr()
+}
+
+func (p *queryParser) parseOr() (queryExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (queryExpr, error) {
+	if p.acceptKeyword("NOT") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "NOT", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *queryParser) parseComparison() (queryExpr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	token := p.peek()
+	if token.kind == sqlTokenSymbol {
+		switch token.text {
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			p.pos++
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			op := token.text
+			if op == "<>" {
+				op = "!="
+			}
+			return &binaryExpr{op: op, left: left, right: right}, nil
+		}
+		return left, nil
+	}
+
+	switch {
+	case p.acceptKeyword("IS"):
+		not := p.acceptKeyword("NOT")
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullExpr{operand: left, not: not}, nil
+	case p.isKeyword("NOT", "LIKE", "IN"):
+		not := p.acceptKeyword("NOT")
+		if p.acceptKeyword("IN") {
+			list, err := p.parseList()
+			if err != nil {
+				return nil, err
+			}
+			return &inExpr{operand: left, list: list, not: not}, nil
+		}
+		if err := p.expectKeyword("LIKE"); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		var expr queryExpr = &binaryExpr{op: "LIKE", left: left, right: right}
+		if not {
+			expr = &unaryExpr{op: "NOT", operand: expr}
+		}
+		return expr, nil
+	case p.acceptKeyword("CONTAINS"):
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryExpr{op: "CONTAINS", left: left, right: right}, nil
+	case p.isKeyword("STARTS", "ENDS"):
+		op := strings.ToUpper(p.next().text) + " WITH"
+		if err := p.expectKeyword("WITH"); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryExpr{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+// IN の値リスト
+func (p *queryParser) parseList() ([]queryExpr, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var list []queryExpr
+	for {
+		expr, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, expr)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (p *queryParser) parseAdditive() (queryExpr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isSymbol("+") || p.isSymbol("-") {
+		op := p.next().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseMultiplicative() (queryExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isSymbol("*") || p.isSymbol("/") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseUnary() (queryExpr, error) {
+	if p.acceptSymbol("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryExpr, error) {
+	token := p.peek()
+	switch token.kind {
+	case sqlTokenNumber:
+		p.pos++
+		n, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("query syntax error at position %d: invalid number '%s'", token.pos+1, token.text)
+		}
+		return &literalExpr{value: n}, nil
+	case sqlTokenString:
+		p.pos++
+		return &literalExpr{value: token.text}, nil
+	case sqlTokenSymbol:
+		if p.acceptSymbol("(") {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+			return expr, nil
+		}
+	case sqlTokenIdent:
+		switch strings.ToUpper(token.text) {
+		case "NULL":
+			p.pos++
+			return &literalExpr{value: nil}, nil
+		case "TRUE":
+			p.pos++
+			return &literalExpr{value: true}, nil
+		case "FALSE":
+			p.pos++
+			return &literalExpr{value: false}, nil
+		}
+		// 関数呼び出し
+		name := strings.ToUpper(token.text)
+		if (aggregateFuncs[name] || scalarFuncs[name]) && p.tokens[p.pos+1].kind == sqlTokenSymbol && p.tokens[p.pos+1].text == "(" {
+			p.pos += 2
+			return p.parseFuncArgs(name)
+		}
+	}
+
+	// 列参照（table.column または column）
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, p.errorf("expected a value, column or function")
+	}
+	if p.acceptSymbol(".") {
+		column, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		return &columnExpr{table: name, name: column}, nil
+	}
+	return &columnExpr{name: name}, nil
+}
+
+func (p *queryParser) parseFuncArgs(name string) (queryExpr, error) {
+	fn := &funcExpr{name: name}
+	if name == "COUNT" && p.acceptSymbol("*") {
+		fn.star = true
+	} else {
+		if aggregateFuncs[name] {
+			fn.distinct = p.acceptKeyword("DISTINCT")
+		}
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		fn.arg = arg
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	return fn, nil
+}
+
+// クエリ対象のシートのデータ（1行目をヘッダーとして分離したもの）
+type queryTable struct {
+	name    string
+	columns []string
+	rows    [][]interface{}
+}
+
+// ヘッダー行付きのシートの値から queryTable を作成する
+func newQueryTable(name string, values [][]interface{}) *queryTable {
+	table := &queryTable{name: name}
+	width := 0
+	for _, row := range values {
+		width = max(width, len(row))
+	}
+	for j := 0; j < width; j++ {
+		header := ""
+		if len(values) > 0 && j < len(values[0]) {
+			header = strings.TrimSpace(formatQueryValue(values[0][j]))
+		}
+		if header == "" {
+			header = columnIndexToLetter(int64(j + 1))
+		}
+		table.columns = append(table.columns, header)
+	}
+	if len(values) > 1 {
+		for _, row := range values[1:] {
+			padded := make([]interface{}, width)
+			copy(padded, row)
+			table.rows = append(table.rows, padded)
+		}
+	}
+	return table
+}
+
+// データセットの列（どのシートのどの列か）
+type queryColumn struct {
+	table  string
+	name   string
+	letter string
+}
+
+type queryDataset struct {
+	columns []queryColumn
+	rows    [][]interface{}
+}
+
+// クエリの実行結果
+type queryResult struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+func datasetFromTable(table *queryTable, refName string) *queryDataset {
+	dataset := &queryDataset{rows: table.rows}
+	for j, name := range table.columns {
+		dataset.columns = append(dataset.columns, queryColumn{
+			table:  refName,
+			name:   name,
+			letter: columnIndexToLetter(int64(j + 1)),
+		})
+	}
+	return dataset
+}
+
+// 列参照をデータセットの列番号に解決する
+// 名前が一致する列を優先し、見つからない場合は FROM のシートの列文字として解決する
+func (d *queryDataset) resolve(column *columnExpr) (int, error) {
+	found := -1
+	for i, c := range d.columns {
+		if column.table != "" && !strings.EqualFold(c.table, column.table) {
+			continue
+		}
+		if strings.EqualFold(c.name, column.name) {
+			if found >= 0 {
+				return 0, fmt.Errorf("column '%s' is ambiguous. Qualify it with the sheet name, e.g. sheet.column", column.String())
+			}
+			found = i
+		}
+	}
+	if found >= 0 {
+		return found, nil
+	}
+	if len(d.columns) > 0 {
+		table := column.table
+		if table == "" {
+			table = d.columns[0].table
+		}
+		for i, c := range d.columns {
+			if strings.EqualFold(c.table, table) && c.letter == column.name {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unknown column '%s'", column.String())
+}
+
+// 式の中の列参照を解決する
+func (d *queryDataset) bind(expr queryExpr) error {
+	switch e := expr.(type) {
+	case nil, *literalExpr:
+		return nil
+	case *columnExpr:
+		index, err := d.resolve(e)
+		if err != nil {
+			return err
+		}
+		e.index = index
+		return nil
+	case *unaryExpr:
+		return d.bind(e.operand)
+	case *binaryExpr:
+		if err := d.bind(e.left); err != nil {
+			return err
+		}
+		return d.bind(e.right)
+	case *isNullExpr:
+		return d.bind(e.operand)
+	case *inExpr:
+		if err := d.bind(e.operand); err != nil {
+			return err
+		}
+		for _, item := range e.list {
+			if err := d.bind(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *funcExpr:
+		if e.star {
+			return nil
+		}
+		return d.bind(e.arg)
+	}
+	return fmt.Errorf("unsupported expression: %s", expr.String())
+}
+
+// 式に集計関数が含まれるか
+func containsAggregate(expr queryExpr) bool {
+	switch e := expr.(type) {
+	case *unaryExpr:
+		return containsAggregate(e.operand)
+	case *binaryExpr:
+		return containsAggregate(e.left) || containsAggregate(e.right)
+	case *isNullExpr:
+		return containsAggregate(e.operand)
+	case *inExpr:
+		if containsAggregate(e.operand) {
+			return true
+		}
+		for _, item := range e.list {
+			if containsAggregate(item) {
+				return true
+			}
+		}
+	case *funcExpr:
+		return aggregateFuncs[e.name] || (e.arg != nil && containsAggregate(e.arg))
+	}
+	return false
+}
+
+// クエリを実行する（tables はシート名をキーとする）
+func (q *sheetQuery) execute(tables map[string]*queryTable) (*queryResult, error) {
+	if q.from == nil {
+		return nil, fmt.Errorf("no sheet to query. Specify FROM or sheet_name")
+	}
+	fromTable, ok := tables[q.from.name]
+	if !ok {
+		return nil, fmt.Errorf("sheet not found: '%s'", q.from.name)
+	}
+	dataset := datasetFromTable(fromTable, q.from.refName())
+
+	// JOIN
+	if q.join != nil {
+		joinTable, ok := tables[q.join.table.name]
+		if !ok {
+			return nil, fmt.Errorf("sheet not found: '%s'", q.join.table.name)
+		}
+		var err error
+		dataset, err = joinDatasets(dataset, datasetFromTable(joinTable, q.join.table.refName()), q.join)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// WHERE
+	if q.where != nil {
+		if err := dataset.bind(q.where); err != nil {
+			return nil, err
+		}
+		if containsAggregate(q.where) {
+			return nil, fmt.Errorf("aggregate functions are not allowed in WHERE. Use HAVING instead")
+		}
+		var filtered [][]interface{}
+		for _, row := range dataset.rows {
+			value, err := evalQueryExpr(q.where, row, nil)
+			if err != nil {
+				return nil, err
+			}
+			if isTruthy(value) {
+				filtered = append(filtered, row)
+			}
+		}
+		dataset.rows = filtered
+	}
+
+	// SELECT 句の列
+	items := q.items
+	if q.star {
+		if len(q.groupBy) > 0 {
+			return nil, fmt.Errorf("SELECT * cannot be used with GROUP BY")
+		}
+		// 同名の列がある場合はシート名で修飾する
+		counts := make(map[string]int)
+		for _, c := range dataset.columns {
+			counts[strings.ToLower(c.name)]++
+		}
+		items = nil
+		for i, c := range dataset.columns {
+			alias := c.name
+			if counts[strings.ToLower(c.name)] > 1 {
+				alias = c.table + "." + c.name
+			}
+			items = append(items, selectItem{expr: &columnExpr{table: c.table, name: c.name, index: i}, alias: alias})
+		}
+	} else {
+		for _, item := range items {
+			if err := dataset.bind(item.expr); err != nil {
+				return nil, err
+			}
+		}
+	}
+	result := &queryResult{}
+	for _, item := range items {
+		name := item.alias
+		if name == "" {
+			name = item.expr.String()
+		}
+		result.columns = append(result.columns, name)
+	}
+
+	// ORDER BY（出力列の名前・別名・列番号で指定された場合は出力列で並べる）
+	orderOutput := make([]int, len(q.orderBy))
+	for i, item := range q.orderBy {
+		orderOutput[i] = -1
+		switch e := item.expr.(type) {
+		case *literalExpr:
+			if n, ok := e.value.(float64); ok && n == math.Trunc(n) && n >= 1 && int(n) <= len(items) {
+				orderOutput[i] = int(n) - 1
+				continue
+			}
+		case *columnExpr:
+			if e.table == "" {
+				for j, name := range result.columns {
+					if strings.EqualFold(name, e.name) {
+						orderOutput[i] = j
+						break
+					}
+				}
+			}
+		}
+		if orderOutput[i] < 0 {
+			if err := dataset.bind(item.expr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// GROUP BY・集計関数がある場合はグループごとに1行を出力する
+	grouped := len(q.groupBy) > 0 || q.having != nil
+	for _, item := range items {
+		grouped = grouped || containsAggregate(item.expr)
+	}
+	for _, item := range q.orderBy {
+		grouped = grouped || containsAggregate(item.expr)
+	}
+
+	type outputRow struct {
+		values []interface{}
+		keys   []interface{}
+	}
+	var outputs []outputRow
+	emit := func(row []interface{}, group [][]interface{}) error {
+		out := outputRow{values: make([]interface{}, len(items)), keys: make([]interface{}, len(q.orderBy))}
+		for i, item := range items {
+			value, err := evalQueryExpr(item.expr, row, group)
+			if err != nil {
+				return err
+			}
+			out.values[i] = value
+		}
+		for i, item := range q.orderBy {
+			if orderOutput[i] >= 0 {
+				out.keys[i] = out.values[orderOutput[i]]
+				continue
+			}
+			value, err := evalQueryExpr(item.expr, row, group)
+			if err != nil {
+				return err
+			}
+			out.keys[i] = value
+		}
+		outputs = append(outputs, out)
+		return nil
+	}
+
+	if grouped {
+		for _, expr := range q.groupBy {
+			if err := dataset.bind(expr); err != nil {
+				return nil, err
+			}
+			if containsAggregate(expr) {
+				return nil, fmt.Errorf("aggregate functions are not allowed in GROUP BY")
+			}
+		}
+		if q.having != nil {
+			if err := dataset.bind(q.having); err != nil {
+				return nil, err
+			}
+		}
+
+		// 出現順にグループを作成
+		var (
+			keys   []string
+			groups = make(map[string][][]interface{})
+		)
+		for _, row := range dataset.rows {
+			parts := make([]string, len(q.groupBy))
+			for i, expr := range q.groupBy {
+				value, err := evalQueryExpr(expr, row, nil)
+				if err != nil {
+					return nil, err
+				}
+				parts[i] = formatQueryValue(value)
+			}
+			key := strings.Join(parts, "\x00")
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], row)
+		}
+		// GROUP BY なしの集計は全体を1グループとする（0行でも1行を出力）
+		if len(q.groupBy) == 0 && len(keys) == 0 {
+			keys = append(keys, "")
+			groups[""] = [][]interface{}{}
+		}
+
+		for _, key := range keys {
+			group := groups[key]
+			var first []interface{}
+			if len(group) > 0 {
+				first = group[0]
+			} else {
+				first = make([]interface{}, len(dataset.columns))
+			}
+			if q.having != nil {
+				value, err := evalQueryExpr(q.having, first, group)
+				if err != nil {
+					return nil, err
+				}
+				if !isTruthy(value) {
+					continue
+				}
+			}
+			if err := emit(first, group); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for _, row := range dataset.rows {
+			if err := emit(row, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 並べ替え（NULL は常に最後）
+	if len(q.orderBy) > 0 {
+		sort.SliceStable(outputs, func(a, b int) bool {
+			for i, item := range q.orderBy {
+				x, y := outputs[a].keys[i], outputs[b].keys[i]
+				xNull, yNull := isNullValue(x), isNullValue(y)
+				if xNull || yNull {
+					if xNull != yNull {
+						return yNull
+					}
+					continue
+				}
+				c := compareQueryValues(x, y)
+				if c == 0 {
+					continue
+				}
+				if item.desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+
+	// OFFSET・LIMIT
+	start := min(q.offset, len(outputs))
+	end := len(outputs)
+	if q.limit >= 0 && start+q.limit < end {
+		end = start + q.limit
+	}
+	for _, out := range outputs[start:end] {
+		result.rows = append(result.rows, out.values)
+	}
+	return result, nil
+}
+
+// 2つのデータセットを結合キーで結合する
+func joinDatasets(left, right *queryDataset, join *joinClause) (*queryDataset, error) {
+	combined := &queryDataset{columns: append(append([]queryColumn{}, left.columns...), right.columns...)}
+	leftWidth := len(left.columns)
+
+	// ON の両辺がそれぞれどちらのシートの列を参照しているか確認する
+	leftKey, rightKey := join.leftKey, join.rightKey
+	if err := combined.bind(leftKey); err != nil {
+		return nil, err
+	}
+	if err := combined.bind(rightKey); err != nil {
+		return nil, err
+	}
+	leftSide, err := joinKeySide(leftKey, leftWidth)
+	if err != nil {
+		return nil, err
+	}
+	rightSide, err := joinKeySide(rightKey, leftWidth)
+	if err != nil {
+		return nil, err
+	}
+	if leftSide == rightSide {
+		return nil, fmt.Errorf("JOIN condition must compare a column of each sheet")
+	}
+	if leftSide == 1 {
+		leftKey, rightKey = rightKey, leftKey
+	}
+
+	// 右側のシートの行を結合キーで索引付けする
+	index := make(map[string][][]interface{})
+	for _, row := range right.rows {
+		padded := make([]interface{}, len(combined.columns))
+		copy(padded[leftWidth:], row)
+		value, err := evalQueryExpr(rightKey, padded, nil)
+		if err != nil {
+			return nil, err
+		}
+		if isNullValue(value) {
+			continue
+		}
+		key := joinKey(value)
+		index[key] = append(index[key], row)
+	}
+
+	for _, row := range left.rows {
+		padded := make([]interface{}, len(combined.columns))
+		copy(padded, row)
+		value, err := evalQueryExpr(leftKey, padded, nil)
+		if err != nil {
+			return nil, err
+		}
+		matches := index[joinKey(value)]
+		if isNullValue(value) {
+			matches = nil
+		}
+		for _, match := range matches {
+			joined := make([]interface{}, 0, len(combined.columns))
+			joined = append(joined, row...)
+			joined = append(joined, match...)
+			combined.rows = append(combined.rows, joined)
+		}
+		if len(matches) == 0 && join.left {
+			combined.rows = append(combined.rows, padded)
+		}
+	}
+	return combined, nil
+}
+
+// 結合キーの式がどちらのシートの列を参照しているか（0: 左, 1: 右）
+func joinKeySide(expr queryExpr, leftWidth int) (int, error) {
+	side := -1
+	var walk func(queryExpr)
+	walk = func(expr queryExpr) {
+		switch e := expr.(type) {
+		case *columnExpr:
+			s := 0
+			if e.index >= leftWidth {
+				s = 1
+			}
+			if side == -1 {
+				side = s
+			} else if side != s {
+				side = 2
+			}
+		case *unaryExpr:
+			walk(e.operand)
+		case *binaryExpr:
+			walk(e.left)
+			walk(e.right)
+		case *funcExpr:
+			if e.arg != nil {
+				walk(e.arg)
+			}
+		}
+	}
+	walk(expr)
+	if side < 0 || side > 1 {
+		return 0, fmt.Errorf("each side of the JOIN condition must reference columns of exactly one sheet: %s", expr.String())
+	}
+	return side, nil
+}
+
+// 結合キーの値を比較用の文字列にする（数値は表記を揃える）
+func joinKey(value interface{}) string {
+	if n, ok := toQueryNumber(value); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return formatQueryValue(value)
+}
+
+// 式を評価する（group は集計対象の行、集計しない場合は nil）
+func evalQueryExpr(expr queryExpr, row []interface{}, group [][]interface{}) (interface{}, error) {
+	switch e := expr.(type) {
+	case *literalExpr:
+		return e.value, nil
+	case *columnExpr:
+		return row[e.index], nil
+	case *unaryExpr:
+		value, err := evalQueryExpr(e.operand, row, group)
+		if err != nil {
+			return nil, err
+		}
+		if e.op == "NOT" {
+			return !isTruthy(value), nil
+		}
+		n, ok := toQueryNumber(value)
+		if !ok {
+			return nil, nil
+		}
+		return -n, nil
+	case *isNullExpr:
+		value, err := evalQueryExpr(e.operand, row, group)
+		if err != nil {
+			return nil, err
+		}
+		return isNullValue(value) != e.not, nil
+	case *inExpr:
+		value, err := evalQueryExpr(e.operand, row, group)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, item := range e.list {
+			candidate, err := evalQueryExpr(item, row, group)
+			if err != nil {
+				return nil, err
+			}
+			if !isNullValue(value) && !isNullValue(candidate) && compareQueryValues(value, candidate) == 0 {
+				found = true
+				break
+			}
+		}
+		return found != e.not, nil
+	case *binaryExpr:
+		return evalBinaryExpr(e, row, group)
+	case *funcExpr:
+		if aggregateFuncs[e.name] {
+			if group == nil {
+				return nil, fmt.Errorf("aggregate function %s cannot be used here", e.name)
+			}
+			return evalAggregate(e, group)
+		}
+		value, err := evalQueryExpr(e.arg, row, group)
+		if err != nil {
+			return nil, err
+		}
+		if isNullValue(value) {
+			return nil, nil
+		}
+		switch e.name {
+		case "LOWER":
+			return strings.ToLower(formatQueryValue(value)), nil
+		case "UPPER":
+			return strings.ToUpper(formatQueryValue(value)), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported expression: %s", expr.String())
+}
+
+func evalBinaryExpr(e *binaryExpr, row []interface{}, group [][]interface{}) (interface{}, error) {
+	left, err := evalQueryExpr(e.left, row, group)
+	if err != nil {
+		return nil, err
+	}
+	// AND / OR は短絡評価する
+	switch e.op {
+	case "AND":
+		if !isTruthy(left) {
+			return false, nil
+		}
+		right, err := evalQueryExpr(e.right, row, group)
+		if err != nil {
+			return nil, err
+		}
+		return isTruthy(right), nil
+	case "OR":
+		if isTruthy(left) {
+			return true, nil
+		}
+		right, err := evalQueryExpr(e.right, row, group)
+		if err != nil {
+			return nil, err
+		}
+		return isTruthy(right), nil
+	}
+
+	right, err := evalQueryExpr(e.right, row, group)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "+", "-", "*", "/":
+		x, okX := toQueryNumber(left)
+		y, okY := toQueryNumber(right)
+		if !okX || !okY {
+			return nil, nil
+		}
+		switch e.op {
+		case "+":
+			return x + y, nil
+		case "-":
+			return x - y, nil
+		case "*":
+			return x * y, nil
+		default:
+			if y == 0 {
+				return nil, nil
+			}
+			return x / y, nil
+		}
+	}
+
+	// NULL との比較は常に偽
+	if isNullValue(left) || isNullValue(right) {
+		return false, nil
+	}
+	switch e.op {
+	case "=":
+		return compareQueryValues(left, right) == 0, nil
+	case "!=":
+		return compareQueryValues(left, right) != 0, nil
+	case "<":
+		return compareQueryValues(left, right) < 0, nil
+	case "<=":
+		return compareQueryValues(left, right) <= 0, nil
+	case ">":
+		return compareQueryValues(left, right) > 0, nil
+	case ">=":
+		return compareQueryValues(left, right) >= 0, nil
+	}
+
+	// 文字列の比較は大文字・小文字を区別しない
+	text := strings.ToLower(formatQueryValue(left))
+	pattern := strings.ToLower(formatQueryValue(right))
+	switch e.op {
+	case "LIKE":
+		return matchLike(text, pattern), nil
+	case "CONTAINS":
+		return strings.Contains(text, pattern), nil
+	case "STARTS WITH":
+		return strings.HasPrefix(text, pattern), nil
+	case "ENDS WITH":
+		return strings.HasSuffix(text, pattern), nil
+	}
+	return nil, fmt.Errorf("unsupported operator: %s", e.op)
+}
+
+func evalAggregate(e *funcExpr, group [][]interface{}) (interface{}, error) {
+	if e.star {
+		return float64(len(group)), nil
+	}
+
+	var values []interface{}
+	seen := make(map[string]bool)
+	for _, row := range group {
+		value, err := evalQueryExpr(e.arg, row, nil)
+		if err != nil {
+			return nil, err
+		}
+		if isNullValue(value) {
+			continue
+		}
+		if e.distinct {
+			key := joinKey(value)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		values = append(values, value)
+	}
+
+	switch e.name {
+	case "COUNT":
+		return float64(len(values)), nil
+	case "SUM", "AVG":
+		var sum float64
+		count := 0
+		for _, value := range values {
+			if n, ok := toQueryNumber(value); ok {
+				sum += n
+				count++
+			}
+		}
+		if e.name == "SUM" {
+			return sum, nil
+		}
+		if count == 0 {
+			return nil, nil
+		}
+		return sum / float64(count), nil
+	case "MIN", "MAX":
+		var result interface{}
+		for _, value := range values {
+			if result == nil {
+				result = value
+				continue
+			}
+			c := compareQueryValues(value, result)
+			if (e.name == "MIN" && c < 0) || (e.name == "MAX" && c > 0) {
+				result = value
+			}
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unsupported aggregate function: %s", e.name)
+}
+
+// LIKE のパターン（% は任意の文字列、_ は任意の1文字）に一致するか
+func matchLike(text, pattern string) bool {
+	t, p := []rune(text), []rune(pattern)
+	// 動的計画法で照合する
+	matches := make([]bool, len(t)+1)
+	matches[0] = true
+	for _, pc := range p {
+		next := make([]bool, len(t)+1)
+		if pc == '%' {
+			next[0] = matches[0]
+			for i := 1; i <= len(t); i++ {
+				next[i] = next[i-1] || matches[i]
+			}
+		} else {
+			for i := 1; i <= len(t); i++ {
+				next[i] = matches[i-1] && (pc == '_' || pc == t[i-1])
+			}
+		}
+		matches = next
+	}
+	return matches[len(t)]
+}
+
+// 値が NULL（空のセルを含む）かどうか
+func isNullValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	s, ok := value.(string)
+	return ok && s == ""
+}
+
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+// 値を数値として解釈する
+func toQueryNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// 2つの値を比較する（数値・日付・真偽値はそれぞれの順序、それ以外は文字列として比較）
+func compareQueryValues(x, y interface{}) int {
+	if a, ok := toQueryNumber(x); ok {
+		if b, ok := toQueryNumber(y); ok {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+			return 0
+		}
+	}
+	if a, ok := x.(bool); ok {
+		if b, ok := y.(bool); ok {
+			switch {
+			case a == b:
+				return 0
+			case !a:
+				return -1
+			}
+			return 1
+		}
+	}
+	a, b := formatQueryValue(x), formatQueryValue(y)
+	if da, ok := parseDate(a); ok {
+		if db, ok := parseDate(b); ok {
+			return da.Compare(db)
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// 値を表示用の文字列にする
+func formatQueryValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// クエリ結果を Markdown の表に変換する
+func formatQueryResult(result *queryResult) string {
+	var builder strings.Builder
+	builder.WriteString("| " + strings.Join(result.columns, " | ") + " |\n")
+	borders := make([]string, len(result.columns))
+	for i := range borders {
+		borders[i] = "---"
+	}
+	builder.WriteString("|" + strings.Join(borders, "|") + "|\n")
+	for _, row := range result.rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = formatQueryValue(value)
+		}
+		builder.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func queryTestTables() map[string]*queryTable {
+	return map[string]*queryTable{
+		"Sales": newQueryTable("Sales", [][]interface{}{
+			{"Region", "Product", "Revenue", "Customer ID", "Date"},
+			{"East", "Apple", float64(100), float64(1), "2024-01-05"},
+			{"West", "Apple", float64(250), float64(2), "2024-03-01"},
+			{"East", "Banana", float64(50), float64(2), "2023-12-31"},
+			{"North", "Cherry", nil, float64(3), "2024-02-10"},
+			{"West", "Banana", float64(25.5), float64(9), ""},
+		}),
+		"Customers": newQueryTable("Customers", [][]interface{}{
+			{"ID", "Name"},
+			{float64(1), "Alice"},
+			{"2", "Bob's Shop"},
+			{float64(3), "Carol"},
+		}),
+	}
+}
+
+func runTestQuery(t *testing.T, input string) *queryResult {
+	t.Helper()
+	query, err := parseSheetQuery(input)
+	if err != nil {
+		t.Fatalf("parseSheetQuery(%q) error: %v", input, err)
+	}
+	if query.from == nil {
+		query.from = &tableRef{name: "Sales"}
+	}
+	result, err := query.execute(queryTestTables())
+	if err != nil {
+		t.Fatalf("execute(%q) error: %v", input, err)
+	}
+	return result
+}
+
+// 結果の各行を "|" 区切りの文字列にする
+func resultLines(result *queryResult) []string {
+	lines := []string{strings.Join(result.columns, "|")}
+	for _, row := range result.rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = formatQueryValue(value)
+		}
+		lines = append(lines, strings.Join(cells, "|"))
+	}
+	return lines
+}
+
+func TestSheetQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "select star with limit",
+			query: "SELECT * LIMIT 1",
+			want:  []string{"Region|Product|Revenue|Customer ID|Date", "East|Apple|100|1|2024-01-05"},
+		},
+		{
+			name:  "where with comparison and lowercase keywords",
+			query: "select Product, Revenue from Sales where Revenue >= 100 order by Revenue desc",
+			want:  []string{"Product|Revenue", "Apple|250", "Apple|100"},
+		},
+		{
+			name:  "column letters and quoted names",
+			query: "SELECT A, `Customer ID` WHERE B = 'Cherry'",
+			want:  []string{"A|Customer ID", "North|3"},
+		},
+		{
+			name:  "group by with aggregates and alias ordering",
+			query: "SELECT Region, SUM(Revenue) AS Total, COUNT(*) FROM Sales GROUP BY Region ORDER BY Total DESC",
+			want:  []string{"Region|Total|COUNT(*)", "West|275.5|2", "East|150|2", "North|0|1"},
+		},
+		{
+			name:  "aggregates without group by",
+			query: "SELECT COUNT(Revenue), AVG(Revenue), MIN(Product), MAX(Date), COUNT(DISTINCT Region)",
+			want:  []string{"COUNT(Revenue)|AVG(Revenue)|MIN(Product)|MAX(Date)|COUNT(DISTINCT Region)", "4|106.375|Apple|2024-03-01|3"},
+		},
+		{
+			name:  "aggregate over no rows",
+			query: "SELECT COUNT(*), SUM(Revenue) WHERE Region = 'South'",
+			want:  []string{"COUNT(*)|SUM(Revenue)", "0|0"},
+		},
+		{
+			name:  "having",
+			query: "SELECT Region FROM Sales GROUP BY Region HAVING COUNT(*) > 1 ORDER BY Region",
+			want:  []string{"Region", "East", "West"},
+		},
+		{
+			name:  "like contains and null checks",
+			query: "SELECT Product WHERE (Product LIKE 'b%' OR Product CONTAINS 'ERR') AND Revenue IS NULL",
+			want:  []string{"Product", "Cherry"},
+		},
+		{
+			name:  "not in and arithmetic",
+			query: "SELECT Product, Revenue * 2 AS Double WHERE Region NOT IN ('East', 'North') ORDER BY 2",
+			want:  []string{"Product|Double", "Banana|51", "Apple|500"},
+		},
+		{
+			name:  "dates compare as dates and nulls sort last",
+			query: "SELECT Date WHERE Region = 'West' ORDER BY Date",
+			want:  []string{"Date", "2024-03-01", ""},
+		},
+		{
+			name:  "inner join on key",
+			query: "SELECT s.Product, c.Name FROM Sales s JOIN Customers c ON s.`Customer ID` = c.ID ORDER BY c.Name, s.Product",
+			want:  []string{"s.Product|c.Name", "Apple|Alice", "Apple|Bob's Shop", "Banana|Bob's Shop", "Cherry|Carol"},
+		},
+		{
+			name:  "left join keeps unmatched rows",
+			query: "SELECT Product, Name FROM Sales LEFT JOIN Customers ON Customers.ID = Sales.`Customer ID` WHERE Name IS NULL",
+			want:  []string{"Product|Name", "Banana|"},
+		},
+		{
+			name:  "join with group by",
+			query: "SELECT Name, SUM(Revenue) AS Total FROM Sales JOIN Customers ON `Customer ID` = ID WHERE Name = 'Bob''s Shop' GROUP BY Name",
+			want:  []string{"Name|Total", "Bob's Shop|300"},
+		},
+		{
+			name:  "offset",
+			query: "SELECT Product ORDER BY Revenue LIMIT 2 OFFSET 1",
+			want:  []string{"Product", "Banana", "Apple"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resultLines(runTestQuery(t, tt.query))
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("result =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestSheetQueryErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"missing select", "Product FROM Sales", "expected SELECT"},
+		{"unterminated string", "SELECT * WHERE Region = 'East", "unterminated string"},
+		{"trailing tokens", "SELECT * FROM Sales Sales2 Sales3", "unexpected token"},
+		{"unknown column", "SELECT Price", "unknown column 'Price'"},
+		{"unknown sheet", "SELECT * FROM Missing", "sheet not found: 'Missing'"},
+		{"aggregate in where", "SELECT Product WHERE SUM(Revenue) > 1", "not allowed in WHERE"},
+		{"star with group by", "SELECT * GROUP BY Region", "cannot be used with GROUP BY"},
+		{"join on one sheet", "SELECT * FROM Sales JOIN Customers ON Sales.Region = Sales.Product", "must compare a column of each sheet"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := parseSheetQuery(tt.query)
+			if err == nil {
+				if query.from == nil {
+					query.from = &tableRef{name: "Sales"}
+				}
+				_, err = query.execute(queryTestTables())
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchLike(t *testing.T) {
+	tests := []struct {
+		text, pattern string
+		want          bool
+	}{
+		{"banana", "b%", true},
+		{"banana", "%nan%", true},
+		{"banana", "b_n_n_", true},
+		{"banana", "b_n", false},
+		{"", "%", true},
+		{"東京都", "東京%", true},
+	}
+	for _, tt := range tests {
+		if got := matchLike(tt.text, tt.pattern); got != tt.want {
+			t.Errorf("matchLike(%q, %q) = %v, want %v", tt.text, tt.pattern, got, tt.want)
+		}
+	}
+}